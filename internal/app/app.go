@@ -3,19 +3,21 @@
 package app
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"runtime"
-	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/pranshuparmar/witr/internal/output"
 	"github.com/pranshuparmar/witr/internal/pipeline"
 	procpkg "github.com/pranshuparmar/witr/internal/proc"
-	"github.com/pranshuparmar/witr/internal/source"
+	"github.com/pranshuparmar/witr/internal/procfs"
 	"github.com/pranshuparmar/witr/internal/target"
 	"github.com/pranshuparmar/witr/internal/tui"
 	"github.com/pranshuparmar/witr/pkg/model"
@@ -38,8 +40,9 @@ var rootCmd = &cobra.Command{
 		DisableDefaultCmd: false,
 		DisableNoDescFlag: false,
 	},
-	Example: _genExamples(),
-	RunE:    runApp,
+	Example:           _genExamples(),
+	PersistentPreRunE: setup,
+	RunE:              runApp,
 }
 
 func _genExamples() string {
@@ -54,12 +57,22 @@ func _genExamples() string {
   # Find the process listening on a specific port
   witr --port 5432
 
+  # List every bound port in a range, with its owner and source
+  witr --port 8000-8100
+
+  # Find the process talking to a remote endpoint
+  witr --connection 10.2.3.4:5432
+
   # Find the process holding a file open
   witr --file /var/lib/dpkg/lock
 
   # Inspect a container by name
   witr --container redis
 
+  # Find the process serving a unix socket, or the processes connected to it
+  witr --socket /var/run/docker.sock
+  witr --socket /var/run/docker.sock --clients
+
   # Inspect a process by name with exact matching (no fuzzy search)
   witr bun --exact
 
@@ -72,9 +85,19 @@ func _genExamples() string {
   # Display only environment variables of the process
   witr node --env
 
+  # Match by regular expression, narrowed by owner and what started the process
+  witr --regex 'celery.*worker' --user app --source supervisor
+
+  # Analyze every python process and group them by what started them
+  witr python --all
+  witr python --summary
+
   # Short, single-line output (useful for scripts)
   witr sshd --short
 
+  # Exit with code 1 only for high-severity warnings (CI gates)
+  witr --port 443 --fail-on=high
+
   # Disable colorized output (CI or piping)
   witr redis --no-color
 
@@ -87,6 +110,9 @@ func _genExamples() string {
   # Combine flags: inspect port, show environment variables, output JSON
   witr --port 8080 --env --json
 
+  # Keep watching a port and print what changes (owning PID, children, sockets, restarts)
+  witr --port 8080 --watch
+
   # Multiple inputs
   witr nginx node
   witr --port 8080 --port 3000
@@ -94,6 +120,16 @@ func _genExamples() string {
 
   # Mixed inputs
   witr nginx --pid 1234 --port 8080
+
+  # Inspect the host from a debug container with the host's / mounted at /host
+  witr --proc-root /host nginx
+
+  # Print the effective configuration (default flags, thresholds, tables)
+  witr config show
+
+  # Capture the system's state now, analyze it later on another machine
+  witr snapshot capture -o box.tar.zst
+  witr --snapshot box.tar.zst --port 8080
 `
 }
 
@@ -142,19 +178,78 @@ func init() {
 	rootCmd.SetErr(output.NewSafeTerminalWriter(os.Stderr))
 
 	rootCmd.Flags().StringSliceP("pid", "p", nil, "pid(s) to look up (repeatable)")
-	rootCmd.Flags().StringSliceP("port", "o", nil, "port(s) to look up (repeatable)")
+	rootCmd.Flags().StringSliceP("port", "o", nil, "port(s), ranges (8000-8100), udp/53 or service names to look up (repeatable)")
 	rootCmd.Flags().StringSliceP("file", "f", nil, "file(s) held open by a process (repeatable)")
 	rootCmd.Flags().StringSliceP("container", "c", nil, "container(s) to look up (repeatable)")
+	rootCmd.Flags().StringSlice("connection", nil, "remote host:port(s) to find the processes connected to (repeatable)")
+	rootCmd.Flags().StringSlice("socket", nil, "unix socket path(s) to look up the listener of (repeatable)")
+	rootCmd.Flags().Bool("clients", false, "with --socket, look up the processes connected to the socket instead of its listener")
+	rootCmd.Flags().StringArray("regex", nil, "processes whose name or command line matches this regular expression (repeatable)")
+	rootCmd.Flags().String("user", "", "keep only matches owned by this user")
+	rootCmd.Flags().String("source", "", "keep only matches started by this source: systemd, cron, shell, supervisor, ...")
+	rootCmd.Flags().String("in-container", "", "keep only matches running in this container")
+	rootCmd.Flags().String("cwd", "", "keep only matches whose working directory is this directory or under it")
+	rootCmd.Flags().StringArray("with-env", nil, "keep only matches whose environment has KEY=VALUE, or just KEY (repeatable)")
+	rootCmd.Flags().String("netns", "", "keep only matches in this network namespace: its inode, a /run/netns path or an ip netns name (Linux)")
 	rootCmd.Flags().BoolP("short", "s", false, "show only ancestry")
 	rootCmd.Flags().BoolP("tree", "t", false, "show only ancestry as a tree")
 	rootCmd.Flags().Bool("json", false, "show result as JSON")
 	rootCmd.Flags().Bool("warnings", false, "show only warnings")
+	rootCmd.Flags().String("fail-on", "info", "exit with code 1 for warnings of this severity or above: info, low, medium, high, or none")
+	rootCmd.Flags().Bool("show-suppressed", false, "also list the warnings suppress rules and the baseline hide")
 	rootCmd.Flags().Bool("no-color", false, "disable colorized output")
 	rootCmd.Flags().Bool("env", false, "show environment variables for the process")
 	rootCmd.Flags().Bool("verbose", false, "show extended process information")
 	rootCmd.Flags().BoolP("exact", "x", false, "use exact name matching (no substring search)")
+	rootCmd.Flags().BoolP("all", "a", false, "analyze every process a target matches instead of listing them")
+	rootCmd.Flags().Bool("summary", false, "with --all, show only how many matches each source started (implies --all)")
 	rootCmd.Flags().BoolP("interactive", "i", false, "interactive mode (TUI)")
+	rootCmd.Flags().Duration("timeout", 0, "stop slow lookups (service managers, container runtimes) after this long and show what was found (0: no limit)")
+	rootCmd.Flags().Duration("watch", 0, "re-run the lookup every interval and print only what changed (--watch=5s; default 2s)")
+	rootCmd.Flags().Lookup("watch").NoOptDefVal = defaultWatchInterval.String()
+	rootCmd.Flags().String("snapshot", "", "analyze a snapshot file from `witr snapshot capture` instead of the live system")
+	rootCmd.PersistentFlags().String("config", "", "read configuration from this file instead of the system and user config files (env: WITR_CONFIG)")
+	rootCmd.PersistentFlags().String("baseline", "", "file of accepted warnings that `witr baseline save` writes (default $XDG_STATE_HOME/witr/baseline.json)")
+	rootCmd.PersistentFlags().String("proc-root", "", "read /proc, /sys and /etc from under this directory, e.g. /host (env: WITR_PROC_ROOT)")
+
+}
+
+// setup runs before any command: it loads the configuration, whose defaults
+// may set --proc-root, then selects the state to analyze.
+func setup(cmd *cobra.Command, args []string) error {
+	if err := loadConfig(cmd); err != nil {
+		return err
+	}
+	return selectSource(cmd, args)
+}
 
+// selectSource points every /proc reader at the state to analyze before any
+// command runs: a snapshot (--snapshot), a mounted host filesystem
+// (--proc-root, falling back to $WITR_PROC_ROOT), or by default the live
+// system.
+func selectSource(cmd *cobra.Command, _ []string) error {
+	snap, _ := cmd.Flags().GetString("snapshot")
+	root, _ := cmd.Flags().GetString("proc-root")
+	if snap != "" {
+		if root != "" {
+			return withExitCode(ExitInvalidInput, fmt.Errorf("--snapshot and --proc-root cannot be combined"))
+		}
+		return useSnapshot(cmd, snap)
+	}
+
+	if root == "" {
+		root = os.Getenv("WITR_PROC_ROOT")
+	}
+	if root == "" {
+		return nil
+	}
+	if runtime.GOOS != "linux" {
+		return withExitCode(ExitInvalidInput, fmt.Errorf("--proc-root is only supported on Linux"))
+	}
+	if err := procfs.SetRoot(root); err != nil {
+		return withExitCode(ExitInvalidInput, err)
+	}
+	return nil
 }
 
 // appFlags holds all parsed CLI flags for convenience.
@@ -167,6 +262,21 @@ type appFlags struct {
 	verbose bool
 	exact   bool
 	env     bool
+	// failOn is the least severe warning that exits with ExitWarnings (any
+	// warning when empty), or failOnNone.
+	failOn         model.Severity
+	showSuppressed bool
+	// timeout bounds the whole run, or each refresh with --watch; 0 is no
+	// limit.
+	timeout time.Duration
+	// all analyzes every process a target matches; summary shows only their
+	// grouping by source.
+	all     bool
+	summary bool
+	// clients resolves --socket targets to the socket's clients.
+	clients bool
+	// sel narrows what every target but a container matches.
+	sel target.Selector
 }
 
 func runApp(cmd *cobra.Command, args []string) error {
@@ -180,8 +290,13 @@ func runApp(cmd *cobra.Command, args []string) error {
 	portFlags, _ := cmd.Flags().GetStringSlice("port")
 	fileFlags, _ := cmd.Flags().GetStringSlice("file")
 	containerFlags, _ := cmd.Flags().GetStringSlice("container")
+	regexFlags, _ := cmd.Flags().GetStringArray("regex")
+	socketFlags, _ := cmd.Flags().GetStringSlice("socket")
+	connectionFlags, _ := cmd.Flags().GetStringSlice("connection")
 
-	if !envFlag && len(pidFlags) == 0 && len(portFlags) == 0 && len(fileFlags) == 0 && len(containerFlags) == 0 && len(args) == 0 {
+	watch, _ := cmd.Flags().GetDuration("watch")
+
+	if !envFlag && watch == 0 && len(pidFlags) == 0 && len(portFlags) == 0 && len(fileFlags) == 0 && len(containerFlags) == 0 && len(regexFlags) == 0 && len(socketFlags) == 0 && len(connectionFlags) == 0 && len(args) == 0 {
 		return runInteractive()
 	}
 
@@ -194,22 +309,95 @@ func runApp(cmd *cobra.Command, args []string) error {
 		warn:    boolFlag(cmd, "warnings"),
 		noColor: boolFlag(cmd, "no-color"),
 		verbose: boolFlag(cmd, "verbose"),
+
+		showSuppressed: boolFlag(cmd, "show-suppressed"),
+		summary:        boolFlag(cmd, "summary"),
+		clients:        boolFlag(cmd, "clients"),
+	}
+	if flags.clients && len(socketFlags) == 0 {
+		return withExitCode(ExitInvalidInput, fmt.Errorf("--clients needs a --socket target"))
+	}
+	flags.all = boolFlag(cmd, "all") || flags.summary
+	if flags.summary {
+		for _, name := range []string{"env", "short", "tree", "warnings"} {
+			if boolFlag(cmd, name) {
+				return withExitCode(ExitInvalidInput, fmt.Errorf("--summary cannot be combined with --%s", name))
+			}
+		}
+	}
+	flags.timeout, _ = cmd.Flags().GetDuration("timeout")
+	if flags.timeout < 0 {
+		return withExitCode(ExitInvalidInput, fmt.Errorf("invalid --timeout %s", flags.timeout))
+	}
+	failOn, _ := cmd.Flags().GetString("fail-on")
+	if failOn == string(failOnNone) {
+		flags.failOn = failOnNone
+	} else {
+		sev, err := model.ParseSeverity(failOn)
+		if err != nil {
+			return withExitCode(ExitInvalidInput, fmt.Errorf("invalid --fail-on: %w", err))
+		}
+		flags.failOn = sev
+	}
+
+	sel, err := parseSelector(cmd)
+	if err != nil {
+		return err
+	}
+	flags.sel = sel
+	for _, pattern := range regexFlags {
+		if _, err := target.CompileRegex(pattern); err != nil {
+			return withExitCode(ExitInvalidInput, err)
+		}
+	}
+
+	if err := loadSuppressor(cmd); err != nil {
+		return err
 	}
 
 	// Collect all targets preserving command-line order
 	targets := collectTargetsInOrder(os.Args[1:], args, flagTakesValue(cmd))
 
 	if len(targets) == 0 {
-		return withExitCode(ExitInvalidInput, fmt.Errorf("must specify --pid, --port, --file, --container, or a process name"))
+		return withExitCode(ExitInvalidInput, fmt.Errorf("must specify --pid, --port, --file, --container, --socket, --connection, --regex, or a process name"))
 	}
 
+	// Ctrl-C and SIGTERM cancel the lookups in flight, and what was found so
+	// far is still shown. After the first signal the default handling is
+	// restored, so a second one kills witr outright.
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	context.AfterFunc(ctx, stop)
+
 	outw := cmd.OutOrStdout()
+	if watch != 0 {
+		if err := checkWatchFlags(cmd, flags, watch); err != nil {
+			return err
+		}
+		if code := runWatch(ctx, outw, targets, flags, watch); code != ExitOK {
+			cmd.SilenceErrors = true
+			return withExitCode(code, fmt.Errorf("completed with exit code %d", code))
+		}
+		return nil
+	}
+
+	if flags.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, flags.timeout)
+		defer cancel()
+	}
+	// All targets share one read of the process table.
+	ctx = procpkg.WithIndex(ctx, procpkg.NewIndex())
+
 	outp := output.NewPrinter(outw)
 	multiMode := len(targets) > 1
 	colorEnabled := useColor(flags, outw)
 
-	// For JSON multi-output, collect all JSON strings and wrap in array
-	var jsonResults []string
+	jo := newJSONOutput(flags)
 	highestExit := ExitOK
 
 	for i, t := range targets {
@@ -217,25 +405,14 @@ func runApp(cmd *cobra.Command, args []string) error {
 			printDivider(outp, t, colorEnabled, i > 0)
 		}
 
-		exitCode := processTarget(cmd, outw, outp, t, flags, multiMode, &jsonResults)
+		exitCode := processTarget(ctx, cmd, outw, outp, t, flags, multiMode, jo)
 		if exitCode > highestExit {
 			highestExit = exitCode
 		}
 	}
 
-	// Emit JSON array for multi-target
-	if flags.json && multiMode {
-		indented := make([]string, len(jsonResults))
-		for i, r := range jsonResults {
-			lines := strings.Split(r, "\n")
-			for j := range lines {
-				if j > 0 {
-					lines[j] = "  " + lines[j]
-				}
-			}
-			indented[i] = "  " + strings.Join(lines, "\n")
-		}
-		fmt.Fprintf(outw, "[\n%s\n]\n", strings.Join(indented, ",\n"))
+	if err := jo.write(outw); err != nil {
+		return withExitCode(ExitInternalError, fmt.Errorf("failed to generate json output: %w", err))
 	}
 
 	if highestExit > ExitOK {
@@ -245,6 +422,70 @@ func runApp(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// checkWatchFlags rejects --watch combinations that have nothing to watch or
+// no way to show a change.
+func checkWatchFlags(cmd *cobra.Command, flags appFlags, interval time.Duration) error {
+	if interval < 0 {
+		return withExitCode(ExitInvalidInput, fmt.Errorf("invalid --watch interval %s", interval))
+	}
+	if replayPath != "" {
+		return withExitCode(ExitInvalidInput, fmt.Errorf("--watch needs a live system; a snapshot never changes"))
+	}
+	for _, name := range []string{"env", "short", "tree", "warnings", "all", "summary"} {
+		if boolFlag(cmd, name) {
+			return withExitCode(ExitInvalidInput, fmt.Errorf("--watch cannot be combined with --%s", name))
+		}
+	}
+	return nil
+}
+
+// parseSelector reads the flags that narrow what a target matches.
+func parseSelector(cmd *cobra.Command) (target.Selector, error) {
+	var sel target.Selector
+	sel.User, _ = cmd.Flags().GetString("user")
+	sel.InContainer, _ = cmd.Flags().GetString("in-container")
+	sel.Cwd, _ = cmd.Flags().GetString("cwd")
+	sel.Env, _ = cmd.Flags().GetStringArray("with-env")
+	if src, _ := cmd.Flags().GetString("source"); src != "" {
+		st, err := target.ParseSourceType(src)
+		if err != nil {
+			return sel, withExitCode(ExitInvalidInput, err)
+		}
+		sel.Source = st
+	}
+	for _, kv := range sel.Env {
+		if key, _, _ := strings.Cut(kv, "="); key == "" {
+			return sel, withExitCode(ExitInvalidInput, fmt.Errorf("invalid --with-env %q: want KEY=VALUE or KEY", kv))
+		}
+	}
+	if ns, _ := cmd.Flags().GetString("netns"); ns != "" {
+		inode, err := target.ParseNetns(ns)
+		if err != nil {
+			return sel, withExitCode(ExitInvalidInput, err)
+		}
+		sel.Netns = inode
+	}
+	return sel, nil
+}
+
+// resolvePIDs returns the PIDs t matches that the selector flags keep.
+func resolvePIDs(ctx context.Context, t model.Target, flags appFlags) ([]int, error) {
+	if flags.sel.Netns != "" {
+		ctx = target.WithAllNetns(ctx)
+	}
+	var pids []int
+	var err error
+	if t.Type == model.TargetSocket && flags.clients {
+		pids, err = target.ResolveSocketClients(ctx, t.Value)
+	} else {
+		pids, err = target.Resolve(ctx, t, flags.exact)
+	}
+	if err != nil || len(pids) == 0 {
+		return pids, err
+	}
+	return target.Filter(ctx, pids, flags.sel, flags.exact)
+}
+
 func boolFlag(cmd *cobra.Command, name string) bool {
 	v, _ := cmd.Flags().GetBool(name)
 	return v
@@ -286,6 +527,9 @@ func collectTargetsInOrder(rawArgs []string, positionalArgs []string, takesValue
 		"-o": model.TargetPort, "--port": model.TargetPort,
 		"-f": model.TargetFile, "--file": model.TargetFile,
 		"-c": model.TargetContainer, "--container": model.TargetContainer,
+		"--socket":     model.TargetSocket,
+		"--connection": model.TargetConnection,
+		"--regex":      model.TargetRegex,
 	}
 
 	// Track which positional args we've placed so we can insert them in order
@@ -306,12 +550,7 @@ func collectTargetsInOrder(rawArgs []string, positionalArgs []string, takesValue
 				flagName := arg[:eqIdx]
 				flagVal := arg[eqIdx+1:]
 				if tt, ok := flagType[flagName]; ok {
-					for _, v := range strings.Split(flagVal, ",") {
-						v = strings.TrimSpace(v)
-						if v != "" {
-							targets = append(targets, model.Target{Type: tt, Value: v})
-						}
-					}
+					targets = appendTargetValues(targets, tt, flagVal)
 				}
 				i++
 				continue
@@ -322,12 +561,7 @@ func collectTargetsInOrder(rawArgs []string, positionalArgs []string, takesValue
 		if tt, ok := flagType[arg]; ok {
 			if i+1 < len(rawArgs) {
 				i++
-				for _, v := range strings.Split(rawArgs[i], ",") {
-					v = strings.TrimSpace(v)
-					if v != "" {
-						targets = append(targets, model.Target{Type: tt, Value: v})
-					}
-				}
+				targets = appendTargetValues(targets, tt, rawArgs[i])
 			}
 			i++
 			continue
@@ -361,6 +595,22 @@ func collectTargetsInOrder(rawArgs []string, positionalArgs []string, takesValue
 	return targets
 }
 
+// appendTargetValues appends the targets one flag value names: a
+// comma-separated list, except for a regular expression, whose commas are
+// its own.
+func appendTargetValues(targets []model.Target, tt model.TargetType, val string) []model.Target {
+	if tt == model.TargetRegex {
+		return append(targets, model.Target{Type: tt, Value: val})
+	}
+	for _, v := range strings.Split(val, ",") {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			targets = append(targets, model.Target{Type: tt, Value: v})
+		}
+	}
+	return targets
+}
+
 // targetLabel returns a human-readable label for the divider.
 func targetLabel(t model.Target) string {
 	switch t.Type {
@@ -372,6 +622,12 @@ func targetLabel(t model.Target) string {
 		return fmt.Sprintf("file: %s", t.Value)
 	case model.TargetContainer:
 		return fmt.Sprintf("container: %s", t.Value)
+	case model.TargetSocket:
+		return fmt.Sprintf("socket: %s", t.Value)
+	case model.TargetConnection:
+		return fmt.Sprintf("connection: %s", t.Value)
+	case model.TargetRegex:
+		return fmt.Sprintf("regex: %s", t.Value)
 	default:
 		return fmt.Sprintf("name: %s", t.Value)
 	}
@@ -389,129 +645,233 @@ func printDivider(outp output.Printer, t model.Target, colorEnabled bool, needsN
 	}
 }
 
-// jsonErrorEntry returns a JSON string representing a failed target lookup.
-func jsonErrorEntry(t model.Target, errMsg string) string {
-	type errorEntry struct {
-		Target model.Target
-		Error  string
-	}
-	data, _ := json.MarshalIndent(errorEntry{Error: errMsg, Target: t}, "", "  ")
-	return string(data)
-}
-
 // processTarget handles resolving and rendering a single target.
 // Returns the exit code for this target.
-func processTarget(cmd *cobra.Command, outw io.Writer, outp output.Printer, t model.Target, flags appFlags, multiMode bool, jsonResults *[]string) int {
+func processTarget(ctx context.Context, cmd *cobra.Command, outw io.Writer, outp output.Printer, t model.Target, flags appFlags, multiMode bool, jo *jsonOutput) int {
 	colorEnabled := useColor(flags, outw)
 
 	if flags.env {
-		return processEnvTarget(outw, outp, t, flags, multiMode, jsonResults)
+		return processEnvTarget(ctx, outw, outp, t, flags, multiMode, jo)
 	}
 
 	if t.Type == model.TargetContainer {
-		return processContainerTarget(cmd, outw, outp, t, flags, multiMode, jsonResults)
+		return processContainerTarget(ctx, cmd, outw, outp, t, flags, multiMode, jo)
 	}
 
-	pids, err := target.Resolve(t, flags.exact)
+	if t.Type == model.TargetPort {
+		if spec, err := target.ParsePort(t.Value); err == nil && spec.IsRange() {
+			return processPortRange(ctx, cmd, outw, outp, t, spec, flags, multiMode, jo)
+		}
+	}
+
+	pids, err := resolvePIDs(ctx, t, flags)
 	if err == nil && len(pids) == 0 {
 		err = fmt.Errorf("no matching process found")
 	}
 	if err != nil {
-		return handleResolveError(cmd, outw, outp, t, err, flags, multiMode, jsonResults)
+		return handleResolveError(ctx, cmd, outw, outp, t, err, flags, multiMode, jo)
+	}
+
+	if flags.all {
+		return processAllMatches(ctx, outw, outp, t, pids, flags, multiMode, jo)
 	}
 
 	if len(pids) > 1 {
-		if multiMode && flags.json {
-			*jsonResults = append(*jsonResults, jsonErrorEntry(t, fmt.Sprintf("multiple processes matched (%d results)", len(pids))))
-		} else {
-			hint := "witr --pid <pid>"
+		if jo.report {
+			jo.addError(t, fmt.Sprintf("multiple processes matched (%d results)", len(pids)), pids)
+			return ExitInvalidInput
+		}
+		if canPick(outw, flags, multiMode) {
+			pid, ok, err := pickProcess(ctx, t, pids)
+			if ok {
+				pids = []int{pid}
+			} else if err == nil {
+				return ExitInvalidInput
+			}
+		}
+		if len(pids) > 1 {
+			hint := rerunCommand() + " --pid <pid>"
 			if flags.env {
-				hint = "witr --pid <pid> --env"
+				hint += " --env"
+			}
+			hints := []string{hint}
+			if t.Type == model.TargetPort && spansNetns(pids) {
+				// The same port bound in several containers: --netns picks one.
+				hints = append(hints, rerunCommand()+" --port "+t.Value+" --netns <netns>")
 			}
-			printMultiMatch(outp, pids, colorEnabled, hint)
+			printMultiMatch(outp, pids, colorEnabled, hints...)
+			return ExitInvalidInput
 		}
-		return ExitInvalidInput
 	}
 
-	pid := pids[0]
-
-	var systemdService string
-	if t.Type == model.TargetPort && pid == 1 && source.IsSystemdRunning() {
-		if portNum, err := strconv.Atoi(t.Value); err == nil {
-			if svc, err := procpkg.ResolveSystemdService(portNum); err == nil && svc != "" {
-				systemdService = svc
-			}
+	res, err := analyzeMatch(ctx, t, pids[0], flags, !multiMode)
+	if err != nil {
+		err = describeAbandoned(err, flags)
+		if jo.report {
+			jo.addError(t, err.Error(), nil)
+			return classifyError(err)
 		}
+		if multiMode {
+			outp.Printf("Error: %v\n", err)
+			return classifyError(err)
+		}
+		if abandoned(err) {
+			cmd.PrintErrln(err)
+			return classifyError(err)
+		}
+		errStr := err.Error()
+		errorMsg := fmt.Sprintf("%s\n\nNo matching process or service found. Please check your query or try a different name/port/PID.\nFor usage and options, run: witr --help", errStr)
+		cmd.PrintErrln(errorMsg)
+		return classifyError(err)
 	}
 
-	res, err := pipeline.AnalyzePID(pipeline.AnalyzeConfig{
+	renderResult(outw, res, flags, multiMode, jo)
+	return warningsExit(res, flags)
+}
+
+// analyzeMatch analyzes pid, a process t resolved to, with the suppression
+// rules applied. clients looks up the processes connected to it, which only
+// the result of a single match is worth.
+func analyzeMatch(ctx context.Context, t model.Target, pid int, flags appFlags, clients bool) (model.Result, error) {
+	res, err := pipeline.AnalyzePID(ctx, pipeline.AnalyzeConfig{
 		PID:     pid,
 		Verbose: flags.verbose,
 		Tree:    flags.tree,
+		Clients: clients,
 		Target:  t,
 	})
-
 	if err != nil {
-		if multiMode {
-			if flags.json {
-				*jsonResults = append(*jsonResults, jsonErrorEntry(t, err.Error()))
+		return model.Result{}, err
+	}
+
+	if t.Type == model.TargetPort {
+		if spec, err := target.ParsePort(t.Value); err == nil && !spec.IsRange() {
+			pipeline.AnnotatePortTarget(ctx, &res, spec.Low, spec.Proto)
+		}
+	}
+
+	applySuppression(ctx, &res, flags)
+	return res, nil
+}
+
+// processAllMatches analyzes every process t matched (--all): a result for
+// each, then the matches grouped by the source that started them. With
+// --summary only the grouping is shown. A match that cannot be analyzed is
+// reported and the rest still are.
+func processAllMatches(ctx context.Context, outw io.Writer, outp output.Printer, t model.Target, pids []int, flags appFlags, multiMode bool, jo *jsonOutput) int {
+	colorEnabled := useColor(flags, outw)
+	highestExit := ExitOK
+	rendered := false
+
+	var results []model.Result
+	for _, pid := range pids {
+		res, err := analyzeMatch(ctx, t, pid, flags, false)
+		if err != nil {
+			err = describeAbandoned(err, flags)
+			if jo.report {
+				jo.addError(t, fmt.Sprintf("pid %d: %v", pid, err), []int{pid})
 			} else {
-				outp.Printf("Error: %v\n", err)
+				outp.Printf("Error: pid %d: %v\n", pid, err)
 			}
-			return classifyError(err)
+			highestExit = max(highestExit, classifyError(err))
+			if abandoned(err) {
+				// Out of time: the other matches would fail the same way.
+				break
+			}
+			continue
 		}
-		errStr := err.Error()
-		errorMsg := fmt.Sprintf("%s\n\nNo matching process or service found. Please check your query or try a different name/port/PID.\nFor usage and options, run: witr --help", errStr)
-		cmd.PrintErrln(errorMsg)
-		return classifyError(err)
-	}
+		results = append(results, res)
+		highestExit = max(highestExit, warningsExit(res, flags))
 
-	if systemdService != "" {
-		res.ResolvedTarget = strings.TrimSuffix(systemdService, ".service")
+		if flags.summary && !jo.report {
+			continue
+		}
+		if !flags.json && len(pids) > 1 {
+			printMatchDivider(outp, res, colorEnabled, rendered)
+		}
+		renderResult(outw, res, flags, multiMode, jo)
+		rendered = true
 	}
 
-	if t.Type == model.TargetPort {
-		portNum := 0
-		fmt.Sscanf(t.Value, "%d", &portNum)
-		if portNum > 0 {
-			res.SocketInfo = procpkg.GetSocketStateForPort(portNum)
-			source.EnrichSocketInfo(res.SocketInfo)
+	summary := output.SummarizeMatches(t, results)
+	switch {
+	case jo.report:
+		jo.addSummary(summary)
+	case flags.summary || len(results) > 1:
+		if rendered {
+			outp.Println()
 		}
+		output.RenderMatchSummary(outw, summary, colorEnabled)
 	}
+	return highestExit
+}
 
-	renderResult(outw, res, flags, multiMode, jsonResults)
+// printMatchDivider heads the result of one of several processes a target
+// matched.
+func printMatchDivider(outp output.Printer, res model.Result, colorEnabled bool, needsNewline bool) {
+	label := fmt.Sprintf("%s, pid %d", output.ChainName(res.Process), res.Process.PID)
+	if needsNewline {
+		outp.Println()
+	}
+	if colorEnabled {
+		outp.Printf("%s----- [%s] -----%s\n", output.ColorCyan, label, output.ColorReset)
+	} else {
+		outp.Printf("----- [%s] -----\n", label)
+	}
+}
+
+// failOnNone is --fail-on=none: warnings never set the exit code.
+const failOnNone model.Severity = "none"
 
-	if len(res.Warnings) > 0 {
-		return ExitWarnings
+// warningsExit is ExitWarnings when res has a warning at or above the
+// --fail-on severity, and ExitOK otherwise.
+func warningsExit(res model.Result, flags appFlags) int {
+	if flags.failOn == failOnNone {
+		return ExitOK
+	}
+	for _, w := range res.Warnings {
+		// A warning without a severity counts whatever the threshold.
+		if w.Severity == "" || w.Severity.AtLeast(flags.failOn) {
+			return ExitWarnings
+		}
 	}
 	return ExitOK
 }
 
 // processEnvTarget handles the --env flag for a single target.
-func processEnvTarget(outw io.Writer, outp output.Printer, t model.Target, flags appFlags, multiMode bool, jsonResults *[]string) int {
+func processEnvTarget(ctx context.Context, outw io.Writer, outp output.Printer, t model.Target, flags appFlags, multiMode bool, jo *jsonOutput) int {
 	colorEnabled := useColor(flags, outw)
 
-	pids, err := target.Resolve(t, flags.exact)
+	pids, err := resolvePIDs(ctx, t, flags)
 	if err != nil {
+		err = describeAbandoned(err, flags)
+		if jo.report {
+			jo.addError(t, err.Error(), nil)
+			return classifyError(err)
+		}
 		if multiMode {
-			if flags.json {
-				*jsonResults = append(*jsonResults, jsonErrorEntry(t, err.Error()))
-			} else {
-				outp.Printf("Error: %v\n", err)
-			}
+			outp.Printf("Error: %v\n", err)
 			return classifyError(err)
 		}
 		outp.Printf("error: %v\n", err)
 		return classifyError(err)
 	}
 	if len(pids) == 0 {
-		if multiMode && flags.json {
-			*jsonResults = append(*jsonResults, jsonErrorEntry(t, "no matching process found"))
+		if jo.report {
+			jo.addError(t, "no matching process found", nil)
 			return ExitNotFound
 		}
 		outp.Println("No matching process found.")
 		return ExitNotFound
 	}
+	if len(pids) > 1 && canPick(outw, flags, multiMode) {
+		pid, ok, err := pickProcess(ctx, t, pids)
+		if ok {
+			pids = []int{pid}
+		} else if err == nil {
+			return ExitInvalidInput
+		}
+	}
 	if len(pids) > 1 {
 		printMultiMatch(outp, pids, colorEnabled, "witr --pid <pid> --env")
 		return ExitInvalidInput
@@ -525,21 +885,13 @@ func processEnvTarget(outw io.Writer, outp output.Printer, t model.Target, flags
 	}
 
 	resEnv := model.Result{
+		Target:   t,
 		Process:  procInfo,
 		Ancestry: []model.Process{procInfo},
 	}
 
-	if flags.json {
-		jsonStr, err := output.ToEnvJSON(resEnv)
-		if err != nil {
-			outp.Printf("failed to generate json output: %v\n", err)
-			return ExitInternalError
-		}
-		if multiMode {
-			*jsonResults = append(*jsonResults, jsonStr)
-		} else {
-			fmt.Fprintln(outw, jsonStr)
-		}
+	if jo.report {
+		jo.addResult(resEnv)
 	} else {
 		output.RenderEnvOnly(outw, resEnv, colorEnabled)
 	}
@@ -547,21 +899,35 @@ func processEnvTarget(outw io.Writer, outp output.Printer, t model.Target, flags
 }
 
 // handleResolveError handles target resolution errors, including Docker fallback.
-func handleResolveError(cmd *cobra.Command, outw io.Writer, outp output.Printer, t model.Target, err error, flags appFlags, multiMode bool, jsonResults *[]string) int {
+func handleResolveError(ctx context.Context, cmd *cobra.Command, outw io.Writer, outp output.Printer, t model.Target, err error, flags appFlags, multiMode bool, jo *jsonOutput) int {
+	err = describeAbandoned(err, flags)
 	errStr := err.Error()
 	colorEnabled := useColor(flags, outw)
 
+	// Out of time or interrupted: nothing is known about the target, so
+	// neither "not found" nor a sudo hint applies.
+	if abandoned(err) {
+		switch {
+		case jo.report:
+			jo.addError(t, errStr, nil)
+		case multiMode:
+			outp.Printf("Error: %v\n", err)
+		default:
+			cmd.PrintErrln(errStr)
+		}
+		return classifyError(err)
+	}
+
 	// Platform-unsupported target (e.g. -f on Windows). Don't tack on the
 	// generic "try a different name/port/PID" suffix — the operation isn't a
 	// failed lookup, it's unavailable on this OS.
 	if errors.Is(err, target.ErrUnsupported) || strings.Contains(errStr, "not supported on") {
-		if multiMode {
-			if flags.json {
-				*jsonResults = append(*jsonResults, jsonErrorEntry(t, errStr))
-			} else {
-				outp.Printf("Error: %v\n", err)
-			}
-		} else {
+		switch {
+		case jo.report:
+			jo.addError(t, errStr, nil)
+		case multiMode:
+			outp.Printf("Error: %v\n", err)
+		default:
 			cmd.PrintErrln(errStr)
 		}
 		return ExitInvalidInput
@@ -569,20 +935,11 @@ func handleResolveError(cmd *cobra.Command, outw io.Writer, outp output.Printer,
 
 	if errors.Is(err, target.ErrSocketOwnerUnknown) || strings.Contains(errStr, "socket found but owning process not detected") {
 		if t.Type == model.TargetPort {
-			if portNum, convErr := strconv.Atoi(t.Value); convErr == nil {
-				if match := procpkg.ResolveContainerByPort(portNum); match != nil {
+			if spec, parseErr := target.ParsePort(t.Value); parseErr == nil && !spec.IsRange() {
+				if match := procpkg.ResolveContainerByPort(ctx, spec.Low); match != nil {
 					label := "port " + t.Value
-					if flags.json {
-						jsonStr, jsonErr := output.ContainerFallbackToJSON(label, match)
-						if jsonErr != nil {
-							outp.Printf("failed to generate json output: %v\n", jsonErr)
-							return ExitInternalError
-						}
-						if multiMode {
-							*jsonResults = append(*jsonResults, jsonStr)
-						} else {
-							fmt.Fprintln(outw, jsonStr)
-						}
+					if jo.report {
+						jo.addContainer(t, match)
 					} else if flags.short {
 						output.RenderContainerFallbackShort(outw, label, match, colorEnabled)
 					} else {
@@ -592,25 +949,29 @@ func handleResolveError(cmd *cobra.Command, outw io.Writer, outp output.Printer,
 				}
 			}
 		}
+		if jo.report {
+			jo.addError(t, "socket found but owning process not detected (try sudo)", nil)
+			return ExitPermission
+		}
 		if multiMode {
-			if flags.json {
-				*jsonResults = append(*jsonResults, jsonErrorEntry(t, "socket found but owning process not detected (try sudo)"))
-			} else {
-				outp.Printf("Error: socket found but owning process not detected (try sudo)\n")
-			}
+			outp.Printf("Error: socket found but owning process not detected (try sudo)\n")
 			return ExitPermission
 		}
-		errorMsg := fmt.Sprintf("%s\n\nA socket was found for the port, but the owning process could not be detected.\nThis may be due to insufficient permissions. Try running with sudo:\n  sudo %s", errStr, strings.Join(os.Args, " "))
+		what := "port"
+		if t.Type == model.TargetSocket {
+			what = "path"
+		}
+		errorMsg := fmt.Sprintf("%s\n\nA socket was found for the "+what+", but the owning process could not be detected.\nThis may be due to insufficient permissions. Try running with sudo:\n  sudo %s", errStr, strings.Join(os.Args, " "))
 		cmd.PrintErrln(errorMsg)
 		return ExitPermission
 	}
 
+	if jo.report {
+		jo.addError(t, errStr, nil)
+		return classifyError(err)
+	}
 	if multiMode {
-		if flags.json {
-			*jsonResults = append(*jsonResults, jsonErrorEntry(t, errStr))
-		} else {
-			outp.Printf("Error: %v\n", err)
-		}
+		outp.Printf("Error: %v\n", err)
 		return classifyError(err)
 	}
 	errorMsg := fmt.Sprintf("%s\n\nNo matching process or service found. Please check your query or try a different name/port/PID.\nFor usage and options, run: witr --help", errStr)
@@ -622,32 +983,11 @@ func handleResolveError(cmd *cobra.Command, outw io.Writer, outp output.Printer,
 }
 
 // renderResult renders a single result in the appropriate output mode.
-func renderResult(outw io.Writer, res model.Result, flags appFlags, multiMode bool, jsonResults *[]string) {
+func renderResult(outw io.Writer, res model.Result, flags appFlags, multiMode bool, jo *jsonOutput) {
 	colorEnabled := useColor(flags, outw)
 
-	if flags.json {
-		var jsonStr string
-		var err error
-
-		if flags.short {
-			jsonStr, err = output.ToShortJSON(res)
-		} else if flags.tree {
-			jsonStr, err = output.ToTreeJSON(res)
-		} else if flags.warn {
-			jsonStr, err = output.ToWarningsJSON(res)
-		} else {
-			jsonStr, err = output.ToJSON(res)
-		}
-
-		if err != nil {
-			fmt.Fprintf(outw, "failed to generate json output: %v\n", err)
-			return
-		}
-		if multiMode {
-			*jsonResults = append(*jsonResults, jsonStr)
-		} else {
-			fmt.Fprintln(outw, jsonStr)
-		}
+	if jo.report {
+		jo.addResult(res)
 	} else if flags.warn {
 		output.RenderWarnings(outw, res, colorEnabled)
 	} else if flags.tree {
@@ -669,8 +1009,9 @@ func runInteractive() error {
 	return tui.Start(v)
 }
 
-func printMultiMatch(outp output.Printer, pids []int, colorEnabled bool, hint string) {
+func printMultiMatch(outp output.Printer, pids []int, colorEnabled bool, hints ...string) {
 	outp.Printf("Multiple matching processes found:\n\n")
+	own := procpkg.OwnNetns()
 	for i, pid := range pids {
 		proc, err := procpkg.ReadProcess(pid)
 		var command, cmdline string
@@ -681,17 +1022,41 @@ func printMultiMatch(outp output.Printer, pids []int, colorEnabled bool, hint st
 			command = proc.Command
 			cmdline = proc.Cmdline
 		}
+		// Name the network namespace of a match outside witr's own, so
+		// listeners on one port in several containers can be told apart.
+		where := fmt.Sprintf("pid %d", pid)
+		if ns := procpkg.NetnsOf(pid); ns != "" && ns != own {
+			where += ", netns " + ns
+		}
 		if colorEnabled {
-			outp.Printf("[%d] %s%s%s (%spid %d%s)\n    %s\n",
+			outp.Printf("[%d] %s%s%s (%s%s%s)\n    %s\n",
 				i+1, output.ColorGreen, command, output.ColorReset,
-				output.ColorDim, pid, output.ColorReset,
+				output.ColorDim, where, output.ColorReset,
 				cmdline)
 		} else {
-			outp.Printf("[%d] %s (pid %d)\n    %s\n", i+1, command, pid, cmdline)
+			outp.Printf("[%d] %s (%s)\n    %s\n", i+1, command, where, cmdline)
 		}
 	}
 	outp.Printf("\nRe-run with:\n")
-	outp.Printf("  %s\n", hint)
+	for _, hint := range hints {
+		outp.Printf("  %s\n", hint)
+	}
+}
+
+// spansNetns reports whether pids are in more than one network namespace.
+func spansNetns(pids []int) bool {
+	seen := ""
+	for _, pid := range pids {
+		ns := procpkg.NetnsOf(pid)
+		if ns == "" {
+			continue
+		}
+		if seen != "" && ns != seen {
+			return true
+		}
+		seen = ns
+	}
+	return false
 }
 
 func printContainerMultiMatch(outp output.Printer, matches []*model.ContainerMatch, colorEnabled bool) {
@@ -722,8 +1087,29 @@ func printContainerMultiMatch(outp output.Printer, matches []*model.ContainerMat
 	outp.Println("  witr -c <container-name> --exact")
 }
 
+// abandoned reports whether err means a lookup was given up on: --timeout
+// ran out or the run was interrupted.
+func abandoned(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
+}
+
+// describeAbandoned says why a lookup was given up on, instead of the bare
+// "context deadline exceeded".
+func describeAbandoned(err error, flags appFlags) error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("timed out after %s (raise --timeout): %w", flags.timeout, err)
+	case errors.Is(err, context.Canceled):
+		return fmt.Errorf("interrupted: %w", err)
+	}
+	return err
+}
+
 // classifyError maps common error strings to exit codes.
 func classifyError(err error) int {
+	if abandoned(err) {
+		return ExitInternalError
+	}
 	msg := strings.ToLower(err.Error())
 	switch {
 	case strings.Contains(msg, "permission denied") ||
@@ -747,62 +1133,71 @@ func classifyError(err error) int {
 // every available container runtime, dispatches to the normal pipeline if
 // the container's main process is host-visible, otherwise renders the
 // runtime-side metadata via the container fallback view.
-func processContainerTarget(cmd *cobra.Command, outw io.Writer, outp output.Printer, t model.Target, flags appFlags, multiMode bool, jsonResults *[]string) int {
+func processContainerTarget(ctx context.Context, cmd *cobra.Command, outw io.Writer, outp output.Printer, t model.Target, flags appFlags, multiMode bool, jo *jsonOutput) int {
 	colorEnabled := useColor(flags, outw)
 
-	matches := procpkg.ResolveContainer(t.Value, flags.exact)
+	matches := procpkg.ResolveContainer(ctx, t.Value, flags.exact)
 	if len(matches) == 0 {
 		err := fmt.Errorf("no container found matching %q", t.Value)
-		return handleResolveError(cmd, outw, outp, t, err, flags, multiMode, jsonResults)
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		return handleResolveError(ctx, cmd, outw, outp, t, err, flags, multiMode, jo)
 	}
 
 	if len(matches) > 1 {
-		if multiMode && flags.json {
-			*jsonResults = append(*jsonResults, jsonErrorEntry(t, fmt.Sprintf("multiple containers matched (%d results)", len(matches))))
-		} else {
+		if jo.report {
+			jo.addError(t, fmt.Sprintf("multiple containers matched (%d results)", len(matches)), nil)
+			return ExitInvalidInput
+		}
+		if canPick(outw, flags, multiMode) {
+			match, ok, err := pickContainer(ctx, t, matches)
+			if ok {
+				matches = []*model.ContainerMatch{match}
+			} else if err == nil {
+				return ExitInvalidInput
+			}
+		}
+		if len(matches) > 1 {
 			printContainerMultiMatch(outp, matches, colorEnabled)
+			return ExitInvalidInput
 		}
-		return ExitInvalidInput
 	}
 
 	match := matches[0]
-	procpkg.EnrichContainer(match)
-	pid := procpkg.ResolveContainerHostPID(match.Runtime, match.ID)
+	procpkg.EnrichContainer(ctx, match)
+	pid := procpkg.ResolveContainerHostPID(ctx, match.Runtime, match.ID)
 	if pid > 0 && procpkg.PIDBelongsToContainer(pid, match.ID) {
-		res, err := pipeline.AnalyzePID(pipeline.AnalyzeConfig{
+		res, err := pipeline.AnalyzePID(ctx, pipeline.AnalyzeConfig{
 			PID:     pid,
 			Verbose: flags.verbose,
 			Tree:    flags.tree,
+			Clients: !multiMode,
 			Target:  t,
 		})
 		if err != nil {
-			outp.Printf("Error: %v\n", err)
+			err = describeAbandoned(err, flags)
+			if jo.report {
+				jo.addError(t, err.Error(), nil)
+			} else {
+				outp.Printf("Error: %v\n", err)
+			}
 			return classifyError(err)
 		}
+		res.Container = match
 		res.Process.Container = output.FormatContainerLine(match)
 		if len(res.Ancestry) > 0 {
 			res.Ancestry[len(res.Ancestry)-1].Container = res.Process.Container
 		}
-		renderResult(outw, res, flags, multiMode, jsonResults)
-		if len(res.Warnings) > 0 {
-			return ExitWarnings
-		}
-		return ExitOK
+		applySuppression(ctx, &res, flags)
+		renderResult(outw, res, flags, multiMode, jo)
+		return warningsExit(res, flags)
 	}
 
 	label := "container " + match.Name
 	switch {
-	case flags.json:
-		jsonStr, err := output.ContainerFallbackToJSON(label, match)
-		if err != nil {
-			outp.Printf("failed to generate json output: %v\n", err)
-			return ExitInternalError
-		}
-		if multiMode {
-			*jsonResults = append(*jsonResults, jsonStr)
-		} else {
-			fmt.Fprintln(outw, jsonStr)
-		}
+	case jo.report:
+		jo.addContainer(t, match)
 	case flags.short:
 		output.RenderContainerFallbackShort(outw, label, match, colorEnabled)
 	case flags.tree:
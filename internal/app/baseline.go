@@ -0,0 +1,187 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/internal/config"
+	"github.com/pranshuparmar/witr/internal/pipeline"
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/procfs"
+	"github.com/pranshuparmar/witr/internal/suppress"
+	"github.com/pranshuparmar/witr/internal/target"
+	"github.com/pranshuparmar/witr/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+var baselineCmd = &cobra.Command{
+	Use:   "baseline",
+	Short: "Accept the warnings processes have now",
+	Long: "A baseline records, per process, the warnings that are accepted on this host. witr\n" +
+		"reads it on every run and moves those warnings to a Suppressed list, so only new ones\n" +
+		"are reported and set the exit code; --show-suppressed lists them again.\n\n" +
+		"A process is identified by its name, executable and unit, not its PID, so the\n" +
+		"baseline still applies after a restart. A baseline saved on another host is ignored.\n" +
+		"The baseline is kept in $XDG_STATE_HOME/witr/baseline.json\n" +
+		"(~/.local/state/witr/baseline.json by default); --baseline uses another file.",
+	Args: cobra.NoArgs,
+}
+
+var baselineSaveCmd = &cobra.Command{
+	Use:   "save [process name...]",
+	Short: "Record the current warnings as accepted",
+	Long: "save analyzes the named processes, or every process when none is named, and records\n" +
+		"their warnings in the baseline. Entries for other processes are kept unless --replace\n" +
+		"is given. Warnings a suppress rule in the configuration already hides are not recorded.",
+	Example: `  # Accept what sshd and nginx warn about today
+  witr baseline save sshd nginx
+
+  # Start over from everything running now
+  witr baseline save --replace`,
+	RunE: runBaselineSave,
+}
+
+func init() {
+	baselineSaveCmd.Flags().BoolP("exact", "x", false, "use exact name matching (no substring search)")
+	baselineSaveCmd.Flags().Bool("replace", false, "drop entries for processes that were not analyzed this time")
+	baselineCmd.AddCommand(baselineSaveCmd)
+	rootCmd.AddCommand(baselineCmd)
+}
+
+// suppressor hides the warnings the configuration's suppress rules and the
+// baseline accept. It is nil until loadSuppressor runs.
+var suppressor *suppress.Suppressor
+
+// baselinePath is --baseline, or the default baseline file.
+func baselinePath(cmd *cobra.Command) (string, error) {
+	path, _ := cmd.Flags().GetString("baseline")
+	if path == "" {
+		path = suppress.DefaultBaselinePath()
+	}
+	if path == "" {
+		return "", withExitCode(ExitInvalidInput, fmt.Errorf("no home directory for the baseline; use --baseline"))
+	}
+	return path, nil
+}
+
+// loadSuppressor reads the baseline and sets suppressor. A baseline saved
+// on another host is ignored, with a note on stderr.
+func loadSuppressor(cmd *cobra.Command) error {
+	path, err := baselinePath(cmd)
+	if err != nil {
+		return err
+	}
+	b, err := suppress.LoadBaseline(path)
+	if err != nil {
+		return withExitCode(ExitInvalidInput, fmt.Errorf("baseline: %w", err))
+	}
+	if err := b.CheckHost(baselineHost()); err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "witr: ignoring baseline %s: %v\n", path, err)
+		b = nil
+	}
+	suppressor = suppress.New(config.Current().Suppress, b)
+	return nil
+}
+
+// applySuppression moves the accepted warnings of res to res.Suppressed,
+// and drops them entirely unless --show-suppressed asked to see them.
+func applySuppression(ctx context.Context, res *model.Result, flags appFlags) {
+	suppressor.Apply(ctx, res)
+	if !flags.showSuppressed {
+		res.Suppressed = nil
+	}
+}
+
+func runBaselineSave(cmd *cobra.Command, names []string) error {
+	path, err := baselinePath(cmd)
+	if err != nil {
+		return err
+	}
+	exact, _ := cmd.Flags().GetBool("exact")
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	idx := procpkg.NewIndex()
+	ctx = procpkg.WithIndex(ctx, idx)
+
+	var pids []int
+	if len(names) == 0 {
+		procs, err := idx.Processes()
+		if err != nil {
+			return withExitCode(classifyError(err), fmt.Errorf("list processes: %w", err))
+		}
+		for _, p := range procs {
+			if p.PID != os.Getpid() {
+				pids = append(pids, p.PID)
+			}
+		}
+	} else {
+		for _, name := range names {
+			matched, err := target.ResolveName(ctx, name, exact)
+			if err == nil && len(matched) == 0 {
+				err = fmt.Errorf("no matching process found")
+			}
+			if err != nil {
+				return withExitCode(classifyError(err), fmt.Errorf("%s: %w", name, err))
+			}
+			pids = append(pids, matched...)
+		}
+	}
+
+	// The baseline records what the rules leave visible; it is not applied,
+	// or the warnings it already accepts would be dropped from it.
+	rules := suppress.New(config.Current().Suppress, nil)
+	var rec suppress.Recorder
+	for _, pid := range pids {
+		res, err := pipeline.AnalyzePID(ctx, pipeline.AnalyzeConfig{PID: pid})
+		if err != nil {
+			// The process exited or is not readable; there is nothing to
+			// accept for it.
+			continue
+		}
+		rules.Apply(ctx, &res)
+		rec.Record(res)
+	}
+
+	b := &suppress.Baseline{}
+	if replace, _ := cmd.Flags().GetBool("replace"); !replace {
+		old, err := suppress.LoadBaseline(path)
+		if err != nil {
+			return withExitCode(ExitInvalidInput, fmt.Errorf("baseline: %w (use --replace to overwrite it)", err))
+		}
+		if old != nil {
+			b = old
+		}
+	}
+	b.Merge(rec.Entries())
+	b.Hostname = baselineHost()
+	b.SavedAt = time.Now().UTC()
+	if err := b.Save(path); err != nil {
+		return withExitCode(ExitInternalError, fmt.Errorf("save baseline: %w", err))
+	}
+
+	accepted := 0
+	for _, e := range rec.Entries() {
+		accepted += len(e.Warnings)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Accepted %d warnings for %d processes in %s\n", accepted, len(rec.Entries()), path)
+	return nil
+}
+
+// baselineHost names the host whose processes are analyzed: the live
+// system, or the one a snapshot or --proc-root was taken from.
+func baselineHost() string {
+	if data, err := procfs.ReadFile("/proc/sys/kernel/hostname"); err == nil {
+		if host := strings.TrimSpace(string(data)); host != "" {
+			return host
+		}
+	}
+	host, _ := procpkg.HostIdentity()
+	return host
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pranshuparmar/witr/internal/config"
+	"github.com/pranshuparmar/witr/internal/suppress"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// TestApplySuppression checks that suppressed warnings stop setting the exit
+// code and are only kept for output with --show-suppressed.
+func TestApplySuppression(t *testing.T) {
+	old := suppressor
+	t.Cleanup(func() { suppressor = old })
+	suppressor = suppress.New([]config.SuppressRule{{Code: "W_ROOT"}}, nil)
+
+	newResult := func() model.Result {
+		return model.Result{Warnings: []model.Warning{
+			{Code: model.WarnRoot, Severity: model.SeverityLow, Message: "Process is running as root"},
+		}}
+	}
+
+	res := newResult()
+	applySuppression(context.Background(), &res, appFlags{})
+	if len(res.Warnings) != 0 || res.Suppressed != nil {
+		t.Errorf("without --show-suppressed: warnings %v, suppressed %v", res.Warnings, res.Suppressed)
+	}
+	if code := warningsExit(res, appFlags{}); code != ExitOK {
+		t.Errorf("a suppressed warning should not set the exit code, got %d", code)
+	}
+
+	res = newResult()
+	applySuppression(context.Background(), &res, appFlags{showSuppressed: true})
+	if len(res.Suppressed) != 1 || res.Suppressed[0].SuppressedBy != "config" {
+		t.Errorf("with --show-suppressed: suppressed %+v", res.Suppressed)
+	}
+}
@@ -2,7 +2,6 @@ package app
 
 import (
 	"reflect"
-	"strings"
 	"testing"
 
 	"github.com/pranshuparmar/witr/pkg/model"
@@ -44,6 +43,11 @@ func TestCollectTargetsInOrder(t *testing.T) {
 			rawArgs: []string{"--port=8080"},
 			want:    []model.Target{tgt(model.TargetPort, "8080")},
 		},
+		{
+			name:    "regex keeps its commas",
+			rawArgs: []string{"--regex", "worker-{1,3}", "--regex=a,b"},
+			want:    []model.Target{tgt(model.TargetRegex, "worker-{1,3}"), tgt(model.TargetRegex, "a,b")},
+		},
 		{
 			name:    "comma split, space form",
 			rawArgs: []string{"--port", "80,443"},
@@ -184,14 +188,3 @@ func TestTargetLabel(t *testing.T) {
 		}
 	}
 }
-
-func TestJSONErrorEntry(t *testing.T) {
-	t.Parallel()
-
-	s := jsonErrorEntry(tgt(model.TargetPort, "8080"), "boom")
-	for _, want := range []string{`"Error"`, "boom", "8080", "port"} {
-		if !strings.Contains(s, want) {
-			t.Errorf("jsonErrorEntry missing %q in:\n%s", want, s)
-		}
-	}
-}
@@ -0,0 +1,142 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pranshuparmar/witr/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect witr's configuration",
+	Long: "witr reads " + config.SystemPath() + " and then the user's\n" +
+		"$XDG_CONFIG_HOME/witr/config.yaml (~/.config/witr/config.yaml by default); a later file\n" +
+		"overrides an earlier one. --config, or $WITR_CONFIG, reads one file instead of both.\n\n" +
+		"A config file can set default flags, warning and health thresholds, and extend the\n" +
+		"tables of known supervisors, suspicious working directories, dangerous capabilities and\n" +
+		"environment variable rules. List a table under `replace:` to replace it instead.",
+	Args: cobra.NoArgs,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective configuration",
+	Long: "show prints the configuration witr runs with: the built-in defaults merged with every\n" +
+		"config file that was read, in the YAML format the files use.",
+	Example: `  # Start a user config from the effective one
+  witr config show > ~/.config/witr/config.yaml
+
+  # Check what a file changes
+  witr --config ./witr.yaml config show`,
+	Args: cobra.NoArgs,
+	RunE: runConfigShow,
+}
+
+func init() {
+	configCmd.AddCommand(configShowCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// configFiles are the config files loadConfig read, lowest precedence first.
+var configFiles []string
+
+// notConfigurable are flags a config file cannot default: they pick what one
+// run analyzes, narrow it, or switch witr into another mode. A defaulted
+// selector would silently filter every run.
+var notConfigurable = []string{
+	"pid", "port", "file", "container", "regex", "socket", "clients", "connection",
+	"user", "source", "in-container", "cwd", "with-env", "netns",
+	"interactive", "watch", "snapshot", "config", "help", "version",
+}
+
+// loadConfig reads the config files, makes the result the policy every
+// package uses, and fills in the flags the command line left unset.
+func loadConfig(cmd *cobra.Command) error {
+	paths := config.Paths()
+	path, _ := cmd.Flags().GetString("config")
+	if path == "" {
+		path = os.Getenv("WITR_CONFIG")
+	}
+	if path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return withExitCode(ExitInvalidInput, fmt.Errorf("config: %w", err))
+		}
+		paths = []string{path}
+	}
+
+	cfg, read, err := config.Load(paths)
+	if err != nil {
+		return withExitCode(ExitInvalidInput, fmt.Errorf("config: %w", err))
+	}
+	if err := applyFlagDefaults(cmd, cfg.Defaults); err != nil {
+		return withExitCode(ExitInvalidInput, fmt.Errorf("config: %w", err))
+	}
+	config.Set(cfg)
+	configFiles = read
+	return nil
+}
+
+// applyFlagDefaults sets each of cmd's flags named in defaults that was not
+// given on the command line. Defaults for flags of other commands are
+// skipped; names no command knows are an error, to catch typos.
+func applyFlagDefaults(cmd *cobra.Command, defaults map[string]string) error {
+	names := make([]string, 0, len(defaults))
+	for name := range defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if slices.Contains(notConfigurable, name) {
+			return fmt.Errorf("defaults: --%s cannot be set in a config file", name)
+		}
+		f := cmd.Flags().Lookup(name)
+		if f == nil {
+			if !anyCommandHasFlag(cmd.Root(), name) {
+				return fmt.Errorf("defaults: unknown flag --%s", name)
+			}
+			continue
+		}
+		if f.Changed {
+			continue
+		}
+		// Value.Set rather than Flags().Set leaves the flag unchanged, so it
+		// still reads as not given on the command line.
+		if err := f.Value.Set(defaults[name]); err != nil {
+			return fmt.Errorf("defaults: --%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func anyCommandHasFlag(c *cobra.Command, name string) bool {
+	if c.Flags().Lookup(name) != nil || c.PersistentFlags().Lookup(name) != nil {
+		return true
+	}
+	for _, sub := range c.Commands() {
+		if anyCommandHasFlag(sub, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func runConfigShow(cmd *cobra.Command, _ []string) error {
+	outw := cmd.OutOrStdout()
+	if len(configFiles) == 0 {
+		fmt.Fprintln(outw, "# Built-in defaults; no config file was found.")
+	} else {
+		fmt.Fprintf(outw, "# Built-in defaults, overridden by %s\n", strings.Join(configFiles, ", then "))
+	}
+	if err := config.Current().Write(outw); err != nil {
+		return withExitCode(ExitInternalError, err)
+	}
+	return nil
+}
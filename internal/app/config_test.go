@@ -0,0 +1,81 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestApplyFlagDefaults(t *testing.T) {
+	newCmds := func() (root, sub *cobra.Command) {
+		root = &cobra.Command{Use: "witr"}
+		root.Flags().Bool("verbose", false, "")
+		root.Flags().Bool("no-color", false, "")
+		root.Flags().StringSlice("pid", nil, "")
+		sub = &cobra.Command{Use: "diff"}
+		sub.Flags().Bool("json", false, "")
+		root.AddCommand(sub)
+		return root, sub
+	}
+
+	root, _ := newCmds()
+	if err := root.ParseFlags([]string{"--no-color=false"}); err != nil {
+		t.Fatal(err)
+	}
+	err := applyFlagDefaults(root, map[string]string{"verbose": "true", "no-color": "true", "json": "true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !boolFlag(root, "verbose") {
+		t.Error("a default should set a flag the command line left unset")
+	}
+	if boolFlag(root, "no-color") {
+		t.Error("the command line should win over a default")
+	}
+	if root.Flags().Changed("verbose") {
+		t.Error("a defaulted flag should still read as not given")
+	}
+
+	_, sub := newCmds()
+	if err := applyFlagDefaults(sub, map[string]string{"verbose": "true", "json": "true"}); err != nil || !boolFlag(sub, "json") {
+		t.Errorf("subcommand defaults: json=%v, err %v", boolFlag(sub, "json"), err)
+	}
+
+	for defaults, want := range map[string]string{
+		"pid":        "cannot be set",
+		"regex":      "cannot be set",
+		"user":       "cannot be set",
+		"socket":     "cannot be set",
+		"clients":    "cannot be set",
+		"connection": "cannot be set",
+		"netns":      "cannot be set",
+		"verbos":     "unknown flag",
+		"verbose":    "invalid",
+	} {
+		root, _ := newCmds()
+		value := "true"
+		if defaults == "verbose" {
+			value = "often"
+		}
+		err := applyFlagDefaults(root, map[string]string{defaults: value})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("default %s=%s: got %v, want an error containing %q", defaults, value, err, want)
+		}
+	}
+}
+
+func TestConfigCannotDefaultTargets(t *testing.T) {
+	// A defaulted target or selector would change what every run analyzes,
+	// and keep a bare witr from reaching the interactive picker.
+	for _, name := range []string{"pid", "port", "file", "container", "regex", "socket", "clients", "connection", "user", "source", "in-container", "cwd", "with-env", "netns"} {
+		if rootCmd.Flags().Lookup(name) == nil {
+			t.Errorf("witr has no --%s flag", name)
+			continue
+		}
+		err := applyFlagDefaults(rootCmd, map[string]string{name: "x"})
+		if err == nil || !strings.Contains(err.Error(), "cannot be set") {
+			t.Errorf("default %s: got %v, want it refused", name, err)
+		}
+	}
+}
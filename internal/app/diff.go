@@ -0,0 +1,249 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pranshuparmar/witr/internal/diff"
+	"github.com/pranshuparmar/witr/internal/output"
+	"github.com/pranshuparmar/witr/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff OLD.json NEW.json",
+	Short: "Compare two saved witr --json results",
+	Long: "diff reports what changed between two saved `witr --json` outputs, such as before and\n" +
+		"after a deploy: the ancestry chain, the source, sockets, environment variables,\n" +
+		"capabilities, warnings, and resource usage. Multi-target outputs are matched target by\n" +
+		"target. The exit code is 0 when nothing changed and 1 when something did, like diff(1).",
+	Example: `  # Save a result before and after a deploy, then compare them
+  witr --port 8080 --json > before.json
+  witr --port 8080 --json > after.json
+  witr diff before.json after.json
+
+  # Machine-readable differences
+  witr diff before.json after.json --json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().Bool("json", false, "show differences as JSON")
+	diffCmd.Flags().Bool("no-color", false, "disable colorized output")
+	rootCmd.AddCommand(diffCmd)
+}
+
+// savedEntry is one target of a saved `witr --json` document: a full result,
+// or the entry written for a target that failed.
+type savedEntry struct {
+	model.Result
+	Error string
+}
+
+// targetDiff is the comparison of one target across the two documents.
+type targetDiff struct {
+	Target model.Target
+	// Only is "old" or "new" when the target appears in just one document.
+	Only    string        `json:",omitempty"`
+	Changes []diff.Change `json:",omitempty"`
+}
+
+type diffReport struct {
+	Old     string
+	New     string
+	Targets []targetDiff
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	oldEntries, err := loadSavedResults(args[0])
+	if err != nil {
+		return withExitCode(ExitInvalidInput, err)
+	}
+	newEntries, err := loadSavedResults(args[1])
+	if err != nil {
+		return withExitCode(ExitInvalidInput, err)
+	}
+
+	report := diffReport{Old: args[0], New: args[1], Targets: diffSavedResults(oldEntries, newEntries)}
+	outw := cmd.OutOrStdout()
+	if boolFlag(cmd, "json") {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return withExitCode(ExitInternalError, err)
+		}
+		fmt.Fprintln(outw, string(data))
+	} else {
+		renderDiffReport(outw, report, useColor(appFlags{noColor: boolFlag(cmd, "no-color")}, outw))
+	}
+
+	// Differences exit like diff(1)'s "files differ", with the code that
+	// otherwise means "found, with warnings".
+	for _, td := range report.Targets {
+		if td.Only != "" || len(td.Changes) > 0 {
+			cmd.SilenceErrors = true
+			return withExitCode(ExitWarnings, fmt.Errorf("results differ"))
+		}
+	}
+	return nil
+}
+
+// loadSavedResults reads a `witr --json` document: a versioned report, or
+// from older releases one bare result object or the array written for
+// several targets.
+func loadSavedResults(path string) ([]savedEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []json.RawMessage
+	var failed []model.TargetError
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	} else {
+		var report struct {
+			SchemaVersion *int
+			Results       []json.RawMessage
+			Errors        []model.TargetError
+		}
+		if err := json.Unmarshal(data, &report); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if report.SchemaVersion == nil {
+			raw = []json.RawMessage{data}
+		} else {
+			if *report.SchemaVersion > model.SchemaVersion {
+				return nil, fmt.Errorf("%s: schema version %d is newer than this witr supports (%d); upgrade witr", path, *report.SchemaVersion, model.SchemaVersion)
+			}
+			raw, failed = report.Results, report.Errors
+		}
+	}
+
+	entries := make([]savedEntry, len(raw), len(raw)+len(failed))
+	for i, r := range raw {
+		var probe map[string]json.RawMessage
+		if err := json.Unmarshal(r, &probe); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		_, hasProcess := probe["Process"]
+		_, hasError := probe["Error"]
+		if !hasProcess && !hasError {
+			return nil, fmt.Errorf("%s: entry %d is not a full witr --json result (--short, --tree, --warnings and --env output cannot be compared)", path, i+1)
+		}
+		if err := json.Unmarshal(r, &entries[i]); err != nil {
+			return nil, fmt.Errorf("%s: entry %d: %w", path, i+1, err)
+		}
+	}
+	for _, e := range failed {
+		entries = append(entries, savedEntry{Result: model.Result{Target: e.Target}, Error: e.Error})
+	}
+	return entries, nil
+}
+
+// diffSavedResults pairs the entries of two documents by target, in the old
+// document's order, and compares each pair. Two single-result documents are
+// compared whatever their targets, so `--pid 812` can be compared with a
+// later `--pid 901`.
+func diffSavedResults(old, new []savedEntry) []targetDiff {
+	if len(old) == 1 && len(new) == 1 {
+		return []targetDiff{diffSavedEntry(old[0], new[0])}
+	}
+
+	unmatched := make(map[model.Target][]int)
+	for i, e := range new {
+		unmatched[e.Target] = append(unmatched[e.Target], i)
+	}
+	used := make([]bool, len(new))
+
+	var out []targetDiff
+	for _, o := range old {
+		idx := unmatched[o.Target]
+		if len(idx) == 0 {
+			out = append(out, targetDiff{Target: o.Target, Only: "old"})
+			continue
+		}
+		unmatched[o.Target] = idx[1:]
+		used[idx[0]] = true
+		out = append(out, diffSavedEntry(o, new[idx[0]]))
+	}
+	for i, n := range new {
+		if !used[i] {
+			out = append(out, targetDiff{Target: n.Target, Only: "new"})
+		}
+	}
+	return out
+}
+
+func diffSavedEntry(old, new savedEntry) targetDiff {
+	td := targetDiff{Target: new.Target}
+	if old.Error == "" && new.Error == "" {
+		td.Changes = append(diff.Results(old.Result, new.Result), diff.Resources(old.Result, new.Result)...)
+		return td
+	}
+
+	// A lookup that failed on either side has nothing to compare field by
+	// field; report the error and the process on the side that has one.
+	describe := func(e savedEntry) string {
+		if e.Error != "" {
+			return ""
+		}
+		return fmt.Sprintf("%s (pid %d)", output.ChainName(e.Process), e.Process.PID)
+	}
+	for _, c := range []diff.Change{
+		{Field: "Error", Old: old.Error, New: new.Error},
+		{Field: "Process", Old: describe(old), New: describe(new)},
+	} {
+		switch {
+		case c.Old == c.New:
+			continue
+		case c.Old == "":
+			c.Kind = diff.Added
+		case c.New == "":
+			c.Kind = diff.Removed
+		default:
+			c.Kind = diff.Changed
+		}
+		td.Changes = append(td.Changes, c)
+	}
+	return td
+}
+
+func renderDiffReport(w io.Writer, r diffReport, colorEnabled bool) {
+	outp := output.NewPrinter(w)
+	if colorEnabled {
+		outp.Printf("%s--- %s%s\n%s+++ %s%s\n", output.ColorRed, r.Old, output.ColorReset, output.ColorGreen, r.New, output.ColorReset)
+	} else {
+		outp.Printf("--- %s\n+++ %s\n", r.Old, r.New)
+	}
+
+	for _, td := range r.Targets {
+		label := targetLabel(td.Target)
+		if colorEnabled {
+			outp.Printf("\n%s[%s]%s", output.ColorCyan, label, output.ColorReset)
+		} else {
+			outp.Printf("\n[%s]", label)
+		}
+		switch {
+		case td.Only == "old":
+			outp.Printf(" only in %s\n", r.Old)
+		case td.Only == "new":
+			outp.Printf(" only in %s\n", r.New)
+		case len(td.Changes) == 0:
+			outp.Printf("\n  No differences.\n")
+		default:
+			outp.Printf("\n")
+			for _, c := range td.Changes {
+				output.RenderChange(w, c, colorEnabled)
+			}
+		}
+	}
+}
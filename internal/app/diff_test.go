@@ -0,0 +1,150 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/internal/diff"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func writeJSON(t *testing.T, name string, v any) string {
+	t.Helper()
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func savedResult(target model.Target, pid int, env ...string) model.Result {
+	proc := model.Process{PID: pid, Command: "nginx", Env: env, MemoryRSS: 10 << 20}
+	return model.Result{
+		Target:   target,
+		Process:  proc,
+		Ancestry: []model.Process{{PID: 1, Command: "systemd"}, proc},
+		Source:   model.Source{Type: model.SourceSystemd, Name: "nginx.service"},
+	}
+}
+
+func TestLoadSavedResults(t *testing.T) {
+	port := model.Target{Type: model.TargetPort, Value: "8080"}
+
+	single := writeJSON(t, "single.json", savedResult(port, 812))
+	got, err := loadSavedResults(single)
+	if err != nil || len(got) != 1 || got[0].Process.PID != 812 {
+		t.Fatalf("single result: got %+v, %v", got, err)
+	}
+
+	multi := writeJSON(t, "multi.json", []any{
+		savedResult(port, 812),
+		map[string]any{"Target": model.Target{Type: model.TargetName, Value: "ghost"}, "Error": "no matching process found"},
+	})
+	got, err = loadSavedResults(multi)
+	if err != nil || len(got) != 2 || got[1].Error == "" || got[1].Target.Value != "ghost" {
+		t.Fatalf("multi result: got %+v, %v", got, err)
+	}
+
+	report := writeJSON(t, "report.json", model.Report{
+		SchemaVersion: model.SchemaVersion,
+		Results:       []model.Result{savedResult(port, 812)},
+		Errors:        []model.TargetError{{Target: model.Target{Type: model.TargetName, Value: "ghost"}, Error: "no matching process found"}},
+	})
+	got, err = loadSavedResults(report)
+	if err != nil || len(got) != 2 || got[0].Process.PID != 812 || got[1].Target.Value != "ghost" || got[1].Error == "" {
+		t.Fatalf("report: got %+v, %v", got, err)
+	}
+
+	future := writeJSON(t, "future.json", model.Report{SchemaVersion: model.SchemaVersion + 1})
+	if _, err := loadSavedResults(future); err == nil || !strings.Contains(err.Error(), "upgrade witr") {
+		t.Errorf("a newer schema version should be rejected, got %v", err)
+	}
+
+	short := writeJSON(t, "short.json", []map[string]any{{"PID": 1, "Command": "systemd"}})
+	if _, err := loadSavedResults(short); err == nil || !strings.Contains(err.Error(), "not a full witr --json result") {
+		t.Errorf("--short output should be rejected, got %v", err)
+	}
+}
+
+func TestDiffSavedResults(t *testing.T) {
+	port := model.Target{Type: model.TargetPort, Value: "8080"}
+	name := model.Target{Type: model.TargetName, Value: "redis"}
+	gone := model.Target{Type: model.TargetPID, Value: "77"}
+
+	before := []savedEntry{
+		{Result: savedResult(port, 812, "MODE=blue")},
+		{Result: savedResult(name, 300)},
+		{Result: savedResult(gone, 77)},
+	}
+	afterPort := savedResult(port, 901, "MODE=green")
+	afterPort.Process.MemoryRSS = 12 << 20
+	after := []savedEntry{
+		{Result: savedResult(name, 300)},
+		{Result: afterPort},
+		{Result: model.Result{Target: model.Target{Type: model.TargetPID, Value: "9"}}, Error: "no matching process found"},
+	}
+
+	got := diffSavedResults(before, after)
+	if len(got) != 4 {
+		t.Fatalf("want 4 target diffs, got %+v", got)
+	}
+	if got[0].Target != port || !hasChange(got[0].Changes, "Process.PID", "901") ||
+		!hasChange(got[0].Changes, "Process.Env.MODE", "green") || !hasChange(got[0].Changes, "Process.MemoryRSS", "12.0 MB") {
+		t.Errorf("port diff missing changes: %+v", got[0])
+	}
+	if got[1].Target != name || len(got[1].Changes) != 0 {
+		t.Errorf("unchanged target should have no changes: %+v", got[1])
+	}
+	if got[2].Only != "old" || got[3].Only != "new" {
+		t.Errorf("unpaired targets: %+v, %+v", got[2], got[3])
+	}
+}
+
+func TestDiffSavedEntryError(t *testing.T) {
+	pid := model.Target{Type: model.TargetPID, Value: "812"}
+	td := diffSavedEntry(savedEntry{Result: savedResult(pid, 812)}, savedEntry{Result: model.Result{Target: pid}, Error: "no matching process found"})
+	want := []diff.Change{
+		{Field: "Error", Kind: diff.Added, New: "no matching process found"},
+		{Field: "Process", Kind: diff.Removed, Old: "nginx (pid 812)"},
+	}
+	if len(td.Changes) != len(want) || td.Changes[0] != want[0] || td.Changes[1] != want[1] {
+		t.Errorf("diffSavedEntry() = %+v, want %+v", td.Changes, want)
+	}
+}
+
+func TestRenderDiffReport(t *testing.T) {
+	var buf bytes.Buffer
+	renderDiffReport(&buf, diffReport{
+		Old: "a.json",
+		New: "b.json",
+		Targets: []targetDiff{
+			{Target: model.Target{Type: model.TargetPort, Value: "8080"}, Changes: []diff.Change{{Field: "Process.PID", Kind: diff.Changed, Old: "1", New: "2"}}},
+			{Target: model.Target{Type: model.TargetName, Value: "redis"}},
+			{Target: model.Target{Type: model.TargetPID, Value: "9"}, Only: "new"},
+		},
+	}, false)
+	want := "--- a.json\n+++ b.json\n" +
+		"\n[port: 8080]\n  ~ Process.PID: 1 → 2\n" +
+		"\n[name: redis]\n  No differences.\n" +
+		"\n[pid: 9] only in b.json\n"
+	if buf.String() != want {
+		t.Errorf("renderDiffReport() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func hasChange(changes []diff.Change, field, newValue string) bool {
+	for _, c := range changes {
+		if c.Field == field && c.New == newValue {
+			return true
+		}
+	}
+	return false
+}
@@ -6,6 +6,8 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
 )
 
 // buildWitr compiles the real witr binary once so the process exit codes can be
@@ -64,7 +66,20 @@ func TestExitCodes(t *testing.T) {
 		{"invalid pid (non-numeric)", []string{"--pid", "notanumber"}, ExitInvalidInput},
 		{"invalid pid (zero)", []string{"--pid", "0"}, ExitInvalidInput},
 		{"invalid port (out of range)", []string{"--port", "70000"}, ExitInvalidInput},
+		{"invalid port range", []string{"--port", "9000-8000"}, ExitInvalidInput},
+		{"unknown service name", []string{"--port", "witr-no-such-service"}, ExitInvalidInput},
 		{"not found (ghost pid)", []string{"--pid", ghostPID}, ExitNotFound},
+		{"invalid --fail-on", []string{"--fail-on", "severe", "--pid", "1"}, ExitInvalidInput},
+		{"negative --timeout", []string{"--timeout", "-1s", "--pid", "1"}, ExitInvalidInput},
+		{"--summary with --short", []string{"--summary", "--short", "--pid", "1"}, ExitInvalidInput},
+		{"--watch with --all", []string{"--watch", "--all", "--pid", "1"}, ExitInvalidInput},
+		{"invalid --regex", []string{"--regex", "("}, ExitInvalidInput},
+		{"--clients without --socket", []string{"--clients", "--pid", "1"}, ExitInvalidInput},
+		{"invalid --netns", []string{"--netns", "witr-no-such-netns", "--pid", "1"}, ExitInvalidInput},
+		{"invalid --source", []string{"--source", "upstart", "--pid", "1"}, ExitInvalidInput},
+		{"--with-env without a key", []string{"--with-env", "=x", "--pid", "1"}, ExitInvalidInput},
+		{"selector keeps nothing", []string{"--pid", "1", "--with-env", "WITR_NO_SUCH_VARIABLE"}, ExitNotFound},
+		{"invalid proc root", []string{"--proc-root", "/nonexistent-witr-root", "--pid", "1"}, ExitInvalidInput},
 		// Multi-target exit code is the highest severity among targets, not the
 		// first or last — assert with both orderings of a not-found(2) and an
 		// invalid(4) target.
@@ -81,3 +96,34 @@ func TestExitCodes(t *testing.T) {
 		})
 	}
 }
+
+func TestWarningsExit(t *testing.T) {
+	t.Parallel()
+
+	res := sampleResult() // one low-severity W_ROOT warning
+	tests := []struct {
+		failOn model.Severity
+		want   int
+	}{
+		{"", ExitWarnings}, // default: any warning
+		{model.SeverityInfo, ExitWarnings},
+		{model.SeverityLow, ExitWarnings},
+		{model.SeverityMedium, ExitOK},
+		{model.SeverityHigh, ExitOK},
+		{failOnNone, ExitOK},
+	}
+	for _, tc := range tests {
+		if got := warningsExit(res, appFlags{failOn: tc.failOn}); got != tc.want {
+			t.Errorf("--fail-on=%q: exit %d, want %d", tc.failOn, got, tc.want)
+		}
+	}
+
+	if got := warningsExit(model.Result{}, appFlags{}); got != ExitOK {
+		t.Errorf("no warnings: exit %d, want %d", got, ExitOK)
+	}
+	// A warning saved without a severity always counts.
+	legacy := model.Result{Warnings: []model.Warning{{Message: "Process is running as root"}}}
+	if got := warningsExit(legacy, appFlags{failOn: model.SeverityHigh}); got != ExitWarnings {
+		t.Errorf("warning without severity: exit %d, want %d", got, ExitWarnings)
+	}
+}
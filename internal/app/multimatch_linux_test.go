@@ -0,0 +1,51 @@
+//go:build linux
+
+package app
+
+import (
+	"bytes"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	"github.com/pranshuparmar/witr/internal/procfs"
+)
+
+func TestPrintMultiMatchNetns(t *testing.T) {
+	ns := func(inode string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte("net:[" + inode + "]"), Mode: fs.ModeSymlink}
+	}
+	tree := fstest.MapFS{
+		"proc/self/ns/net": ns("4026531840"),
+		"proc/310/stat":    {Data: []byte("310 (nginx) S 1 310 310 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 500 0 0\n")},
+		"proc/310/ns/net":  ns("4026531840"),
+		"proc/4242/stat":   {Data: []byte("4242 (node) S 1 4242 4242 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 900 0 0\n")},
+		"proc/4242/ns/net": ns("4026532289"),
+		"proc/4243/stat":   {Data: []byte("4243 (node) S 1 4243 4243 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 900 0 0\n")},
+		"proc/4243/ns/net": ns("4026532290"),
+	}
+	prev, prevLive := procfs.Current(), procfs.Live()
+	procfs.Set(procfs.FromFS(tree), false)
+	t.Cleanup(func() { procfs.Set(prev, prevLive) })
+
+	if spansNetns([]int{310}) || !spansNetns([]int{310, 4242}) || !spansNetns([]int{4242, 4243}) {
+		t.Error("spansNetns should be true only for PIDs in different namespaces")
+	}
+
+	var buf bytes.Buffer
+	printMultiMatch(output.NewPrinter(&buf), []int{310, 4242, 4243}, false,
+		"witr --pid <pid>", "witr --port 8080 --netns <netns>")
+	out := buf.String()
+	for _, want := range []string{
+		"(pid 310)\n",
+		"(pid 4242, netns 4026532289)\n",
+		"(pid 4243, netns 4026532290)\n",
+		"  witr --pid <pid>\n  witr --port 8080 --netns <netns>\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("printMultiMatch output missing %q:\n%s", want, out)
+		}
+	}
+}
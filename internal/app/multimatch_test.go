@@ -2,6 +2,8 @@ package app
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"os"
 	"strings"
 	"testing"
@@ -43,3 +45,93 @@ func TestPrintContainerMultiMatch(t *testing.T) {
 		}
 	}
 }
+
+func TestProcessAllMatches(t *testing.T) {
+	self, parent := os.Getpid(), os.Getppid()
+	name := model.Target{Type: model.TargetName, Value: "witr-test"}
+	flags := appFlags{all: true, failOn: failOnNone}
+
+	var buf bytes.Buffer
+	code := processAllMatches(context.Background(), &buf, output.NewPrinter(&buf), name,
+		[]int{self, parent}, flags, false, newJSONOutput(flags))
+	out := buf.String()
+	if code != ExitOK {
+		t.Errorf("exit %d, want %d", code, ExitOK)
+	}
+	for _, want := range []string{
+		fmt.Sprintf(", pid %d] -----\n", self),
+		fmt.Sprintf(", pid %d] -----\n", parent),
+		"Summary     : 2 matching processes\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("--all output missing %q:\n%s", want, out)
+		}
+	}
+
+	// A match that is gone is reported; the others are still analyzed.
+	buf.Reset()
+	summary := flags
+	summary.summary = true
+	code = processAllMatches(context.Background(), &buf, output.NewPrinter(&buf), name,
+		[]int{self, 2147483646}, summary, false, newJSONOutput(summary))
+	out = buf.String()
+	if code != ExitNotFound {
+		t.Errorf("exit %d with a vanished match, want %d", code, ExitNotFound)
+	}
+	if !strings.Contains(out, "Error: pid 2147483646:") || !strings.Contains(out, "Summary     : 1 matching process\n") {
+		t.Errorf("--summary output:\n%s", out)
+	}
+	if strings.Contains(out, "-----") {
+		t.Errorf("--summary should show no full results:\n%s", out)
+	}
+
+	// The JSON report has a result per match and the grouping.
+	buf.Reset()
+	asJSON := flags
+	asJSON.json = true
+	jo := newJSONOutput(asJSON)
+	processAllMatches(context.Background(), &buf, output.NewPrinter(&buf), name,
+		[]int{self, parent}, asJSON, false, jo)
+	if buf.Len() != 0 || len(jo.results) != 2 || len(jo.summaries) != 1 {
+		t.Fatalf("JSON: output %q, %d results, %d summaries; want nothing written yet, 2 and 1", buf.String(), len(jo.results), len(jo.summaries))
+	}
+	groups := jo.summaries[0].Groups
+	n := 0
+	for _, g := range groups {
+		n += len(g.PIDs)
+	}
+	if jo.summaries[0].Target != name || n != 2 {
+		t.Errorf("summary %+v, want both matches of %v", jo.summaries[0], name)
+	}
+}
+
+func TestProcessPickerItems(t *testing.T) {
+	self := os.Getpid()
+	items := processPickerItems(context.Background(), []int{self, 2147483646})
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want one per PID", len(items))
+	}
+	if it := items[0]; it.PID != self || it.Cmdline == "" || it.Source == "" {
+		t.Errorf("item for witr itself = %+v, want its command line and parents", it)
+	}
+	if it := items[1]; it.PID != 2147483646 || it.User != "" || it.Source != "" {
+		t.Errorf("a vanished PID should still be listed, bare: %+v", it)
+	}
+
+	lineage := []model.Process{{PID: 1, Command: "systemd"}, {PID: 812, Cmdline: "sshd: alice"}, {PID: 4211, Command: "bash"}}
+	if got := parentsLabel(lineage); got != "systemd → sshd: alice" {
+		t.Errorf("parentsLabel = %q", got)
+	}
+	if got := parentsLabel(lineage[:1]); got != "" {
+		t.Errorf("parentsLabel of a process with no parent = %q, want empty", got)
+	}
+}
+
+func TestCanPick(t *testing.T) {
+	// Test output is never a terminal, so the picker never runs here and
+	// the list is printed as before.
+	var buf bytes.Buffer
+	if canPick(&buf, appFlags{}, false) {
+		t.Error("canPick should be false when output is not a terminal")
+	}
+}
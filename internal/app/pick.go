@@ -0,0 +1,103 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/tui"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// canPick reports whether a lookup that matched several processes may ask
+// which one to analyze: only when a person is at the terminal on both ends,
+// and never for --json or a run with several targets.
+func canPick(outw io.Writer, flags appFlags, multiMode bool) bool {
+	return !flags.json && !multiMode && isTerminal(os.Stdin) && isTerminal(outw)
+}
+
+// pickProcess asks which of pids to analyze. ok is false when the user
+// cancelled; err is set when the picker could not run, for the caller to
+// fall back to listing the matches.
+func pickProcess(ctx context.Context, t model.Target, pids []int) (pid int, ok bool, err error) {
+	i, err := tui.Pick(ctx, pickTitle(t, len(pids), "processes"), processPickerItems(ctx, pids))
+	if err != nil || i < 0 {
+		return 0, false, err
+	}
+	return pids[i], true, nil
+}
+
+// pickContainer is pickProcess for a container lookup.
+func pickContainer(ctx context.Context, t model.Target, matches []*model.ContainerMatch) (match *model.ContainerMatch, ok bool, err error) {
+	i, err := tui.Pick(ctx, pickTitle(t, len(matches), "containers"), containerPickerItems(ctx, matches))
+	if err != nil || i < 0 {
+		return nil, false, err
+	}
+	return matches[i], true, nil
+}
+
+func pickTitle(t model.Target, n int, noun string) string {
+	return fmt.Sprintf("%d %s match %q — pick one to analyze", n, noun, t.Value)
+}
+
+// processPickerItems describes pids for the picker, reading each process
+// through the run's index so the analysis of the chosen one does not read it
+// again. A match's source is only detected once it is chosen; the list names
+// its parent processes from the process table instead.
+func processPickerItems(ctx context.Context, pids []int) []tui.PickerItem {
+	idx := procpkg.IndexFrom(ctx)
+	items := make([]tui.PickerItem, 0, len(pids))
+	for _, pid := range pids {
+		item := tui.PickerItem{PID: pid}
+		p, err := idx.Process(pid)
+		if err != nil {
+			item.Cmdline = idx.Cmdline(pid)
+			items = append(items, item)
+			continue
+		}
+		item.User = p.User
+		item.Started = p.StartedAt
+		item.Cmdline = p.Cmdline
+		if lineage, err := idx.Lineage(pid); err == nil {
+			item.Source = parentsLabel(lineage)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// containerPickerItems describes matches for the picker. The PID is the
+// container's main process on the host, when it is visible.
+func containerPickerItems(ctx context.Context, matches []*model.ContainerMatch) []tui.PickerItem {
+	items := make([]tui.PickerItem, 0, len(matches))
+	for _, m := range matches {
+		cmdline := m.Command
+		if cmdline == "" {
+			cmdline = m.Image
+		}
+		items = append(items, tui.PickerItem{
+			PID:     procpkg.ResolveContainerHostPID(ctx, m.Runtime, m.ID),
+			Started: m.StartedAt,
+			Source:  fmt.Sprintf("%s (%s, %s)", m.Name, m.Runtime, m.Image),
+			Cmdline: cmdline,
+		})
+	}
+	return items
+}
+
+// parentsLabel names the parents in lineage, oldest first, the way the
+// short output chains them: "systemd → sshd → bash". The match itself, last
+// in lineage, is left out.
+func parentsLabel(lineage []model.Process) string {
+	names := make([]string, 0, len(lineage))
+	for _, p := range lineage[:len(lineage)-1] {
+		names = append(names, output.ChainName(p))
+	}
+	return strings.Join(names, " → ")
+}
@@ -0,0 +1,94 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"context"
+	"io"
+	"slices"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/source"
+	"github.com/pranshuparmar/witr/internal/target"
+	"github.com/pranshuparmar/witr/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+// processPortRange handles a --port target covering a range. Rather than
+// explaining one process, it lists every port in the range that a process
+// is listening on or bound to, with the owner and what started it.
+func processPortRange(ctx context.Context, cmd *cobra.Command, outw io.Writer, outp output.Printer, t model.Target, spec target.PortSpec, flags appFlags, multiMode bool, jo *jsonOutput) int {
+	bound, err := target.BoundPorts(ctx, spec)
+	if err == nil {
+		bound, err = filterBoundPorts(ctx, bound, flags)
+	}
+	if err != nil {
+		return handleResolveError(ctx, cmd, outw, outp, t, err, flags, multiMode, jo)
+	}
+
+	table := buildPortTable(ctx, t, bound)
+	switch {
+	case jo.report:
+		jo.addPortTable(table)
+	default:
+		output.RenderPortTable(outw, table, useColor(flags, outw))
+	}
+	return ExitOK
+}
+
+// filterBoundPorts keeps the ports whose owner the selector flags keep.
+func filterBoundPorts(ctx context.Context, bound []model.OpenPort, flags appFlags) ([]model.OpenPort, error) {
+	if flags.sel.Empty() {
+		return bound, nil
+	}
+	var pids []int
+	for _, p := range bound {
+		if !slices.Contains(pids, p.PID) {
+			pids = append(pids, p.PID)
+		}
+	}
+	slices.Sort(pids)
+	kept, err := target.Filter(ctx, pids, flags.sel, flags.exact)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(bound, func(p model.OpenPort) bool {
+		return !slices.Contains(kept, p.PID)
+	}), nil
+}
+
+// buildPortTable names the owner of each bound port and the source that
+// started it. Owners holding several ports are traced once.
+func buildPortTable(ctx context.Context, t model.Target, bound []model.OpenPort) model.PortTable {
+	idx := procpkg.IndexFrom(ctx)
+	type owner struct {
+		name string
+		src  model.Source
+	}
+	owners := make(map[int]owner)
+
+	table := model.PortTable{Target: t, Ports: make([]model.BoundPort, 0, len(bound))}
+	for _, p := range bound {
+		o, ok := owners[p.PID]
+		if !ok {
+			o = owner{name: "unknown", src: model.Source{Type: model.SourceUnknown}}
+			if ancestry, err := idx.Ancestry(p.PID); err == nil && len(ancestry) > 0 {
+				o.name = ancestry[len(ancestry)-1].Command
+				o.src = source.Detect(ctx, ancestry)
+			}
+			owners[p.PID] = o
+		}
+		table.Ports = append(table.Ports, model.BoundPort{
+			Port:       p.Port,
+			Protocol:   p.Protocol,
+			Address:    p.Address,
+			PID:        p.PID,
+			Process:    o.name,
+			SourceType: o.src.Type,
+			SourceName: o.src.Name,
+			Netns:      p.Netns,
+		})
+	}
+	return table
+}
@@ -13,41 +13,46 @@ func sampleResult() model.Result {
 		Process:  model.Process{PID: 1234, Command: "nginx", Cmdline: "nginx -g daemon off;"},
 		Ancestry: []model.Process{{PID: 1, Command: "systemd"}, {PID: 1234, Command: "nginx"}},
 		Source:   model.Source{Type: model.SourceSystemd, Name: "nginx.service"},
-		Warnings: []string{"Process is running as root"},
+		Warnings: []model.Warning{{Code: model.WarnRoot, Severity: model.SeverityLow, Message: "Process is running as root"}},
 	}
 }
 
 // TestRenderResultDispatch pins the output-mode routing in renderResult: which
-// renderer each flag selects, and that multi-target JSON accumulates into the
-// shared slice instead of writing to the output stream.
+// renderer each flag selects, and that JSON, in any view, accumulates into
+// the report instead of writing to the output stream.
 func TestRenderResultDispatch(t *testing.T) {
 	t.Parallel()
 	res := sampleResult()
 
 	// Standard (no flags) writes a human report containing the process name.
 	var std bytes.Buffer
-	var jr []string
-	renderResult(&std, res, appFlags{}, false, &jr)
+	jo := newJSONOutput(appFlags{})
+	renderResult(&std, res, appFlags{}, false, jo)
 	if !strings.Contains(std.String(), "nginx") {
 		t.Errorf("standard output missing process name:\n%s", std.String())
 	}
 
-	// JSON single-target writes serialized output containing the PID.
+	// Plain JSON collects the result into the report, even for one target,
+	// and writes nothing until the report is written.
 	var js bytes.Buffer
-	renderResult(&js, res, appFlags{json: true}, false, &jr)
-	if !strings.Contains(js.String(), "1234") {
-		t.Errorf("json output missing pid:\n%s", js.String())
+	jo = newJSONOutput(appFlags{json: true})
+	renderResult(&js, res, appFlags{json: true}, false, jo)
+	if len(jo.results) != 1 || js.Len() != 0 {
+		t.Errorf("json report: got %d collected results and output %q, want 1 and none", len(jo.results), js.String())
+	}
+	if err := jo.write(&js); err != nil || !strings.Contains(js.String(), "1234") {
+		t.Errorf("json report missing pid (err %v):\n%s", err, js.String())
 	}
 
-	// JSON multi-target accumulates into jsonResults and does not write to outw.
-	jr = nil
-	var jm bytes.Buffer
-	renderResult(&jm, res, appFlags{json: true}, true, &jr)
-	if len(jr) != 1 {
-		t.Errorf("multi-target json: got %d accumulated results, want 1", len(jr))
+	// A JSON view collects the result in its own shape, in the same report.
+	var sv bytes.Buffer
+	jo = newJSONOutput(appFlags{json: true, short: true})
+	renderResult(&sv, res, appFlags{json: true, short: true}, false, jo)
+	if len(jo.shortViews) != 1 || len(jo.results) != 0 || sv.Len() != 0 {
+		t.Errorf("short json: got %d short views, %d results and output %q, want 1, 0 and none", len(jo.shortViews), len(jo.results), sv.String())
 	}
-	if jm.Len() != 0 {
-		t.Errorf("multi-target json must not write to outw, got: %s", jm.String())
+	if err := jo.write(&sv); err != nil || !strings.Contains(sv.String(), `"ShortViews"`) || !strings.Contains(sv.String(), `"SchemaVersion"`) {
+		t.Errorf("short json report (err %v):\n%s", err, sv.String())
 	}
 
 	// Each non-JSON mode produces some output.
@@ -58,7 +63,7 @@ func TestRenderResultDispatch(t *testing.T) {
 	}
 	for name, f := range modes {
 		var b bytes.Buffer
-		renderResult(&b, res, f, false, &jr)
+		renderResult(&b, res, f, false, newJSONOutput(f))
 		if b.Len() == 0 {
 			t.Errorf("%s mode produced no output", name)
 		}
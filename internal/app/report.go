@@ -0,0 +1,144 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// jsonOutput gathers --json output across targets into one versioned
+// model.Report, written once at the end even for a single target. The
+// --short, --tree, --warnings and --env views put each analyzed process in
+// their compact shape instead of a full result; errors, --all summaries and
+// port tables are the same in every view.
+type jsonOutput struct {
+	report bool // --json was given, so the output is a model.Report
+	view   view
+
+	results      []model.Result
+	errors       []model.TargetError
+	summaries    []model.MatchSummary
+	portTables   []model.PortTable
+	shortViews   []model.ShortView
+	treeViews    []model.TreeView
+	warningViews []model.WarningsView
+	envViews     []model.EnvView
+}
+
+// view is the compact --json view a run asked for, or viewFull.
+type view int
+
+const (
+	viewFull view = iota
+	viewShort
+	viewTree
+	viewWarnings
+	viewEnv
+)
+
+func newJSONOutput(flags appFlags) *jsonOutput {
+	j := &jsonOutput{report: flags.json}
+	// The same precedence as the text output.
+	switch {
+	case flags.env:
+		j.view = viewEnv
+	case flags.short:
+		j.view = viewShort
+	case flags.tree:
+		j.view = viewTree
+	case flags.warn:
+		j.view = viewWarnings
+	}
+	return j
+}
+
+// addResult records an analyzed process, in the run's view.
+func (j *jsonOutput) addResult(res model.Result) {
+	switch j.view {
+	case viewShort:
+		j.shortViews = append(j.shortViews, output.ShortViewOf(res))
+	case viewTree:
+		j.treeViews = append(j.treeViews, output.TreeViewOf(res))
+	case viewWarnings:
+		j.warningViews = append(j.warningViews, output.WarningsViewOf(res))
+	case viewEnv:
+		j.envViews = append(j.envViews, output.EnvViewOf(res))
+	default:
+		j.results = append(j.results, res)
+	}
+}
+
+// addContainer records a container t matched whose processes are not
+// visible from this host: a result with only Container set, whatever the
+// view, since no view has a process to show.
+func (j *jsonOutput) addContainer(t model.Target, match *model.ContainerMatch) {
+	j.results = append(j.results, output.ContainerFallbackResult(t, match))
+}
+
+func (j *jsonOutput) addSummary(s model.MatchSummary) {
+	j.summaries = append(j.summaries, s)
+}
+
+// addError records a target that could not be analyzed; pids lists the
+// candidates of an ambiguous target.
+func (j *jsonOutput) addError(t model.Target, msg string, pids []int) {
+	j.errors = append(j.errors, model.TargetError{Target: t, Error: msg, PIDs: pids})
+}
+
+// write prints the report, when --json was given.
+func (j *jsonOutput) write(w io.Writer) error {
+	if !j.report {
+		return nil
+	}
+	data, err := json.MarshalIndent(j.buildReport(), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// buildReport wraps the collected results in the report envelope. The
+// timestamp is when the analyzed state was observed: now, or when the
+// --snapshot being replayed was captured.
+func (j *jsonOutput) buildReport() model.Report {
+	hostname, kernel := procpkg.HostIdentity()
+	ts := replayTime
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	r := model.Report{
+		SchemaVersion: model.SchemaVersion,
+		WitrVersion:   version,
+		Hostname:      hostname,
+		Kernel:        kernel,
+		Timestamp:     ts,
+		Results:       j.results,
+		Errors:        j.errors,
+		Summaries:     j.summaries,
+		PortTables:    j.portTables,
+		ShortViews:    j.shortViews,
+		TreeViews:     j.treeViews,
+		WarningViews:  j.warningViews,
+		EnvViews:      j.envViews,
+	}
+	// Empty lists are [], not null, so consumers can always range over them.
+	if r.Results == nil {
+		r.Results = []model.Result{}
+	}
+	if r.Errors == nil {
+		r.Errors = []model.TargetError{}
+	}
+	return r
+}
+
+func (j *jsonOutput) addPortTable(t model.PortTable) {
+	j.portTables = append(j.portTables, t)
+}
@@ -2,8 +2,12 @@ package app
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/pranshuparmar/witr/internal/output"
 	"github.com/pranshuparmar/witr/internal/target"
@@ -22,11 +26,10 @@ func TestHandleResolveError(t *testing.T) {
 
 	t.Run("generic not-found maps to ExitNotFound", func(t *testing.T) {
 		var outw bytes.Buffer
-		var jsonResults []string
-		code := handleResolveError(newCmd(), &outw, output.NewPrinter(&outw),
+		code := handleResolveError(context.Background(), newCmd(), &outw, output.NewPrinter(&outw),
 			model.Target{Type: model.TargetName, Value: "ghost"},
 			errors.New("no matching process found"),
-			appFlags{}, false, &jsonResults)
+			appFlags{}, false, newJSONOutput(appFlags{}))
 		if code != ExitNotFound {
 			t.Errorf("code = %d, want %d (ExitNotFound)", code, ExitNotFound)
 		}
@@ -34,25 +37,53 @@ func TestHandleResolveError(t *testing.T) {
 
 	t.Run("unsupported target maps to ExitInvalidInput", func(t *testing.T) {
 		var outw bytes.Buffer
-		var jsonResults []string
-		code := handleResolveError(newCmd(), &outw, output.NewPrinter(&outw),
+		code := handleResolveError(context.Background(), newCmd(), &outw, output.NewPrinter(&outw),
 			model.Target{Type: model.TargetFile, Value: "/x"},
 			target.ErrUnsupported,
-			appFlags{}, false, &jsonResults)
+			appFlags{}, false, newJSONOutput(appFlags{}))
 		if code != ExitInvalidInput {
 			t.Errorf("code = %d, want %d (ExitInvalidInput)", code, ExitInvalidInput)
 		}
 	})
 
-	t.Run("multi-mode JSON appends an error entry", func(t *testing.T) {
+	t.Run("JSON view records the error in the report", func(t *testing.T) {
 		var outw bytes.Buffer
-		var jsonResults []string
-		handleResolveError(newCmd(), &outw, output.NewPrinter(&outw),
+		jo := newJSONOutput(appFlags{json: true, short: true})
+		handleResolveError(context.Background(), newCmd(), &outw, output.NewPrinter(&outw),
 			model.Target{Type: model.TargetName, Value: "ghost"},
 			errors.New("no matching process found"),
-			appFlags{json: true}, true, &jsonResults)
-		if len(jsonResults) != 1 {
-			t.Errorf("expected 1 JSON error entry, got %d", len(jsonResults))
+			appFlags{json: true, short: true}, true, jo)
+		if len(jo.errors) != 1 || outw.Len() != 0 {
+			t.Errorf("errors %+v, output %q; want one error and no output", jo.errors, outw.String())
+		}
+	})
+
+	t.Run("JSON report records the error, even for one target", func(t *testing.T) {
+		var outw bytes.Buffer
+		jo := newJSONOutput(appFlags{json: true})
+		ghost := model.Target{Type: model.TargetName, Value: "ghost"}
+		code := handleResolveError(context.Background(), newCmd(), &outw, output.NewPrinter(&outw),
+			ghost, errors.New("no matching process found"),
+			appFlags{json: true}, false, jo)
+		if code != ExitNotFound || len(jo.errors) != 1 || jo.errors[0].Target != ghost || outw.Len() != 0 {
+			t.Errorf("code %d, errors %+v, output %q", code, jo.errors, outw.String())
+		}
+	})
+
+	t.Run("timeout says so instead of not found", func(t *testing.T) {
+		var outw, errBuf bytes.Buffer
+		cmd := &cobra.Command{}
+		cmd.SetErr(&errBuf)
+		code := handleResolveError(context.Background(), cmd, &outw, output.NewPrinter(&outw),
+			model.Target{Type: model.TargetPort, Value: "8080"},
+			fmt.Errorf("lsof: %w", context.DeadlineExceeded),
+			appFlags{timeout: 2 * time.Second}, false, newJSONOutput(appFlags{}))
+		if code != ExitInternalError {
+			t.Errorf("code = %d, want %d (ExitInternalError)", code, ExitInternalError)
+		}
+		msg := errBuf.String()
+		if !strings.Contains(msg, "timed out after 2s") || strings.Contains(msg, "No matching process") {
+			t.Errorf("stderr = %q, want a timeout and no not-found hint", msg)
 		}
 	})
 }
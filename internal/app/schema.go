@@ -0,0 +1,52 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pranshuparmar/witr/internal/schema"
+	"github.com/pranshuparmar/witr/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of witr --json output",
+	Long: "schema prints a JSON Schema (draft 2020-12) for the report witr --json writes, generated\n" +
+		"from the Go types that produce it. The report's SchemaVersion changes only when a field is\n" +
+		"removed, renamed or changes type, so a validator can pin the version it was written for.",
+	Example: `  # Save the schema next to a validator's config
+  witr schema > witr-report.schema.json`,
+	Args: cobra.NoArgs,
+	RunE: runSchema,
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+}
+
+func runSchema(cmd *cobra.Command, _ []string) error {
+	data, err := json.MarshalIndent(reportSchema(), "", "  ")
+	if err != nil {
+		return withExitCode(ExitInternalError, err)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), string(data))
+	return nil
+}
+
+// reportSchema is the schema of model.Report, pinned to the current schema
+// version.
+func reportSchema() map[string]any {
+	s := schema.Generate(model.Report{})
+	s["title"] = "witr --json report"
+	s["description"] = fmt.Sprintf("The document witr --json writes, schema version %d.", model.SchemaVersion)
+	props := s["properties"].(map[string]any)
+	props["SchemaVersion"] = map[string]any{"type": "integer", "const": model.SchemaVersion}
+	// The report always writes these two lists, empty or not.
+	for _, name := range []string{"Results", "Errors"} {
+		props[name].(map[string]any)["type"] = "array"
+	}
+	return s
+}
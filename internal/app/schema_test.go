@@ -0,0 +1,158 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// TestReportMatchesSchema writes a report the way --json does and checks it
+// against `witr schema`, so the two cannot drift apart. The check is the
+// subset of JSON Schema the generator emits: types, required properties,
+// items, $ref and anyOf, plus a walk that flags properties the schema does
+// not describe.
+func TestReportMatchesSchema(t *testing.T) {
+	res := sampleResult()
+	res.Target = model.Target{Type: model.TargetName, Value: "nginx"}
+	res.Process.Sockets = []model.Socket{{Protocol: "tcp", Address: "0.0.0.0", Port: 80, State: "LISTEN"}}
+	res.Children = []model.Process{{PID: 1300, Command: "nginx"}}
+
+	// Every view writes the same report, with its entries in its own list.
+	for name, flags := range map[string]appFlags{
+		"full":     {json: true},
+		"short":    {json: true, short: true},
+		"tree":     {json: true, tree: true},
+		"warnings": {json: true, warn: true},
+		"env":      {json: true, env: true},
+	} {
+		jo := newJSONOutput(flags)
+		jo.addResult(res)
+		jo.addError(model.Target{Type: model.TargetPort, Value: "9"}, "no process listening on port 9", nil)
+		jo.addPortTable(model.PortTable{Target: model.Target{Type: model.TargetPort, Value: "8000-8100"},
+			Ports: []model.BoundPort{{Port: 8080, Protocol: "tcp", Address: "0.0.0.0", PID: 812, Process: "gunicorn", SourceType: model.SourceSystemd}}})
+		jo.addContainer(model.Target{Type: model.TargetContainer, Value: "db"}, &model.ContainerMatch{Runtime: "docker", ID: "abc123", Name: "db"})
+		var buf bytes.Buffer
+		if err := jo.write(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		var doc any
+		if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+			t.Fatal(err)
+		}
+		s := reportSchema()
+		if err := validate(s, s, doc, "$"); err != nil {
+			t.Errorf("%s: report does not match its schema: %v\n%s", name, err, buf.String())
+		}
+	}
+}
+
+func TestReportEmptyLists(t *testing.T) {
+	var buf bytes.Buffer
+	if err := newJSONOutput(appFlags{json: true}).write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"Results": []`, `"Errors": []`, fmt.Sprintf(`"SchemaVersion": %d`, model.SchemaVersion)} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("empty report missing %s:\n%s", want, buf.String())
+		}
+	}
+}
+
+func validate(root, s map[string]any, v any, path string) error {
+	if ref, ok := s["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/$defs/")
+		def, ok := root["$defs"].(map[string]any)[name].(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: unresolved %s", path, ref)
+		}
+		return validate(root, def, v, path)
+	}
+	if anyOf, ok := s["anyOf"].([]any); ok {
+		var errs []string
+		for _, alt := range anyOf {
+			err := validate(root, alt.(map[string]any), v, path)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, err.Error())
+		}
+		return fmt.Errorf("%s: no alternative matched: %s", path, strings.Join(errs, "; "))
+	}
+	if c, ok := s["const"]; ok && fmt.Sprint(c) != fmt.Sprint(v) {
+		return fmt.Errorf("%s: %v is not %v", path, v, c)
+	}
+
+	var types []string
+	switch typ := s["type"].(type) {
+	case string:
+		types = []string{typ}
+	case []string:
+		types = typ
+	case nil:
+		return nil
+	}
+	matched := false
+	for _, typ := range types {
+		if jsonType(v, typ) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return fmt.Errorf("%s: %T is not %v", path, v, types)
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		props, _ := s["properties"].(map[string]any)
+		required, _ := s["required"].([]string)
+		for _, name := range required {
+			if _, ok := v[name]; !ok {
+				return fmt.Errorf("%s: missing required %s", path, name)
+			}
+		}
+		for k, val := range v {
+			sub, ok := props[k].(map[string]any)
+			if !ok {
+				sub, ok = s["additionalProperties"].(map[string]any)
+			}
+			if !ok {
+				return fmt.Errorf("%s: %s is not described by the schema", path, k)
+			}
+			if err := validate(root, sub, val, path+"."+k); err != nil {
+				return err
+			}
+		}
+	case []any:
+		items, _ := s["items"].(map[string]any)
+		for i, val := range v {
+			if err := validate(root, items, val, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func jsonType(v any, typ string) bool {
+	switch v := v.(type) {
+	case nil:
+		return typ == "null"
+	case bool:
+		return typ == "boolean"
+	case float64:
+		return typ == "number" || (typ == "integer" && v == float64(int64(v)))
+	case string:
+		return typ == "string"
+	case []any:
+		return typ == "array"
+	case map[string]any:
+		return typ == "object"
+	}
+	return false
+}
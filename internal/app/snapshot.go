@@ -0,0 +1,100 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/pranshuparmar/witr/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Capture process state for offline analysis",
+	Long: "snapshot records the procfs state witr reads (process stat, cmdline, environ, cgroups,\n" +
+		"fd links, /proc/net, /proc/locks) and the container runtimes' listings into one archive.\n" +
+		"Analyze it later, on any Linux machine, with witr --snapshot FILE.",
+	Args: cobra.NoArgs,
+}
+
+var snapshotCaptureCmd = &cobra.Command{
+	Use:   "capture",
+	Short: "Write a snapshot of this system to a file",
+	Example: `  # Capture, compressed with zstd or gzip
+  witr snapshot capture -o box.tar.zst
+  witr snapshot capture -o box.tar.gz
+
+  # Analyze it later
+  witr --snapshot box.tar.zst nginx
+  witr --snapshot box.tar.zst -i`,
+	Args: cobra.NoArgs,
+	RunE: runSnapshotCapture,
+}
+
+func init() {
+	snapshotCaptureCmd.Flags().StringP("output", "o", "", "file to write: .tar, .tar.gz or .tar.zst (required)")
+	_ = snapshotCaptureCmd.MarkFlagRequired("output")
+	snapshotCmd.AddCommand(snapshotCaptureCmd)
+	rootCmd.AddCommand(snapshotCmd)
+}
+
+func runSnapshotCapture(cmd *cobra.Command, _ []string) error {
+	if runtime.GOOS != "linux" {
+		return withExitCode(ExitInvalidInput, fmt.Errorf("snapshot capture is only supported on Linux"))
+	}
+	out, _ := cmd.Flags().GetString("output")
+
+	meta, err := snapshot.CaptureFile(out, snapshot.Options{WitrVersion: version})
+	if err != nil {
+		return withExitCode(ExitInternalError, fmt.Errorf("capture snapshot: %w", err))
+	}
+
+	containers := 0
+	for _, rt := range meta.Containers {
+		containers += len(rt.Containers)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Captured %d processes and %d containers from %s to %s\n",
+		meta.Processes, containers, meta.Hostname, out)
+	return nil
+}
+
+// replayPath is the --snapshot file being analyzed, if any, and replayTime
+// when it was captured.
+var (
+	replayPath string
+	replayTime time.Time
+)
+
+// rerunCommand is how a hint should start so that re-running it analyzes the
+// same state.
+func rerunCommand() string {
+	if replayPath != "" {
+		return "witr --snapshot " + replayPath
+	}
+	return "witr"
+}
+
+// useSnapshot replaces the live system with the snapshot at path for the
+// rest of the run.
+func useSnapshot(cmd *cobra.Command, path string) error {
+	if runtime.GOOS != "linux" {
+		return withExitCode(ExitInvalidInput, fmt.Errorf("--snapshot is only supported on Linux"))
+	}
+	snap, err := snapshot.Open(path)
+	if err != nil {
+		return withExitCode(ExitInvalidInput, err)
+	}
+	snap.Install()
+	replayPath = path
+	replayTime = snap.Meta.CapturedAt
+
+	// Say which state is shown, but keep stdout clean for --json and pipes.
+	if isTerminal(os.Stderr) {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Reading %s\n\n", snap.Meta.Describe())
+	}
+	return nil
+}
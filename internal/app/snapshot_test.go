@@ -0,0 +1,64 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// TestSnapshotCaptureAndReplay captures this machine with the real binary and
+// analyzes PID 1 from the file, which must work without the live system.
+func TestSnapshotCaptureAndReplay(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the witr binary; skipped under -short")
+	}
+	if runtime.GOOS != "linux" {
+		t.Skip("snapshots are Linux-only")
+	}
+	bin := buildWitr(t)
+	file := filepath.Join(t.TempDir(), "box.tar.gz")
+
+	if out, err := exec.Command(bin, "snapshot", "capture", "-o", file).CombinedOutput(); err != nil {
+		t.Fatalf("snapshot capture: %v\n%s", err, out)
+	}
+
+	out, err := exec.Command(bin, "--snapshot", file, "--pid", "1", "--json").Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); !ok || ee.ExitCode() != ExitWarnings {
+			t.Fatalf("replay: %v\n%s", err, out)
+		}
+	}
+	var report model.Report
+	if err := json.Unmarshal(out, &report); err != nil {
+		t.Fatalf("replay output is not a JSON report: %v\n%s", err, out)
+	}
+	if len(report.Results) != 1 || report.Results[0].Process.PID != 1 {
+		t.Fatalf("replay should report PID 1, got %+v", report.Results)
+	}
+	// The report is stamped with the captured host and capture time.
+	if host, _ := os.Hostname(); report.Hostname != host || report.Timestamp.After(time.Now()) || time.Since(report.Timestamp) > time.Minute {
+		t.Errorf("report identity: host %q at %s", report.Hostname, report.Timestamp)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"missing file", []string{"--snapshot", filepath.Join(t.TempDir(), "nope.tar"), "--pid", "1"}},
+		{"not a snapshot", []string{"--snapshot", bin, "--pid", "1"}},
+		{"with proc root", []string{"--snapshot", file, "--proc-root", "/", "--pid", "1"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := runExit(t, bin, tc.args...); got != ExitInvalidInput {
+				t.Errorf("witr %v exit = %d, want %d", tc.args, got, ExitInvalidInput)
+			}
+		})
+	}
+}
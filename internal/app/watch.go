@@ -0,0 +1,240 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pranshuparmar/witr/internal/diff"
+	"github.com/pranshuparmar/witr/internal/output"
+	"github.com/pranshuparmar/witr/internal/pipeline"
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/target"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// defaultWatchInterval is used when --watch is given without a value.
+const defaultWatchInterval = 2 * time.Second
+
+// Watch event types.
+const (
+	watchStart  = "start"  // first successful analysis; carries the Result
+	watchChange = "change" // the result differs from the previous one
+	watchError  = "error"  // the target stopped resolving, or failed to analyze
+)
+
+// watchEvent is one line of --watch --json output (NDJSON).
+type watchEvent struct {
+	Time    time.Time
+	Target  model.Target
+	Event   string
+	Result  *model.Result `json:",omitempty"`
+	Changes []diff.Change `json:",omitempty"`
+	Error   string        `json:",omitempty"`
+}
+
+// watchState is what the watcher remembers about one target between ticks.
+type watchState struct {
+	target model.Target
+	last   *model.Result
+	err    string
+}
+
+// ambiguousError reports a target that resolves to more than one process or
+// container, which watching cannot follow.
+type ambiguousError struct {
+	what string // "processes" or "containers"
+	n    int
+	pids []int // the matching processes, when what is "processes"
+}
+
+func (e *ambiguousError) Error() string {
+	return fmt.Sprintf("multiple %s matched (%d results)", e.what, e.n)
+}
+
+// runWatch analyzes every target each interval until ctx is done, printing
+// only what changed since the previous tick. A target that is invalid or
+// ambiguous on the first tick ends the watch with that target's exit code; a
+// target that is merely not there yet is watched until it appears.
+func runWatch(ctx context.Context, outw io.Writer, targets []model.Target, flags appFlags, interval time.Duration) int {
+	outp := output.NewPrinter(outw)
+	colorEnabled := useColor(flags, outw)
+
+	// Each refresh reads the process table afresh, once for all targets.
+	tick := procpkg.WithIndex(ctx, procpkg.NewIndex())
+	states := make([]*watchState, len(targets))
+	for i, t := range targets {
+		states[i] = &watchState{target: t}
+		res, err := analyzeWatchTarget(tick, t, flags)
+		if ctx.Err() != nil {
+			return ExitOK
+		}
+		if err != nil {
+			if ae, ok := err.(*ambiguousError); ok {
+				switch {
+				case flags.json:
+					emitWatchEvent(outw, watchEvent{Time: time.Now(), Target: t, Event: watchError, Error: err.Error()})
+				case len(ae.pids) > 0:
+					printMultiMatch(outp, ae.pids, colorEnabled, rerunCommand()+" --watch --pid <pid>")
+				default:
+					outp.Printf("Error: %v\n", err)
+				}
+				return ExitInvalidInput
+			}
+			if code := classifyError(err); code == ExitInvalidInput {
+				if flags.json {
+					emitWatchEvent(outw, watchEvent{Time: time.Now(), Target: t, Event: watchError, Error: err.Error()})
+				} else {
+					outp.Printf("Error: %v\n", err)
+				}
+				return code
+			}
+		}
+		if !flags.json && len(targets) > 1 {
+			printDivider(outp, t, colorEnabled, i > 0)
+		}
+		states[i].update(outw, res, err, flags, colorEnabled)
+	}
+	if !flags.json {
+		if colorEnabled {
+			outp.Printf("\n%sWatching for changes every %s (Ctrl-C to stop)%s\n", output.ColorDim, interval, output.ColorReset)
+		} else {
+			outp.Printf("\nWatching for changes every %s (Ctrl-C to stop)\n", interval)
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ExitOK
+		case <-ticker.C:
+		}
+		tick := procpkg.WithIndex(ctx, procpkg.NewIndex())
+		for _, s := range states {
+			res, err := analyzeWatchTarget(tick, s.target, flags)
+			if ctx.Err() != nil {
+				// Stopped mid-refresh; the half-done lookup is not a change.
+				return ExitOK
+			}
+			s.update(outw, res, err, flags, colorEnabled)
+		}
+	}
+}
+
+// update records one analysis of the target and prints the event it amounts
+// to, if any. A repeated error is reported once.
+func (s *watchState) update(outw io.Writer, res model.Result, err error, flags appFlags, colorEnabled bool) {
+	now := time.Now()
+
+	if err != nil {
+		if err.Error() == s.err {
+			return
+		}
+		s.err = err.Error()
+		if flags.json {
+			emitWatchEvent(outw, watchEvent{Time: now, Target: s.target, Event: watchError, Error: s.err})
+			return
+		}
+		outp := output.NewPrinter(outw)
+		if colorEnabled {
+			outp.Printf("%s%s%s %s\n  %s!%s %s\n", output.ColorDim, now.Format("15:04:05"), output.ColorReset, targetLabel(s.target), output.ColorRed, output.ColorReset, s.err)
+		} else {
+			outp.Printf("%s %s\n  ! %s\n", now.Format("15:04:05"), targetLabel(s.target), s.err)
+		}
+		return
+	}
+	s.err = ""
+
+	if s.last == nil {
+		s.last = &res
+		if flags.json {
+			emitWatchEvent(outw, watchEvent{Time: now, Target: s.target, Event: watchStart, Result: &res})
+		} else {
+			output.RenderStandard(outw, res, colorEnabled, flags.verbose)
+		}
+		return
+	}
+
+	changes := diff.Results(*s.last, res)
+	s.last = &res
+	if len(changes) == 0 {
+		return
+	}
+	if flags.json {
+		emitWatchEvent(outw, watchEvent{Time: now, Target: s.target, Event: watchChange, Changes: changes})
+		return
+	}
+	output.RenderChanges(outw, now, targetLabel(s.target), changes, colorEnabled)
+}
+
+func emitWatchEvent(w io.Writer, ev watchEvent) {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "%s\n", data)
+}
+
+// analyzeWatchTarget resolves t afresh, so a port or name that moves to a new
+// process is followed, and analyzes the process it resolves to. Children are
+// always collected: processes coming and going is part of what is watched.
+// --timeout bounds each lookup rather than the whole watch.
+func analyzeWatchTarget(ctx context.Context, t model.Target, flags appFlags) (model.Result, error) {
+	if flags.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, flags.timeout)
+		defer cancel()
+	}
+
+	var pid int
+	if t.Type == model.TargetContainer {
+		matches := procpkg.ResolveContainer(ctx, t.Value, flags.exact)
+		switch {
+		case len(matches) == 0:
+			return model.Result{}, fmt.Errorf("no container found matching %q", t.Value)
+		case len(matches) > 1:
+			return model.Result{}, &ambiguousError{what: "containers", n: len(matches)}
+		}
+		pid = procpkg.ResolveContainerHostPID(ctx, matches[0].Runtime, matches[0].ID)
+		// The runtime's PID may be stale by the time it is read: a container
+		// that stopped between ticks must not be followed to a reused PID.
+		if pid <= 0 || !procpkg.PIDBelongsToContainer(pid, matches[0].ID) {
+			return model.Result{}, fmt.Errorf("container %s has no host-visible process", matches[0].Name)
+		}
+	} else {
+		pids, err := resolvePIDs(ctx, t, flags)
+		if err != nil {
+			return model.Result{}, describeAbandoned(err, flags)
+		}
+		switch {
+		case len(pids) == 0:
+			return model.Result{}, fmt.Errorf("no matching process found")
+		case len(pids) > 1:
+			return model.Result{}, &ambiguousError{what: "processes", n: len(pids), pids: pids}
+		}
+		pid = pids[0]
+	}
+
+	res, err := pipeline.AnalyzePID(ctx, pipeline.AnalyzeConfig{
+		PID:     pid,
+		Verbose: flags.verbose,
+		Tree:    true,
+		Target:  t,
+	})
+	if err != nil {
+		return model.Result{}, describeAbandoned(err, flags)
+	}
+	if t.Type == model.TargetPort {
+		if spec, err := target.ParsePort(t.Value); err == nil && !spec.IsRange() {
+			pipeline.AnnotatePortTarget(ctx, &res, spec.Low, spec.Proto)
+		}
+	}
+	applySuppression(ctx, &res, flags)
+	return res, nil
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// TestRunWatchJSON watches the test process itself for a few ticks and checks
+// the NDJSON stream: a start event with the full result first, then only
+// well-formed events.
+func TestRunWatchJSON(t *testing.T) {
+	self := model.Target{Type: model.TargetPID, Value: strconv.Itoa(os.Getpid())}
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+
+	var out bytes.Buffer
+	if code := runWatch(ctx, &out, []model.Target{self}, appFlags{json: true}, 20*time.Millisecond); code != ExitOK {
+		t.Fatalf("runWatch exit = %d, want %d\n%s", code, ExitOK, out.String())
+	}
+
+	if out.Len() == 0 {
+		t.Fatal("no events written")
+	}
+	sc := bufio.NewScanner(&out)
+	sc.Buffer(nil, 1<<20)
+	for i := 0; sc.Scan(); i++ {
+		var ev watchEvent
+		if err := json.Unmarshal(sc.Bytes(), &ev); err != nil {
+			t.Fatalf("line %d is not a JSON event: %v\n%s", i+1, err, sc.Text())
+		}
+		if ev.Time.IsZero() || ev.Target != self {
+			t.Errorf("line %d: missing time or wrong target: %+v", i+1, ev)
+		}
+		if i == 0 {
+			if ev.Event != watchStart || ev.Result == nil || ev.Result.Process.PID != os.Getpid() {
+				t.Fatalf("first event should be a start carrying our own result, got %+v", ev)
+			}
+			continue
+		}
+		if ev.Event != watchChange || len(ev.Changes) == 0 {
+			t.Errorf("line %d: later events should be non-empty changes, got %+v", i+1, ev)
+		}
+	}
+}
+
+func TestRunWatchInvalidTarget(t *testing.T) {
+	var out bytes.Buffer
+	bad := model.Target{Type: model.TargetPort, Value: "70000"}
+	if code := runWatch(context.Background(), &out, []model.Target{bad}, appFlags{}, time.Hour); code != ExitInvalidInput {
+		t.Errorf("runWatch on an invalid port = %d, want %d\n%s", code, ExitInvalidInput, out.String())
+	}
+}
+
+func TestWatchStateReportsErrorsOnce(t *testing.T) {
+	s := &watchState{target: model.Target{Type: model.TargetName, Value: "ghost"}}
+	var out bytes.Buffer
+	gone := errString("no matching process found")
+	s.update(&out, model.Result{}, gone, appFlags{}, false)
+	s.update(&out, model.Result{}, gone, appFlags{}, false)
+	if n := strings.Count(out.String(), "no matching process found"); n != 1 {
+		t.Errorf("a repeated error should print once, printed %d times:\n%s", n, out.String())
+	}
+}
+
+type errString string
+
+func (e errString) Error() string { return string(e) }
@@ -0,0 +1,190 @@
+// Package config is witr's policy: the tables and thresholds that decide which
+// supervisors are recognized and what earns a warning, plus default values for
+// command-line flags. Default is the built-in policy; Load layers the
+// system-wide and per-user config files over it, and Set makes the result the
+// policy every reader sees through Current.
+package config
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Config is the effective configuration. Its YAML form is both what a config
+// file contains and what `witr config show` prints.
+type Config struct {
+	// Defaults are flag values used when a flag is not given on the command
+	// line, keyed by long flag name ("no-color", "verbose").
+	Defaults   map[string]string `yaml:"defaults"`
+	Thresholds Thresholds        `yaml:"thresholds"`
+	// Supervisors maps a process name, or a token of a command line, to the
+	// supervisor it identifies ("runsv": "runit").
+	Supervisors map[string]string `yaml:"supervisors"`
+	// SuspiciousDirs are working directories a service should not run from.
+	SuspiciousDirs []string `yaml:"suspicious-dirs"`
+	// DangerousCapabilities are warned about on processes that do not run as
+	// root.
+	DangerousCapabilities []string `yaml:"dangerous-capabilities"`
+	// EnvRules flag environment variables that are warned about when set.
+	EnvRules []EnvRule `yaml:"env-rules"`
+	// Severities override the built-in severity of warnings by code
+	// ("W_ROOT": "info").
+	Severities map[model.WarningCode]model.Severity `yaml:"severities"`
+	// Suppress hides the warnings its rules match.
+	Suppress []SuppressRule `yaml:"suppress"`
+}
+
+// Thresholds are the limits beyond which a process is flagged.
+type Thresholds struct {
+	// Restarts is how many service restarts are tolerated before warning.
+	Restarts int `yaml:"restarts"`
+	// Age is how long a process may run before it is reported as long-lived.
+	Age Duration `yaml:"age"`
+	// Memory is the resident set size above which a process is high-mem.
+	Memory ByteSize `yaml:"memory"`
+	// CPUTime is the total CPU time above which a process is high-cpu on
+	// Linux and Windows.
+	CPUTime Duration `yaml:"cpu-time"`
+	// CPUPercent is the CPU usage above which a process is high-cpu on macOS
+	// and FreeBSD, where ps reports a percentage instead of a total.
+	CPUPercent float64 `yaml:"cpu-percent"`
+	// CgroupUsage is the percentage of its cgroup's memory or pids limit a
+	// process's cgroup may use before warning (Linux).
+	CgroupUsage float64 `yaml:"cgroup-usage"`
+}
+
+// EnvRule warns when a process sets a matching, non-empty environment
+// variable.
+type EnvRule struct {
+	Pattern string `yaml:"pattern"`
+	// Match is "exact" (the default) or "prefix".
+	Match   string `yaml:"match,omitempty"`
+	Warning string `yaml:"warning"`
+	// IncludeKeys appends the matching variable names to the warning.
+	IncludeKeys bool `yaml:"include-keys,omitempty"`
+	// Code and Severity default to W_SUSPICIOUS_ENV and medium.
+	Code     string `yaml:"code,omitempty"`
+	Severity string `yaml:"severity,omitempty"`
+}
+
+// Matches reports whether the variable key matches r.
+func (r EnvRule) Matches(key string) bool {
+	if r.Match == "prefix" {
+		return strings.HasPrefix(key, r.Pattern)
+	}
+	return key == r.Pattern
+}
+
+// SuppressRule hides warnings. Every field that is set must match; the
+// matchers are globs ("W_*", "/usr/sbin/*", "nginx:*").
+type SuppressRule struct {
+	// Code is the warning code, e.g. W_ROOT.
+	Code string `yaml:"code,omitempty"`
+	// Process is the process name.
+	Process string `yaml:"process,omitempty"`
+	// Exe is the executable path.
+	Exe string `yaml:"exe,omitempty"`
+	// Unit is the service (systemd unit, launchd label, rc script or Windows
+	// service) that manages the process.
+	Unit string `yaml:"unit,omitempty"`
+	// Image is the container image the process runs in.
+	Image string `yaml:"image,omitempty"`
+	// Reason is shown next to a suppressed warning.
+	Reason string `yaml:"reason,omitempty"`
+}
+
+// Default returns the built-in configuration.
+func Default() *Config {
+	return &Config{
+		Defaults: map[string]string{},
+		Thresholds: Thresholds{
+			Restarts:    5,
+			Age:         Duration(90 * 24 * time.Hour),
+			Memory:      1 << 30,
+			CPUTime:     Duration(2 * time.Hour),
+			CPUPercent:  90,
+			CgroupUsage: 90,
+		},
+		Supervisors: map[string]string{
+			"pm2":          "pm2",
+			"supervisord":  "supervisord",
+			"supervisor":   "supervisord",
+			"gunicorn":     "gunicorn",
+			"uwsgi":        "uwsgi",
+			"s6-supervise": "s6",
+			"s6":           "s6",
+			"s6-svscan":    "s6",
+			"runsv":        "runit",
+			"runit":        "runit",
+			"runit-init":   "runit",
+			"openrc":       "openrc",
+			"openrc-init":  "openrc",
+			"monit":        "monit",
+			"circusd":      "circus",
+			"circus":       "circus",
+			"systemd":      "systemd service",
+			"systemctl":    "systemd service",
+			"daemontools":  "daemontools",
+			"initctl":      "upstart",
+			"tini":         "tini",
+			"docker-init":  "docker-init",
+			"podman-init":  "podman-init",
+			"smf":          "smf",
+			"launchd":      "launchd",
+			"god":          "god",
+			"forever":      "forever",
+			"nssm":         "nssm",
+		},
+		SuspiciousDirs: []string{"/", "/tmp", "/var/tmp"},
+		DangerousCapabilities: []string{
+			"CAP_SYS_ADMIN",
+			"CAP_SYS_PTRACE",
+			"CAP_NET_RAW",
+			"CAP_DAC_OVERRIDE",
+			"CAP_DAC_READ_SEARCH",
+			"CAP_FOWNER",
+			"CAP_SYS_MODULE",
+			"CAP_SYS_RAWIO",
+		},
+		EnvRules: []EnvRule{
+			{
+				Pattern:  "LD_PRELOAD",
+				Warning:  "Process sets LD_PRELOAD (potential library injection)",
+				Code:     string(model.WarnLDPreload),
+				Severity: string(model.SeverityHigh),
+			},
+			{
+				Pattern:     "DYLD_",
+				Match:       "prefix",
+				Warning:     "Process sets DYLD_* variables (potential library injection)",
+				IncludeKeys: true,
+				Code:        string(model.WarnDYLDVars),
+				Severity:    string(model.SeverityHigh),
+			},
+		},
+		Severities: map[model.WarningCode]model.Severity{},
+	}
+}
+
+var (
+	mu      sync.RWMutex
+	current = Default()
+)
+
+// Set makes c the configuration every reader uses.
+func Set(c *Config) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = c
+}
+
+// Current returns the active configuration. It is shared; callers must not
+// modify it.
+func Current() *Config {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
@@ -0,0 +1,230 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func writeFile(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadLayers(t *testing.T) {
+	system := writeFile(t, "system.yaml", `
+defaults:
+  no-color: true
+thresholds:
+  restarts: 2
+  memory: 512MiB
+supervisors:
+  mysup: my supervisor
+suspicious-dirs: [/dev/shm]
+dangerous-capabilities: [bpf]
+`)
+	user := writeFile(t, "user.yaml", `
+defaults:
+  verbose: "true"
+thresholds:
+  age: 30d
+supervisors:
+  god: ""
+env-rules:
+  - pattern: LD_PRELOAD
+    warning: preload is set
+  - pattern: LD_AUDIT
+    warning: audit is set
+    code: w_ld_audit
+    severity: HIGH
+severities:
+  w_root: info
+`)
+	missing := filepath.Join(t.TempDir(), "missing.yaml")
+
+	c, read, err := Load([]string{system, missing, user})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(read, []string{system, user}) {
+		t.Errorf("read = %v, want the two existing files", read)
+	}
+
+	if c.Defaults["no-color"] != "true" || c.Defaults["verbose"] != "true" {
+		t.Errorf("defaults = %v", c.Defaults)
+	}
+	want := Thresholds{Restarts: 2, Age: Duration(30 * day), Memory: 512 << 20, CPUTime: Duration(2 * time.Hour), CPUPercent: 90, CgroupUsage: 90}
+	if c.Thresholds != want {
+		t.Errorf("thresholds = %+v, want %+v", c.Thresholds, want)
+	}
+	if c.Supervisors["mysup"] != "my supervisor" || c.Supervisors["runsv"] != "runit" {
+		t.Errorf("supervisors should extend the built-ins: %v", c.Supervisors)
+	}
+	if _, ok := c.Supervisors["god"]; ok {
+		t.Error("an empty label should drop a supervisor")
+	}
+	if !slices.Contains(c.SuspiciousDirs, "/tmp") || !slices.Contains(c.SuspiciousDirs, "/dev/shm") {
+		t.Errorf("suspicious-dirs = %v", c.SuspiciousDirs)
+	}
+	if !slices.Contains(c.DangerousCapabilities, "CAP_BPF") {
+		t.Errorf("capabilities should be normalized: %v", c.DangerousCapabilities)
+	}
+	if len(c.EnvRules) != 3 || c.EnvRules[0].Warning != "preload is set" || c.EnvRules[2].Pattern != "LD_AUDIT" {
+		t.Errorf("env-rules = %+v", c.EnvRules)
+	} else if r := c.EnvRules[2]; r.Code != "W_LD_AUDIT" || r.Severity != "high" {
+		t.Errorf("env rule code and severity should be normalized: %+v", r)
+	}
+	if c.Severities[model.WarnRoot] != model.SeverityInfo {
+		t.Errorf("severities = %v", c.Severities)
+	}
+}
+
+func TestLoadReplace(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+replace: [supervisors, suspicious-dirs]
+supervisors:
+  mysup: mine
+suspicious-dirs: [/srv/scratch]
+`)
+	c, _, err := Load([]string{path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Supervisors) != 1 || !slices.Equal(c.SuspiciousDirs, []string{"/srv/scratch"}) {
+		t.Errorf("replaced tables: supervisors %v, dirs %v", c.Supervisors, c.SuspiciousDirs)
+	}
+	if len(c.DangerousCapabilities) != len(Default().DangerousCapabilities) {
+		t.Error("tables not listed under replace should keep their built-ins")
+	}
+}
+
+func TestLoadSuppress(t *testing.T) {
+	system := writeFile(t, "system.yaml", `
+suppress:
+  - code: w_root
+    process: sshd
+    reason: sshd needs root
+`)
+	user := writeFile(t, "user.yaml", `
+suppress:
+  - code: W_PUBLIC_BIND
+    unit: nginx.service
+`)
+	c, _, err := Load([]string{system, user})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SuppressRule{
+		{Code: "W_ROOT", Process: "sshd", Reason: "sshd needs root"},
+		{Code: "W_PUBLIC_BIND", Unit: "nginx.service"},
+	}
+	if !reflect.DeepEqual(c.Suppress, want) {
+		t.Errorf("suppress rules should append across files:\n got %+v\nwant %+v", c.Suppress, want)
+	}
+
+	replaced := writeFile(t, "replace.yaml", "replace: [suppress]\nsuppress:\n  - code: W_LONG_RUNNING\n")
+	c, _, err = Load([]string{system, replaced})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Suppress) != 1 || c.Suppress[0].Code != "W_LONG_RUNNING" {
+		t.Errorf("replace: [suppress] should drop earlier rules, got %+v", c.Suppress)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"unknown key":       "threshold:\n  restarts: 1\n",
+		"bad duration":      "thresholds:\n  age: forever\n",
+		"bad size":          "thresholds:\n  memory: lots\n",
+		"negative":          "thresholds:\n  restarts: -1\n",
+		"unknown table":     "replace: [widgets]\n",
+		"bad match":         "env-rules:\n  - pattern: X\n    warning: x\n    match: regex\n",
+		"rule, no warning":  "env-rules:\n  - pattern: X\n",
+		"bad severity":      "severities:\n  W_ROOT: severe\n",
+		"bad rule severity": "env-rules:\n  - pattern: X\n    warning: x\n    severity: severe\n",
+		"empty suppress":    "suppress:\n  - reason: everything\n",
+		"bad glob":          "suppress:\n  - process: \"[nginx\"\n",
+	} {
+		path := writeFile(t, "config.yaml", data)
+		if _, _, err := Load([]string{path}); err == nil || !strings.Contains(err.Error(), path) {
+			t.Errorf("%s: got %v, want an error naming the file", name, err)
+		}
+	}
+
+	if _, _, err := Load([]string{writeFile(t, "empty.yaml", "")}); err != nil {
+		t.Errorf("an empty file should load, got %v", err)
+	}
+}
+
+// TestWriteRoundTrip checks that what `witr config show` prints loads back to
+// the same configuration.
+func TestWriteRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Default().Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	c, _, err := Load([]string{writeFile(t, "config.yaml", buf.String())})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c, Default()) {
+		t.Errorf("round trip changed the configuration:\n%s", buf.String())
+	}
+}
+
+func TestUnits(t *testing.T) {
+	for in, want := range map[string]time.Duration{
+		"90d":   90 * day,
+		"1.5d":  36 * time.Hour,
+		"2h":    2 * time.Hour,
+		"1h30m": 90 * time.Minute,
+	} {
+		got, err := ParseDuration(in)
+		if err != nil || time.Duration(got) != want {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v", in, time.Duration(got), err, want)
+		}
+	}
+	for d, want := range map[Duration]string{
+		Duration(90 * day):         "90d",
+		Duration(2 * time.Hour):    "2h",
+		Duration(90 * time.Minute): "1h30m",
+		Duration(45 * time.Second): "45s",
+	} {
+		if got := d.String(); got != want {
+			t.Errorf("Duration(%v).String() = %q, want %q", time.Duration(d), got, want)
+		}
+	}
+	if got := Duration(day).Describe(); got != "1 day" {
+		t.Errorf("Describe() = %q", got)
+	}
+
+	for in, want := range map[string]ByteSize{
+		"1GiB":    1 << 30,
+		"512 MiB": 512 << 20,
+		"1.5G":    3 << 29,
+		"2GB":     2e9,
+		"4096":    4096,
+	} {
+		got, err := ParseByteSize(in)
+		if err != nil || got != want {
+			t.Errorf("ParseByteSize(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	for b, want := range map[ByteSize]string{1 << 30: "1GiB", 1536 << 20: "1536MiB", 100: "100"} {
+		if got := b.String(); got != want {
+			t.Errorf("ByteSize(%d).String() = %q, want %q", b, got, want)
+		}
+	}
+}
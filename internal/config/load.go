@@ -0,0 +1,226 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+	"go.yaml.in/yaml/v3"
+)
+
+// SystemPath is the system-wide config file: /etc/witr/config.yaml, or
+// %ProgramData%\witr\config.yaml on Windows.
+func SystemPath() string {
+	if runtime.GOOS == "windows" {
+		dir := os.Getenv("ProgramData")
+		if dir == "" {
+			dir = `C:\ProgramData`
+		}
+		return filepath.Join(dir, "witr", "config.yaml")
+	}
+	return "/etc/witr/config.yaml"
+}
+
+// UserPath is the per-user config file: $XDG_CONFIG_HOME/witr/config.yaml,
+// falling back to ~/.config (or %AppData% on Windows). It is empty when no
+// home directory is known.
+func UserPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" && runtime.GOOS == "windows" {
+		dir, _ = os.UserConfigDir()
+	}
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "witr", "config.yaml")
+}
+
+// Paths returns the files Load reads by default, lowest precedence first.
+func Paths() []string {
+	paths := []string{SystemPath()}
+	if p := UserPath(); p != "" {
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// Load returns the built-in configuration with each file in paths layered
+// over it in order, and the files that were actually read. Missing files are
+// skipped; a file that is present but invalid is an error.
+func Load(paths []string) (*Config, []string, error) {
+	c := Default()
+	var read []string
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := c.apply(data); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", path, err)
+		}
+		read = append(read, path)
+	}
+	return c, read, nil
+}
+
+// layer is one config file. Tables extend what earlier layers set unless the
+// file names them in Replace; thresholds and defaults override key by key.
+type layer struct {
+	Defaults              map[string]string `yaml:"defaults"`
+	Thresholds            *Thresholds       `yaml:"thresholds"`
+	Supervisors           map[string]string `yaml:"supervisors"`
+	SuspiciousDirs        []string          `yaml:"suspicious-dirs"`
+	DangerousCapabilities []string          `yaml:"dangerous-capabilities"`
+	EnvRules              []EnvRule         `yaml:"env-rules"`
+	Severities            map[string]string `yaml:"severities"`
+	Suppress              []SuppressRule    `yaml:"suppress"`
+	Replace               []string          `yaml:"replace"`
+}
+
+// replaceable are the tables a layer may replace instead of extend.
+var replaceable = []string{"supervisors", "suspicious-dirs", "dangerous-capabilities", "env-rules", "suppress"}
+
+func (c *Config) apply(data []byte) error {
+	// Thresholds decode over the current values, so a file that sets one
+	// threshold leaves the others alone.
+	th := c.Thresholds
+	l := layer{Thresholds: &th}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&l); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	for _, name := range l.Replace {
+		if !slices.Contains(replaceable, name) {
+			return fmt.Errorf("replace: unknown table %q (want one of %s)", name, strings.Join(replaceable, ", "))
+		}
+	}
+	replace := func(name string) bool { return slices.Contains(l.Replace, name) }
+
+	if th.Restarts < 0 || th.CPUPercent < 0 || th.CgroupUsage < 0 {
+		return fmt.Errorf("thresholds: restarts, cpu-percent and cgroup-usage must not be negative")
+	}
+	c.Thresholds = th
+
+	for k, v := range l.Defaults {
+		c.Defaults[k] = v
+	}
+
+	if replace("supervisors") {
+		c.Supervisors = map[string]string{}
+	}
+	for k, v := range l.Supervisors {
+		// An empty label drops a supervisor an earlier layer defined.
+		if v == "" {
+			delete(c.Supervisors, strings.ToLower(k))
+			continue
+		}
+		c.Supervisors[strings.ToLower(k)] = v
+	}
+
+	if replace("suspicious-dirs") {
+		c.SuspiciousDirs = nil
+	}
+	for _, dir := range l.SuspiciousDirs {
+		c.SuspiciousDirs = appendUnique(c.SuspiciousDirs, filepath.Clean(dir))
+	}
+
+	if replace("dangerous-capabilities") {
+		c.DangerousCapabilities = nil
+	}
+	for _, cp := range l.DangerousCapabilities {
+		cp = strings.ToUpper(cp)
+		if !strings.HasPrefix(cp, "CAP_") {
+			cp = "CAP_" + cp
+		}
+		c.DangerousCapabilities = appendUnique(c.DangerousCapabilities, cp)
+	}
+
+	if replace("env-rules") {
+		c.EnvRules = nil
+	}
+	for _, r := range l.EnvRules {
+		if r.Pattern == "" || r.Warning == "" {
+			return fmt.Errorf("env-rules: each rule needs a pattern and a warning")
+		}
+		if r.Match != "" && r.Match != "exact" && r.Match != "prefix" {
+			return fmt.Errorf("env-rules: %s: match must be exact or prefix, not %q", r.Pattern, r.Match)
+		}
+		if r.Severity != "" {
+			sev, err := model.ParseSeverity(r.Severity)
+			if err != nil {
+				return fmt.Errorf("env-rules: %s: %w", r.Pattern, err)
+			}
+			r.Severity = string(sev)
+		}
+		r.Code = strings.ToUpper(r.Code)
+		// A rule for a pattern an earlier layer defined replaces it in place.
+		if i := slices.IndexFunc(c.EnvRules, func(e EnvRule) bool { return e.Pattern == r.Pattern }); i >= 0 {
+			c.EnvRules[i] = r
+		} else {
+			c.EnvRules = append(c.EnvRules, r)
+		}
+	}
+
+	for code, name := range l.Severities {
+		sev, err := model.ParseSeverity(name)
+		if err != nil {
+			return fmt.Errorf("severities: %s: %w", code, err)
+		}
+		c.Severities[model.WarningCode(strings.ToUpper(code))] = sev
+	}
+
+	if replace("suppress") {
+		c.Suppress = nil
+	}
+	for i, r := range l.Suppress {
+		r.Code = strings.ToUpper(r.Code)
+		globs := []string{r.Code, r.Process, r.Exe, r.Unit, r.Image}
+		if strings.Join(globs, "") == "" {
+			return fmt.Errorf("suppress: rule %d matches nothing; set code, process, exe, unit or image", i+1)
+		}
+		for _, g := range globs {
+			if _, err := path.Match(g, ""); err != nil {
+				return fmt.Errorf("suppress: rule %d: bad pattern %q", i+1, g)
+			}
+		}
+		if !slices.Contains(c.Suppress, r) {
+			c.Suppress = append(c.Suppress, r)
+		}
+	}
+	return nil
+}
+
+func appendUnique(list []string, v string) []string {
+	if slices.Contains(list, v) {
+		return list
+	}
+	return append(list, v)
+}
+
+// Write prints c as YAML, in the format Load reads.
+func (c *Config) Write(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(c); err != nil {
+		return err
+	}
+	return enc.Close()
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+const day = 24 * time.Hour
+
+// Duration is a time.Duration written as Go duration syntax ("2h30m") or,
+// for long spans, whole days ("90d").
+type Duration time.Duration
+
+// ParseDuration parses "90d" or anything time.ParseDuration accepts.
+func ParseDuration(s string) (Duration, error) {
+	s = strings.TrimSpace(s)
+	if n, ok := strings.CutSuffix(s, "d"); ok {
+		days, err := strconv.ParseFloat(n, 64)
+		if err != nil || days < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return Duration(days * float64(day)), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return Duration(d), nil
+}
+
+// String renders d in the syntax ParseDuration reads: "90d", "2h", "1h30m".
+func (d Duration) String() string {
+	td := time.Duration(d)
+	if td != 0 && td%day == 0 {
+		return strconv.FormatInt(int64(td/day), 10) + "d"
+	}
+	s := td.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// Describe renders d for a message: "90 days", "2h".
+func (d Duration) Describe() string {
+	td := time.Duration(d)
+	if td != 0 && td%day == 0 {
+		if n := td / day; n != 1 {
+			return fmt.Sprintf("%d days", n)
+		}
+		return "1 day"
+	}
+	return d.String()
+}
+
+func (d Duration) MarshalYAML() (any, error) { return d.String(), nil }
+
+func (d *Duration) UnmarshalYAML(n *yaml.Node) error {
+	v, err := ParseDuration(n.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", n.Line, err)
+	}
+	*d = v
+	return nil
+}
+
+// ByteSize is a size in bytes written as a plain number or with a unit:
+// decimal (KB, MB, GB, TB) or binary (KiB, MiB, GiB, TiB).
+type ByteSize uint64
+
+var byteUnits = []struct {
+	suffix string
+	size   float64
+}{
+	{"kib", 1 << 10}, {"mib", 1 << 20}, {"gib", 1 << 30}, {"tib", 1 << 40},
+	{"kb", 1e3}, {"mb", 1e6}, {"gb", 1e9}, {"tb", 1e12},
+	{"k", 1 << 10}, {"m", 1 << 20}, {"g", 1 << 30}, {"t", 1 << 40},
+	{"b", 1},
+}
+
+// ParseByteSize parses "1GiB", "512 MB", "1.5G" or a plain byte count. Bare
+// single-letter units are binary, as in ps and top.
+func ParseByteSize(s string) (ByteSize, error) {
+	num := strings.ToLower(strings.TrimSpace(s))
+	mult := 1.0
+	for _, u := range byteUnits {
+		if n, ok := strings.CutSuffix(num, u.suffix); ok {
+			num, mult = strings.TrimSpace(n), u.size
+			break
+		}
+	}
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil || v < 0 || v*mult > math.MaxUint64 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return ByteSize(v * mult), nil
+}
+
+// String renders b with the largest binary unit that divides it exactly:
+// "1GiB", "1536MiB", "100".
+func (b ByteSize) String() string {
+	for _, u := range []struct {
+		suffix string
+		size   ByteSize
+	}{{"TiB", 1 << 40}, {"GiB", 1 << 30}, {"MiB", 1 << 20}, {"KiB", 1 << 10}} {
+		if b != 0 && b%u.size == 0 {
+			return strconv.FormatUint(uint64(b/u.size), 10) + u.suffix
+		}
+	}
+	return strconv.FormatUint(uint64(b), 10)
+}
+
+func (b ByteSize) MarshalYAML() (any, error) { return b.String(), nil }
+
+func (b *ByteSize) UnmarshalYAML(n *yaml.Node) error {
+	v, err := ParseByteSize(n.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", n.Line, err)
+	}
+	*b = v
+	return nil
+}
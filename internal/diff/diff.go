@@ -0,0 +1,357 @@
+// Package diff compares two analyses of the same target and reports what
+// changed between them as a flat list of Changes: a different owning process,
+// a new ancestor, children and listening sockets that came or went, a restart counter
+// that climbed, environment variables and capabilities that changed. Values
+// are rendered to strings so a Change prints and serializes the same way
+// whichever field it came from.
+package diff
+
+import (
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Kind says whether a value appeared, disappeared or changed.
+type Kind string
+
+const (
+	Added   Kind = "added"
+	Removed Kind = "removed"
+	Changed Kind = "changed"
+)
+
+// Change is one difference between two results. Field names the result field
+// in Go syntax ("Process.PID", "Source.Details.NRestarts"); Old is empty for
+// Added and New is empty for Removed. Delta is set for numeric changes
+// ("+12.0 MB", "-3").
+type Change struct {
+	Field string
+	Kind  Kind
+	Old   string `json:",omitempty"`
+	New   string `json:",omitempty"`
+	Delta string `json:",omitempty"`
+}
+
+// Results returns the changes from old to new, in a fixed field order.
+func Results(old, new model.Result) []Change {
+	var d differ
+
+	d.value("Process.PID", pid(old.Process.PID), pid(new.Process.PID))
+	d.value("Process.Command", old.Process.Command, new.Process.Command)
+	d.value("Process.Cmdline", old.Process.Cmdline, new.Process.Cmdline)
+	d.value("Process.Exe", old.Process.Exe, new.Process.Exe)
+	d.value("Process.User", old.Process.User, new.Process.User)
+	d.value("Process.StartedAt", started(old.Process.StartedAt), started(new.Process.StartedAt))
+	d.value("Process.Health", old.Process.Health, new.Process.Health)
+	d.value("Process.ExeDeleted", flag(old.Process.ExeDeleted), flag(new.Process.ExeDeleted))
+	d.value("Process.WorkingDir", old.Process.WorkingDir, new.Process.WorkingDir)
+	d.value("Process.Container", old.Process.Container, new.Process.Container)
+	d.value("Process.Service", old.Process.Service, new.Process.Service)
+
+	d.value("Ancestry", chain(old.Ancestry), chain(new.Ancestry))
+	d.set("Children", processes(old.Children), processes(new.Children))
+	d.set("Sockets", sockets(old.Process.Sockets), sockets(new.Process.Sockets))
+
+	d.value("Source", source(old.Source), source(new.Source))
+	d.value("Source.UnitFile", old.Source.UnitFile, new.Source.UnitFile)
+	d.keyed("Source.Details", old.Source.Details, new.Source.Details)
+	d.value("RestartCount", strconv.Itoa(old.RestartCount), strconv.Itoa(new.RestartCount))
+
+	d.keyed("Process.Env", env(old.Process.Env), env(new.Process.Env))
+	d.set("Process.Capabilities", old.Process.Capabilities, new.Process.Capabilities)
+
+	d.warnings("Warnings", old.Warnings, new.Warnings)
+	return d.changes
+}
+
+// Resources returns how resource usage moved from old to new: CPU, memory,
+// threads, file descriptors and I/O. These change on every sample, so unlike
+// Results they are only worth reporting between two saved results, not
+// between successive ticks of a watch.
+func Resources(old, new model.Result) []Change {
+	var d differ
+	o, n := old.Process, new.Process
+
+	d.percent("Process.CPUPercent", o.CPUPercent, n.CPUPercent)
+	d.bytes("Process.MemoryRSS", o.MemoryRSS, n.MemoryRSS)
+	d.percent("Process.MemoryPercent", o.MemoryPercent, n.MemoryPercent)
+	d.bytes("Process.Memory.VMS", o.Memory.VMS, n.Memory.VMS)
+	d.count("Process.ThreadCount", int64(o.ThreadCount), int64(n.ThreadCount))
+	d.count("Process.FDCount", int64(o.FDCount), int64(n.FDCount))
+	d.bytes("Process.IO.ReadBytes", o.IO.ReadBytes, n.IO.ReadBytes)
+	d.bytes("Process.IO.WriteBytes", o.IO.WriteBytes, n.IO.WriteBytes)
+	return d.changes
+}
+
+type differ struct {
+	changes []Change
+}
+
+// value compares a scalar rendered as a string; "" means absent.
+func (d *differ) value(field, old, new string) {
+	switch {
+	case old == new:
+	case old == "":
+		d.changes = append(d.changes, Change{Field: field, Kind: Added, New: new})
+	case new == "":
+		d.changes = append(d.changes, Change{Field: field, Kind: Removed, Old: old})
+	default:
+		d.changes = append(d.changes, Change{Field: field, Kind: Changed, Old: old, New: new})
+	}
+}
+
+// set compares two unordered collections, reporting removals before
+// additions, each in the order they appear.
+func (d *differ) set(field string, old, new []string) {
+	for _, v := range old {
+		if !slices.Contains(new, v) {
+			d.changes = append(d.changes, Change{Field: field, Kind: Removed, Old: v})
+		}
+	}
+	for _, v := range new {
+		if !slices.Contains(old, v) {
+			d.changes = append(d.changes, Change{Field: field, Kind: Added, New: v})
+		}
+	}
+}
+
+// warnings compares two lists of warnings by what each is about, so a
+// reading in a message that moved (a cgroup's usage) is not a change.
+// Results saved before warnings had codes can only be compared by message.
+func (d *differ) warnings(field string, old, new []model.Warning) {
+	byMessage := uncoded(old) || uncoded(new)
+	subject := func(w model.Warning) string {
+		if byMessage {
+			return w.Message
+		}
+		return w.Subject()
+	}
+	has := func(ws []model.Warning, s string) bool {
+		return slices.ContainsFunc(ws, func(w model.Warning) bool { return subject(w) == s })
+	}
+	for _, w := range old {
+		if !has(new, subject(w)) {
+			d.changes = append(d.changes, Change{Field: field, Kind: Removed, Old: warning(w, byMessage)})
+		}
+	}
+	for _, w := range new {
+		if !has(old, subject(w)) {
+			d.changes = append(d.changes, Change{Field: field, Kind: Added, New: warning(w, byMessage)})
+		}
+	}
+}
+
+// bytes, count and percent compare numbers, zero meaning not measured, and
+// record the difference as Delta.
+func (d *differ) bytes(field string, old, new uint64) {
+	d.number(field, formatBytes(old), formatBytes(new), func() string {
+		if new >= old {
+			return "+" + formatBytes(new-old)
+		}
+		return "-" + formatBytes(old-new)
+	})
+}
+
+func (d *differ) count(field string, old, new int64) {
+	str := func(v int64) string {
+		if v == 0 {
+			return ""
+		}
+		return strconv.FormatInt(v, 10)
+	}
+	d.number(field, str(old), str(new), func() string { return fmt.Sprintf("%+d", new-old) })
+}
+
+func (d *differ) percent(field string, old, new float64) {
+	str := func(v float64) string {
+		if v == 0 {
+			return ""
+		}
+		return strconv.FormatFloat(v, 'f', 1, 64) + "%"
+	}
+	d.number(field, str(old), str(new), func() string { return fmt.Sprintf("%+.1f%%", new-old) })
+}
+
+func (d *differ) number(field, old, new string, delta func() string) {
+	n := len(d.changes)
+	d.value(field, old, new)
+	if len(d.changes) > n && d.changes[n].Kind == Changed {
+		d.changes[n].Delta = delta()
+	}
+}
+
+// keyed compares two maps key by key, in key order.
+func (d *differ) keyed(field string, old, new map[string]string) {
+	keys := slices.Collect(maps.Keys(old))
+	for k := range new {
+		if _, ok := old[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		d.value(field+"."+k, old[k], new[k])
+	}
+}
+
+// env turns KEY=value pairs into a map; a later duplicate wins, as it does
+// for getenv.
+func env(vars []string) map[string]string {
+	out := make(map[string]string, len(vars))
+	for _, kv := range vars {
+		k, v, _ := strings.Cut(kv, "=")
+		out[k] = v
+	}
+	return out
+}
+
+// formatBytes renders a byte count with a binary unit, like the verbose
+// report does; zero means not measured.
+func formatBytes(n uint64) string {
+	const unit = 1024
+	switch {
+	case n == 0:
+		return ""
+	case n < unit:
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func pid(p int) string {
+	if p <= 0 {
+		return ""
+	}
+	return strconv.Itoa(p)
+}
+
+func flag(b bool) string {
+	if !b {
+		return ""
+	}
+	return "true"
+}
+
+func started(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// process is how a process appears in a change: "nginx (pid 812)".
+func process(p model.Process) string {
+	name := p.Command
+	if name == "" {
+		name = "(unknown)"
+	}
+	return fmt.Sprintf("%s (pid %d)", name, p.PID)
+}
+
+func processes(ps []model.Process) []string {
+	out := make([]string, len(ps))
+	for i, p := range ps {
+		out[i] = process(p)
+	}
+	return out
+}
+
+func chain(ps []model.Process) string {
+	return strings.Join(processes(ps), " → ")
+}
+
+// sockets leaves out connections, both those a unix listener accepted, as
+// the Sockets section does, and TCP and UDP ones: they come and go with
+// every client, each from a new ephemeral port.
+func sockets(ss []model.Socket) []string {
+	out := make([]string, 0, len(ss))
+	for _, s := range ss {
+		if (s.Path != "" && s.State == "ESTABLISHED") || s.RemotePort > 0 {
+			continue
+		}
+		out = append(out, socket(s))
+	}
+	return out
+}
+
+// socket is how a socket appears in a change: "tcp 0.0.0.0:8080 LISTEN", or
+// "unix stream /run/app.sock LISTEN".
+func socket(s model.Socket) string {
+	if s.Path != "" {
+		out := fmt.Sprintf("unix %s %s", s.Type, s.Path)
+		if s.State != "" {
+			out += " " + s.State
+		}
+		if s.Netns != "" {
+			out += " netns " + s.Netns
+		}
+		return out
+	}
+	addr := s.Address
+	if strings.Contains(addr, ":") {
+		addr = "[" + addr + "]"
+	}
+	out := fmt.Sprintf("%s %s:%d", s.Protocol, addr, s.Port)
+	if s.RemotePort > 0 {
+		out += " -> " + net.JoinHostPort(s.RemoteAddress, strconv.Itoa(s.RemotePort))
+	}
+	if s.State != "" {
+		out += " " + s.State
+	}
+	if s.Netns != "" {
+		out += " netns " + s.Netns
+	}
+	return out
+}
+
+// warning renders a warning as "W_ROOT: Process is running as root", or as
+// its message alone. Evidence that identifies it but that the message leaves
+// out follows in brackets: "W_PUBLIC_BIND: ... [addresses=0.0.0.0:80]".
+func warning(w model.Warning, messageOnly bool) string {
+	if messageOnly {
+		return w.Message
+	}
+	out := string(w.Code) + ": " + w.Message
+	var extra []string
+	for _, kv := range w.SubjectEvidence() {
+		if _, v, _ := strings.Cut(kv, "="); !strings.Contains(w.Message, v) {
+			extra = append(extra, kv)
+		}
+	}
+	if len(extra) > 0 {
+		out += " [" + strings.Join(extra, " ") + "]"
+	}
+	return out
+}
+
+func uncoded(ws []model.Warning) bool {
+	for _, w := range ws {
+		if w.Code == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func source(s model.Source) string {
+	if s.Type == "" {
+		return ""
+	}
+	if s.Name == "" {
+		return string(s.Type)
+	}
+	return string(s.Type) + ": " + s.Name
+}
@@ -0,0 +1,170 @@
+package diff
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func baseResult() model.Result {
+	started := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	proc := model.Process{
+		PID:       812,
+		PPID:      1,
+		Command:   "nginx",
+		StartedAt: started,
+		Sockets: []model.Socket{
+			{Protocol: "tcp", Address: "0.0.0.0", Port: 80, State: "LISTEN"},
+		},
+	}
+	return model.Result{
+		Process:  proc,
+		Ancestry: []model.Process{{PID: 1, Command: "systemd"}, proc},
+		Children: []model.Process{{PID: 900, Command: "nginx"}},
+		Source: model.Source{
+			Type:    model.SourceSystemd,
+			Name:    "nginx.service",
+			Details: map[string]string{"NRestarts": "0"},
+		},
+	}
+}
+
+func TestResultsIdentical(t *testing.T) {
+	if got := Results(baseResult(), baseResult()); len(got) != 0 {
+		t.Errorf("identical results should not differ, got %+v", got)
+	}
+}
+
+func TestResultsRestart(t *testing.T) {
+	old := baseResult()
+	new := baseResult()
+	new.Process.PID = 901
+	new.Process.StartedAt = old.Process.StartedAt.Add(time.Minute)
+	new.Ancestry[1] = new.Process
+	new.Children = []model.Process{{PID: 950, Command: "nginx"}}
+	new.Process.Sockets = append(new.Process.Sockets, model.Socket{Protocol: "tcp", Address: "::", Port: 443, State: "LISTEN"})
+	new.Source.Details = map[string]string{"NRestarts": "1"}
+	new.RestartCount = 1
+	new.Warnings = []model.Warning{{Code: model.WarnRestarts, Severity: model.SeverityMedium, Message: "Service has restarted 1 times"}}
+
+	want := []Change{
+		{Field: "Process.PID", Kind: Changed, Old: "812", New: "901"},
+		{Field: "Process.StartedAt", Kind: Changed, Old: "2026-01-02T03:04:05Z", New: "2026-01-02T03:05:05Z"},
+		{Field: "Ancestry", Kind: Changed, Old: "systemd (pid 1) → nginx (pid 812)", New: "systemd (pid 1) → nginx (pid 901)"},
+		{Field: "Children", Kind: Removed, Old: "nginx (pid 900)"},
+		{Field: "Children", Kind: Added, New: "nginx (pid 950)"},
+		{Field: "Sockets", Kind: Added, New: "tcp [::]:443 LISTEN"},
+		{Field: "Source.Details.NRestarts", Kind: Changed, Old: "0", New: "1"},
+		{Field: "RestartCount", Kind: Changed, Old: "0", New: "1"},
+		{Field: "Warnings", Kind: Added, New: "W_RESTARTS: Service has restarted 1 times"},
+	}
+	if got := Results(old, new); !reflect.DeepEqual(got, want) {
+		t.Errorf("Results() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+// TestResultsLegacyWarnings compares a result saved before warnings had codes
+// with a current one: only the messages can be matched.
+func TestResultsLegacyWarnings(t *testing.T) {
+	old := baseResult()
+	new := baseResult()
+	if err := json.Unmarshal([]byte(`["Process is running as root"]`), &old.Warnings); err != nil {
+		t.Fatal(err)
+	}
+	new.Warnings = []model.Warning{
+		{Code: model.WarnRoot, Severity: model.SeverityLow, Message: "Process is running as root"},
+		{Code: model.WarnDeletedExe, Severity: model.SeverityHigh, Message: "Process is running from a deleted binary"},
+	}
+
+	want := []Change{{Field: "Warnings", Kind: Added, New: "Process is running from a deleted binary"}}
+	if got := Results(old, new); !reflect.DeepEqual(got, want) {
+		t.Errorf("Results() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+// TestResultsIgnoresReadings checks that what changes on every tick of a
+// watch, a client's connection or the usage a warning quotes, is not
+// reported, while a warning about something new is.
+func TestResultsIgnoresReadings(t *testing.T) {
+	cgroup := func(pct string) model.Warning {
+		return model.Warning{Code: model.WarnCgroupMemory, Severity: model.SeverityMedium,
+			Message:  "Cgroup memory at " + pct + " of limit",
+			Evidence: map[string]string{"cgroup": "/system.slice/nginx.service", "current": pct}}
+	}
+	public := func(addrs string) model.Warning {
+		return model.Warning{Code: model.WarnPublicBind, Severity: model.SeverityMedium,
+			Message: "Process is listening on a public interface", Evidence: map[string]string{"addresses": addrs}}
+	}
+	old := baseResult()
+	old.Process.Sockets = append(old.Process.Sockets, model.Socket{Protocol: "tcp", Address: "10.0.0.5", Port: 80, RemoteAddress: "10.0.0.9", RemotePort: 51234, State: "ESTABLISHED"})
+	old.Warnings = []model.Warning{cgroup("87%"), public("0.0.0.0:80")}
+	new := baseResult()
+	new.Process.Sockets = append(new.Process.Sockets, model.Socket{Protocol: "tcp", Address: "10.0.0.5", Port: 80, RemoteAddress: "10.0.0.9", RemotePort: 51240, State: "ESTABLISHED"})
+	new.Warnings = []model.Warning{cgroup("88%"), public("0.0.0.0:80, 0.0.0.0:9000")}
+
+	want := []Change{
+		{Field: "Warnings", Kind: Removed, Old: "W_PUBLIC_BIND: Process is listening on a public interface [addresses=0.0.0.0:80]"},
+		{Field: "Warnings", Kind: Added, New: "W_PUBLIC_BIND: Process is listening on a public interface [addresses=0.0.0.0:80, 0.0.0.0:9000]"},
+	}
+	if got := Results(old, new); !reflect.DeepEqual(got, want) {
+		t.Errorf("Results() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestResultsSourceAndDetails(t *testing.T) {
+	old := baseResult()
+	new := baseResult()
+	new.Source = model.Source{Type: model.SourceShell, Details: map[string]string{"tty": "pts/0"}}
+
+	want := []Change{
+		{Field: "Source", Kind: Changed, Old: "systemd: nginx.service", New: "shell"},
+		{Field: "Source.Details.NRestarts", Kind: Removed, Old: "0"},
+		{Field: "Source.Details.tty", Kind: Added, New: "pts/0"},
+	}
+	if got := Results(old, new); !reflect.DeepEqual(got, want) {
+		t.Errorf("Results() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestResultsEnvAndCapabilities(t *testing.T) {
+	old := baseResult()
+	old.Process.Env = []string{"MODE=blue", "DEBUG=1"}
+	old.Process.Capabilities = []string{"CAP_NET_BIND_SERVICE"}
+	new := baseResult()
+	new.Process.Env = []string{"MODE=green", "TZ=UTC"}
+	new.Process.Capabilities = []string{"CAP_NET_BIND_SERVICE", "CAP_SYS_ADMIN"}
+
+	want := []Change{
+		{Field: "Process.Env.DEBUG", Kind: Removed, Old: "1"},
+		{Field: "Process.Env.MODE", Kind: Changed, Old: "blue", New: "green"},
+		{Field: "Process.Env.TZ", Kind: Added, New: "UTC"},
+		{Field: "Process.Capabilities", Kind: Added, New: "CAP_SYS_ADMIN"},
+	}
+	if got := Results(old, new); !reflect.DeepEqual(got, want) {
+		t.Errorf("Results() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestResources(t *testing.T) {
+	old := baseResult()
+	old.Process.MemoryRSS = 100 << 20
+	old.Process.CPUPercent = 2.5
+	old.Process.FDCount = 40
+	new := baseResult()
+	new.Process.MemoryRSS = 90 << 20
+	new.Process.CPUPercent = 12
+	new.Process.FDCount = 40
+	new.Process.ThreadCount = 8
+
+	want := []Change{
+		{Field: "Process.CPUPercent", Kind: Changed, Old: "2.5%", New: "12.0%", Delta: "+9.5%"},
+		{Field: "Process.MemoryRSS", Kind: Changed, Old: "100.0 MB", New: "90.0 MB", Delta: "-10.0 MB"},
+		{Field: "Process.ThreadCount", Kind: Added, New: "8"},
+	}
+	if got := Resources(old, new); !reflect.DeepEqual(got, want) {
+		t.Errorf("Resources() =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -0,0 +1,73 @@
+package output
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// renderCgroup prints the verbose Cgroup section: the process's cgroup, and
+// for each controller it has, the usage against the limit and how often the
+// limit bit. Lines with nothing to say are left out.
+func renderCgroup(out Printer, cg *model.CgroupContext, colorEnabled bool) {
+	if cg == nil {
+		return
+	}
+	path := SanitizeTerminal(cg.Path)
+	if colorEnabled {
+		out.Printf("\n%sCgroup%s: %s\n", ColorGreen, ColorReset, path)
+	} else {
+		out.Printf("\nCgroup: %s\n", path)
+	}
+
+	if cg.MemoryCurrent > 0 || cg.MemoryMax > 0 {
+		out.Printf("  Memory   : %s\n", limitUsage(formatBytes(cg.MemoryCurrent), formatBytes(cg.MemoryMax), cg.MemoryCurrent, cg.MemoryMax))
+	}
+	if cg.OOMEvents > 0 || cg.OOMKills > 0 {
+		out.Printf("  OOM      : %s, limit reached %s\n", plural(cg.OOMKills, "kill"), plural(cg.OOMEvents, "time"))
+	}
+	if cg.CPUQuotaUsec > 0 || cg.CPUThrottled > 0 {
+		var parts []string
+		if cg.CPUQuotaUsec > 0 && cg.CPUPeriodUsec > 0 {
+			cpus := math.Round(float64(cg.CPUQuotaUsec)/float64(cg.CPUPeriodUsec)*100) / 100
+			parts = append(parts, "limited to "+strconv.FormatFloat(cpus, 'f', -1, 64)+" CPUs")
+		}
+		if cg.CPUThrottled > 0 {
+			throttled := (time.Duration(cg.CPUThrottledUsec) * time.Microsecond).Round(100 * time.Millisecond)
+			parts = append(parts, "throttled in "+strconv.FormatUint(cg.CPUThrottled, 10)+" of "+plural(cg.CPUPeriods, "period")+" ("+throttled.String()+")")
+		}
+		out.Printf("  CPU      : %s\n", strings.Join(parts, ", "))
+	}
+	if cg.PIDsCurrent > 0 {
+		out.Printf("  PIDs     : %s\n", limitUsage(strconv.FormatUint(cg.PIDsCurrent, 10), strconv.FormatUint(cg.PIDsMax, 10), cg.PIDsCurrent, cg.PIDsMax))
+	}
+	if len(cg.Pressure) > 0 {
+		var parts []string
+		for _, res := range []string{"cpu", "memory", "io"} {
+			if p, ok := cg.Pressure[res]; ok {
+				parts = append(parts, res+" "+strconv.FormatFloat(p.Some.Avg10, 'f', 2, 64)+"%")
+			}
+		}
+		out.Printf("  Pressure : %s stalled (10s avg)\n", strings.Join(parts, ", "))
+	}
+}
+
+// limitUsage renders usage against a limit: "1.9 GB of 2.0 GB (97%)", or
+// "1.9 GB (no limit)" when max is 0.
+func limitUsage(current, limit string, n, max uint64) string {
+	if max == 0 {
+		return current + " (no limit)"
+	}
+	return current + " of " + limit + " (" + strconv.FormatFloat(float64(n)/float64(max)*100, 'f', 0, 64) + "%)"
+}
+
+// plural renders a count with its noun: "1 kill", "3 kills".
+func plural(n uint64, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return strconv.FormatUint(n, 10) + " " + noun + "s"
+}
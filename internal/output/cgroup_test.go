@@ -0,0 +1,50 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestRenderCgroup(t *testing.T) {
+	t.Parallel()
+
+	res := fixedFixture()
+	res.CgroupContext = &model.CgroupContext{
+		Path:          "/system.slice/nginx.service",
+		MemoryCurrent: 1940 << 20, MemoryMax: 2 << 30,
+		OOMEvents: 5, OOMKills: 1,
+		CPUQuotaUsec: 50000, CPUPeriodUsec: 100000,
+		CPUPeriods: 4000, CPUThrottled: 120, CPUThrottledUsec: 12340000,
+		PIDsCurrent: 12,
+		Pressure: map[string]model.Pressure{
+			"memory": {Some: model.PressureStall{Avg10: 12.5}},
+			"cpu":    {Some: model.PressureStall{Avg10: 1.2}},
+		},
+	}
+
+	var buf bytes.Buffer
+	RenderStandard(&buf, res, false, true)
+	out := buf.String()
+	for _, s := range []string{
+		"Cgroup: /system.slice/nginx.service\n",
+		"  Memory   : 1.9 GB of 2.0 GB (95%)\n",
+		"  OOM      : 1 kill, limit reached 5 times\n",
+		"  CPU      : limited to 0.5 CPUs, throttled in 120 of 4000 periods (12.3s)\n",
+		"  PIDs     : 12 (no limit)\n",
+		"  Pressure : cpu 1.20%, memory 12.50% stalled (10s avg)\n",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("verbose output missing %q\n---\n%s\n---", s, out)
+		}
+	}
+
+	// Only --verbose shows it.
+	buf.Reset()
+	RenderStandard(&buf, res, false, false)
+	if strings.Contains(buf.String(), "Cgroup") {
+		t.Errorf("the Cgroup section should be verbose only:\n%s", buf.String())
+	}
+}
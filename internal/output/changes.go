@@ -0,0 +1,47 @@
+package output
+
+import (
+	"io"
+	"time"
+
+	"github.com/pranshuparmar/witr/internal/diff"
+)
+
+// RenderChanges prints one batch of changes under a timestamped heading:
+//
+//	15:04:05 nginx (pid 812)
+//	  ~ Process.PID: 812 → 901
+//	  + Sockets: tcp 0.0.0.0:8080 LISTEN
+func RenderChanges(w io.Writer, at time.Time, label string, changes []diff.Change, colorEnabled bool) {
+	out := NewPrinter(w)
+
+	if colorEnabled {
+		out.Printf("%s%s%s %s\n", ColorDim, at.Format("15:04:05"), ColorReset, label)
+	} else {
+		out.Printf("%s %s\n", at.Format("15:04:05"), label)
+	}
+	for _, c := range changes {
+		RenderChange(w, c, colorEnabled)
+	}
+}
+
+// RenderChange prints a single change as an indented "+", "-" or "~" line.
+func RenderChange(w io.Writer, c diff.Change, colorEnabled bool) {
+	out := NewPrinter(w)
+
+	mark, color, value := "~", ColorDimYellow, c.Old+" → "+c.New
+	if c.Delta != "" {
+		value += " (" + c.Delta + ")"
+	}
+	switch c.Kind {
+	case diff.Added:
+		mark, color, value = "+", ColorGreen, c.New
+	case diff.Removed:
+		mark, color, value = "-", ColorRed, c.Old
+	}
+	if colorEnabled {
+		out.Printf("  %s%s%s %s: %s\n", color, mark, ColorReset, c.Field, value)
+	} else {
+		out.Printf("  %s %s: %s\n", mark, c.Field, value)
+	}
+}
@@ -0,0 +1,43 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pranshuparmar/witr/internal/diff"
+)
+
+func TestRenderChanges(t *testing.T) {
+	at := time.Date(2026, 1, 2, 15, 4, 5, 0, time.Local)
+	changes := []diff.Change{
+		{Field: "Process.PID", Kind: diff.Changed, Old: "812", New: "901"},
+		{Field: "Sockets", Kind: diff.Added, New: "tcp 0.0.0.0:8080 LISTEN"},
+		{Field: "Children", Kind: diff.Removed, Old: "worker (pid 99)"},
+	}
+
+	var buf bytes.Buffer
+	RenderChanges(&buf, at, "port: 8080", changes, false)
+	want := "15:04:05 port: 8080\n" +
+		"  ~ Process.PID: 812 → 901\n" +
+		"  + Sockets: tcp 0.0.0.0:8080 LISTEN\n" +
+		"  - Children: worker (pid 99)\n"
+	if buf.String() != want {
+		t.Errorf("RenderChanges() =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	RenderChanges(&buf, at, "port: 8080", changes, true)
+	if !strings.Contains(buf.String(), string(ColorGreen)+"+") || !strings.Contains(buf.String(), string(ColorRed)+"-") {
+		t.Errorf("colored changes missing markers:\n%q", buf.String())
+	}
+}
+
+func TestRenderChangeSanitizes(t *testing.T) {
+	var buf bytes.Buffer
+	RenderChange(&buf, diff.Change{Field: "Process.Cmdline", Kind: diff.Added, New: "evil\nforged line"}, false)
+	if strings.Count(buf.String(), "\n") != 1 {
+		t.Errorf("a value with a newline must stay on one line, got %q", buf.String())
+	}
+}
@@ -0,0 +1,67 @@
+package output
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// renderClients prints the Connected Clients section: each local process
+// connected to the result's process, as its own causal chain followed by the
+// server ends it is connected to.
+func renderClients(out Printer, r model.Result, colorEnabled bool) {
+	if len(r.Clients) == 0 {
+		return
+	}
+	if colorEnabled {
+		out.Printf("\n%sConnected Clients%s :\n", ColorGreen, ColorReset)
+	} else {
+		out.Println(ansiString("\nConnected Clients :"))
+	}
+	for i, c := range r.Clients {
+		if i >= MaxDisplayItems {
+			out.Printf("  ... and %d more\n", len(r.Clients)-i)
+			break
+		}
+		out.Printf("  ")
+		for j, p := range c.Ancestry {
+			name := SanitizeTerminal(ChainName(p))
+			last := j == len(c.Ancestry)-1
+			switch {
+			case colorEnabled && last:
+				out.Printf("%s%s%s (%spid %d%s)", ColorGreen, name, ColorReset, ColorDim, p.PID, ColorReset)
+			case colorEnabled:
+				out.Printf("%s (%spid %d%s) %s→%s ", name, ColorDim, p.PID, ColorReset, ColorMagenta, ColorReset)
+			case last:
+				out.Printf("%s (pid %d)", name, p.PID)
+			default:
+				out.Printf("%s (pid %d) → ", name, p.PID)
+			}
+		}
+		out.Printf(" via %s\n", SanitizeTerminal(clientEnds(c)))
+	}
+}
+
+// clientEnds describes the server ends of a client's connections:
+// "127.0.0.1:5432 (3 connections)", or a unix socket's path.
+func clientEnds(c model.Client) string {
+	var ends []string
+	for _, s := range c.Sockets {
+		end := s.Path
+		if end == "" {
+			end = net.JoinHostPort(s.Address, strconv.Itoa(s.Port))
+		}
+		if !slices.Contains(ends, end) {
+			ends = append(ends, end)
+		}
+	}
+	desc := strings.Join(ends, ", ")
+	if len(c.Sockets) > 1 {
+		desc += fmt.Sprintf(" (%d connections)", len(c.Sockets))
+	}
+	return desc
+}
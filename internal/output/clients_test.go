@@ -0,0 +1,66 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestRenderClients(t *testing.T) {
+	chain := []model.Process{{PID: 1, Command: "systemd"}, {PID: 100, Command: "bash"}, {PID: 4242, Command: "psql"}}
+	conn := model.Socket{Address: "127.0.0.1", Port: 5432, Protocol: "TCP", State: "ESTABLISHED"}
+	r := model.Result{Clients: []model.Client{
+		{PID: 4242, Ancestry: chain, Sockets: []model.Socket{conn, conn}},
+		{PID: 900, Ancestry: []model.Process{{PID: 1, Command: "systemd"}, {PID: 900, Command: "backup"}},
+			Sockets: []model.Socket{{Path: "/run/postgresql/.s.PGSQL.5432", Protocol: "UNIX", Type: "stream", State: "ESTABLISHED"}}},
+	}}
+
+	var buf bytes.Buffer
+	renderClients(NewPrinter(&buf), r, false)
+	want := "\nConnected Clients :\n" +
+		"  systemd (pid 1) → bash (pid 100) → psql (pid 4242) via 127.0.0.1:5432 (2 connections)\n" +
+		"  systemd (pid 1) → backup (pid 900) via /run/postgresql/.s.PGSQL.5432\n"
+	if buf.String() != want {
+		t.Errorf("renderClients =\n%q\nwant\n%q", buf.String(), want)
+	}
+
+	buf.Reset()
+	renderClients(NewPrinter(&buf), r, true)
+	if !strings.Contains(buf.String(), "Connected Clients") || !strings.Contains(buf.String(), "psql") {
+		t.Errorf("colored render lost the section:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	renderClients(NewPrinter(&buf), model.Result{}, false)
+	if buf.Len() != 0 {
+		t.Errorf("no clients should print nothing, got %q", buf.String())
+	}
+
+	var many model.Result
+	for i := range MaxDisplayItems + 3 {
+		many.Clients = append(many.Clients, model.Client{
+			PID:      1000 + i,
+			Ancestry: []model.Process{{PID: 1000 + i, Command: fmt.Sprintf("client%d", i)}},
+			Sockets:  []model.Socket{conn},
+		})
+	}
+	buf.Reset()
+	renderClients(NewPrinter(&buf), many, false)
+	if !strings.Contains(buf.String(), "... and 3 more") {
+		t.Errorf("long client lists should be cut short:\n%s", buf.String())
+	}
+}
+
+func TestClientsJSON(t *testing.T) {
+	out, err := ToJSON(model.Result{})
+	if err != nil || strings.Contains(out, `"Clients"`) {
+		t.Errorf("a result without clients should leave them out: %v\n%s", err, out)
+	}
+	out, err = ToJSON(model.Result{Clients: []model.Client{{PID: 4242, Ancestry: []model.Process{{PID: 4242, Command: "psql"}}}}})
+	if err != nil || !strings.Contains(out, `"Clients"`) || !strings.Contains(out, `"PID": 4242`) {
+		t.Errorf("clients missing from JSON: %v\n%s", err, out)
+	}
+}
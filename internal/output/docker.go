@@ -1,7 +1,6 @@
 package output
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"strings"
@@ -285,67 +284,18 @@ func writeContainerChainInline(out Printer, segs []string, colorEnabled bool) {
 	}
 }
 
-func ContainerFallbackToJSON(targetLabel string, match *model.ContainerMatch) (string, error) {
-	type containerResult struct {
-		Target            string
-		Runtime           string
-		ContainerID       string
-		ContainerName     string
-		Image             string
-		Command           string `json:",omitempty"`
-		State             string `json:",omitempty"`
-		Status            string `json:",omitempty"`
-		Health            string `json:",omitempty"`
-		CreatedAt         string `json:",omitempty"`
-		StartedAt         string `json:",omitempty"`
-		Networks          string `json:",omitempty"`
-		Mounts            string `json:",omitempty"`
-		Ports             string `json:",omitempty"`
-		ComposeProject    string `json:",omitempty"`
-		ComposeService    string `json:",omitempty"`
-		ComposeConfigFile string `json:",omitempty"`
-		ComposeWorkingDir string `json:",omitempty"`
-		Source            string
-		Chain             []string
-		Note              string
+// ContainerFallbackResult describes a container whose processes are not
+// visible from this host as a model.Result for the --json report: the
+// runtime's view in Container, the runtime as the source, and no process.
+func ContainerFallbackResult(t model.Target, match *model.ContainerMatch) model.Result {
+	return model.Result{
+		Target:         t,
+		ResolvedTarget: match.Name,
+		Source: model.Source{
+			Type:        model.SourceContainer,
+			Name:        match.Runtime,
+			Description: containerSourceLabel(match),
+		},
+		Container: match,
 	}
-
-	created := ""
-	if !match.CreatedAt.IsZero() {
-		created = match.CreatedAt.Format("Mon 2006-01-02 15:04:05 -07:00")
-	}
-	started := ""
-	if !match.StartedAt.IsZero() {
-		started = match.StartedAt.Format("Mon 2006-01-02 15:04:05 -07:00")
-	}
-
-	res := containerResult{
-		Target:            targetLabel,
-		Runtime:           match.Runtime,
-		ContainerID:       match.ID,
-		ContainerName:     match.Name,
-		Image:             match.Image,
-		Command:           match.Command,
-		State:             match.State,
-		Status:            match.Status,
-		Health:            match.Health,
-		CreatedAt:         created,
-		StartedAt:         started,
-		Networks:          match.Networks,
-		Mounts:            match.Mounts,
-		Ports:             match.Ports,
-		ComposeProject:    match.ComposeProject,
-		ComposeService:    match.ComposeService,
-		ComposeConfigFile: match.ComposeConfigFile,
-		ComposeWorkingDir: match.ComposeWorkingDir,
-		Source:            containerSourceLabel(match),
-		Chain:             containerChain(match),
-		Note:              "The owning process is not visible in this environment. This is common when the runtime runs in a separate namespace (e.g., Docker Desktop, WSL2 distro, macOS VM).",
-	}
-
-	data, err := json.MarshalIndent(res, "", "  ")
-	if err != nil {
-		return "", err
-	}
-	return string(data), nil
 }
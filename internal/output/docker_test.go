@@ -2,7 +2,6 @@ package output
 
 import (
 	"bytes"
-	"encoding/json"
 	"strings"
 	"testing"
 
@@ -157,7 +156,7 @@ func TestRenderContainerFallbackWarnings(t *testing.T) {
 	}
 }
 
-func TestContainerFallbackToJSON(t *testing.T) {
+func TestContainerFallbackResult(t *testing.T) {
 	match := &model.ContainerMatch{
 		Runtime: "docker",
 		ID:      "abc123",
@@ -165,54 +164,19 @@ func TestContainerFallbackToJSON(t *testing.T) {
 		Image:   "gcr.io/cloud-sql-connectors/cloud-sql-proxy:2.13.0",
 		Ports:   "127.0.0.1:5432->5432/tcp",
 	}
+	target := model.Target{Type: model.TargetPort, Value: "5432"}
 
-	jsonStr, err := ContainerFallbackToJSON("port 5432", match)
-	if err != nil {
-		t.Fatalf("ContainerFallbackToJSON() error: %v", err)
+	res := ContainerFallbackResult(target, match)
+	if res.Target != target || res.ResolvedTarget != "sql-proxy" || res.Container != match {
+		t.Errorf("ContainerFallbackResult = %+v, want the target and the container", res)
 	}
-
-	var result map[string]interface{}
-	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
-		t.Fatalf("invalid JSON output: %v", err)
-	}
-
-	if result["Target"] != "port 5432" {
-		t.Errorf("Target = %v, want %q", result["Target"], "port 5432")
-	}
-	if result["ContainerName"] != "sql-proxy" {
-		t.Errorf("ContainerName = %v, want %q", result["ContainerName"], "sql-proxy")
-	}
-	if result["Runtime"] != "docker" {
-		t.Errorf("Runtime = %v, want %q", result["Runtime"], "docker")
-	}
-	if result["Source"] != "docker" {
-		t.Errorf("Source = %v, want %q", result["Source"], "docker")
-	}
-}
-
-func TestContainerFallbackToJSONCompose(t *testing.T) {
-	match := &model.ContainerMatch{
-		Runtime:        "docker",
-		ID:             "def456",
-		Name:           "myapp-db-1",
-		Image:          "postgres:16",
-		Ports:          "0.0.0.0:5432->5432/tcp",
-		ComposeProject: "myapp",
-		ComposeService: "db",
-	}
-
-	jsonStr, err := ContainerFallbackToJSON("port 5432", match)
-	if err != nil {
-		t.Fatalf("ContainerFallbackToJSON() error: %v", err)
-	}
-
-	var result map[string]interface{}
-	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
-		t.Fatalf("invalid JSON output: %v", err)
+	if res.Source.Type != model.SourceContainer || res.Source.Name != "docker" || res.Source.Description != "docker" {
+		t.Errorf("Source = %+v, want the docker runtime", res.Source)
 	}
 
-	if result["Source"] != "docker-compose: myapp/db" {
-		t.Errorf("Source = %v, want %q", result["Source"], "docker-compose: myapp/db")
+	match.ComposeProject, match.ComposeService = "myapp", "db"
+	if got := ContainerFallbackResult(target, match).Source.Description; got != "docker-compose: myapp/db" {
+		t.Errorf("Source.Description = %q, want %q", got, "docker-compose: myapp/db")
 	}
 }
 
@@ -12,7 +12,7 @@ func TestRenderWarnings(t *testing.T) {
 	r := model.Result{
 		Process:  model.Process{PID: 1234, Command: "nginx"},
 		Ancestry: []model.Process{{PID: 1, Command: "systemd"}, {PID: 1234, Command: "nginx"}},
-		Warnings: []string{"Process is running as root"},
+		Warnings: []model.Warning{{Code: model.WarnRoot, Severity: model.SeverityLow, Message: "Process is running as root"}},
 	}
 
 	for _, color := range []bool{false, true} {
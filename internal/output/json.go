@@ -14,66 +14,30 @@ func ToJSON(r model.Result) (string, error) {
 	return string(data), nil
 }
 
-type shortProcess struct {
-	PID     int
-	Command string
-}
-
-func ToShortJSON(r model.Result) (string, error) {
-	ancestry := make([]shortProcess, len(r.Ancestry))
-	for i, p := range r.Ancestry {
-		ancestry[i] = shortProcess{PID: p.PID, Command: p.Command}
-	}
-	data, err := json.MarshalIndent(ancestry, "", "  ")
-	if err != nil {
-		return "", err
+func processRefs(ps []model.Process) []model.ProcessRef {
+	refs := make([]model.ProcessRef, len(ps))
+	for i, p := range ps {
+		refs[i] = model.ProcessRef{PID: p.PID, Command: p.Command}
 	}
-	return string(data), nil
+	return refs
 }
 
-func ToTreeJSON(r model.Result) (string, error) {
-	type treeResult struct {
-		Ancestry []shortProcess
-		Children []shortProcess `json:",omitempty"`
-	}
-
-	res := treeResult{
-		Ancestry: make([]shortProcess, len(r.Ancestry)),
-	}
-
-	for i, p := range r.Ancestry {
-		res.Ancestry[i] = shortProcess{PID: p.PID, Command: p.Command}
-	}
+// ShortViewOf is r in the --short --json view.
+func ShortViewOf(r model.Result) model.ShortView {
+	return model.ShortView{Target: r.Target, Ancestry: processRefs(r.Ancestry)}
+}
 
+// TreeViewOf is r in the --tree --json view.
+func TreeViewOf(r model.Result) model.TreeView {
+	v := model.TreeView{Target: r.Target, Ancestry: processRefs(r.Ancestry)}
 	if len(r.Children) > 0 {
-		res.Children = make([]shortProcess, len(r.Children))
-		for i, p := range r.Children {
-			res.Children[i] = shortProcess{PID: p.PID, Command: p.Command}
-		}
+		v.Children = processRefs(r.Children)
 	}
-
-	data, err := json.MarshalIndent(res, "", "  ")
-	if err != nil {
-		return "", err
-	}
-	return string(data), nil
+	return v
 }
 
-func ToWarningsJSON(r model.Result) (string, error) {
-	type warningResult struct {
-		PID      int
-		Process  string
-		Command  string
-		Warnings []string
-	}
-
-	procName := "unknown"
-	if len(r.Ancestry) > 0 {
-		procName = r.Ancestry[len(r.Ancestry)-1].Command
-	} else if r.Process.Command != "" {
-		procName = r.Process.Command
-	}
-
+// WarningsViewOf is r in the --warnings --json view.
+func WarningsViewOf(r model.Result) model.WarningsView {
 	cmdLine := r.Process.Cmdline
 	if cmdLine == "" {
 		cmdLine = r.Process.Command
@@ -81,48 +45,36 @@ func ToWarningsJSON(r model.Result) (string, error) {
 
 	warnings := r.Warnings
 	if warnings == nil {
-		warnings = []string{}
-	}
-
-	res := warningResult{
-		PID:      r.Process.PID,
-		Process:  procName,
-		Command:  cmdLine,
-		Warnings: warnings,
+		warnings = []model.Warning{}
 	}
 
-	data, err := json.MarshalIndent(res, "", "  ")
-	if err != nil {
-		return "", err
+	return model.WarningsView{
+		Target:     r.Target,
+		PID:        r.Process.PID,
+		Process:    viewProcessName(r),
+		Command:    cmdLine,
+		Warnings:   warnings,
+		Suppressed: r.Suppressed,
 	}
-	return string(data), nil
 }
 
-func ToEnvJSON(r model.Result) (string, error) {
-	type envResult struct {
-		PID     int
-		Process string
-		Command string
-		Env     []string
-	}
-
-	procName := "unknown"
-	if len(r.Ancestry) > 0 {
-		procName = r.Ancestry[len(r.Ancestry)-1].Command
-	} else if r.Process.Command != "" {
-		procName = r.Process.Command
-	}
-
-	res := envResult{
+// EnvViewOf is r in the --env --json view.
+func EnvViewOf(r model.Result) model.EnvView {
+	return model.EnvView{
+		Target:  r.Target,
 		PID:     r.Process.PID,
-		Process: procName,
+		Process: viewProcessName(r),
 		Command: r.Process.Cmdline,
 		Env:     r.Process.Env,
 	}
+}
 
-	data, err := json.MarshalIndent(res, "", "  ")
-	if err != nil {
-		return "", err
+func viewProcessName(r model.Result) string {
+	if len(r.Ancestry) > 0 {
+		return r.Ancestry[len(r.Ancestry)-1].Command
 	}
-	return string(data), nil
+	if r.Process.Command != "" {
+		return r.Process.Command
+	}
+	return "unknown"
 }
@@ -21,7 +21,7 @@ func jsonFixture() model.Result {
 		Ancestry: []model.Process{{PID: 1, Command: "systemd"}, target},
 		Children: []model.Process{{PID: 5678, Command: "worker"}},
 		Source:   model.Source{Type: model.SourceSystemd, Name: "nginx.service"},
-		Warnings: []string{"Process is listening on a public interface"},
+		Warnings: []model.Warning{{Code: model.WarnPublicBind, Severity: model.SeverityMedium, Message: "Process is listening on a public interface"}},
 	}
 }
 
@@ -47,133 +47,93 @@ func TestToJSONIsValidAndIncludesKeyFields(t *testing.T) {
 	}
 }
 
-func TestToShortJSON(t *testing.T) {
-	t.Parallel()
-
-	s, err := ToShortJSON(jsonFixture())
+func mustMarshal(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
-		t.Fatalf("ToShortJSON: %v", err)
+		t.Fatal(err)
 	}
+	return string(data)
+}
 
-	var got []struct {
-		PID     int
-		Command string
-	}
-	if err := json.Unmarshal([]byte(s), &got); err != nil {
-		t.Fatalf("ToShortJSON not parseable as []shortProcess: %v\n%s", err, s)
-	}
-	if len(got) != 2 {
-		t.Fatalf("ToShortJSON length = %d, want 2", len(got))
+func TestShortViewOf(t *testing.T) {
+	t.Parallel()
+
+	got := ShortViewOf(jsonFixture())
+	if got.Target.Value != "nginx" || len(got.Ancestry) != 2 {
+		t.Fatalf("ShortViewOf = %+v, want the target and two ancestors", got)
 	}
-	if got[0].Command != "systemd" || got[1].Command != "nginx" {
-		t.Errorf("ToShortJSON unexpected ancestry: %+v", got)
+	if got.Ancestry[0].Command != "systemd" || got.Ancestry[1].Command != "nginx" {
+		t.Errorf("ShortViewOf unexpected ancestry: %+v", got.Ancestry)
 	}
 }
 
-func TestToTreeJSONIncludesChildren(t *testing.T) {
+func TestTreeViewOfIncludesChildren(t *testing.T) {
 	t.Parallel()
 
-	s, err := ToTreeJSON(jsonFixture())
-	if err != nil {
-		t.Fatalf("ToTreeJSON: %v", err)
-	}
-
-	var got struct {
-		Ancestry []struct{ PID int }
-		Children []struct {
-			PID     int
-			Command string
-		}
-	}
-	if err := json.Unmarshal([]byte(s), &got); err != nil {
-		t.Fatalf("ToTreeJSON not parseable: %v\n%s", err, s)
-	}
+	got := TreeViewOf(jsonFixture())
 	if len(got.Children) != 1 || got.Children[0].PID != 5678 || got.Children[0].Command != "worker" {
-		t.Errorf("ToTreeJSON children = %+v, want one worker pid 5678", got.Children)
+		t.Errorf("TreeViewOf children = %+v, want one worker pid 5678", got.Children)
 	}
 }
 
-// TestToTreeJSONOmitsEmptyChildren pins the `omitempty` contract — callers
+// TestTreeViewOmitsEmptyChildren pins the `omitempty` contract — callers
 // scripting against the JSON shouldn't need to special-case "Children":[].
-func TestToTreeJSONOmitsEmptyChildren(t *testing.T) {
+func TestTreeViewOmitsEmptyChildren(t *testing.T) {
 	t.Parallel()
 
 	fx := jsonFixture()
 	fx.Children = nil
 
-	s, err := ToTreeJSON(fx)
-	if err != nil {
-		t.Fatalf("ToTreeJSON: %v", err)
-	}
-	if strings.Contains(s, `"Children"`) {
-		t.Errorf("ToTreeJSON should omit Children when empty; got:\n%s", s)
+	if s := mustMarshal(t, TreeViewOf(fx)); strings.Contains(s, `"Children"`) {
+		t.Errorf("TreeViewOf should omit Children when empty; got:\n%s", s)
 	}
 }
 
-func TestToWarningsJSON(t *testing.T) {
+func TestWarningsViewOf(t *testing.T) {
 	t.Parallel()
 
-	s, err := ToWarningsJSON(jsonFixture())
-	if err != nil {
-		t.Fatalf("ToWarningsJSON: %v", err)
-	}
-
 	var got struct {
 		PID      int
 		Process  string
 		Command  string
-		Warnings []string
+		Warnings []struct{ Code, Severity, Message string }
 	}
+	s := mustMarshal(t, WarningsViewOf(jsonFixture()))
 	if err := json.Unmarshal([]byte(s), &got); err != nil {
-		t.Fatalf("ToWarningsJSON not parseable: %v\n%s", err, s)
+		t.Fatalf("WarningsViewOf not parseable: %v\n%s", err, s)
 	}
 	if got.PID != 1234 || got.Process != "nginx" {
-		t.Errorf("ToWarningsJSON identity wrong: %+v", got)
+		t.Errorf("WarningsViewOf identity wrong: %+v", got)
 	}
-	if len(got.Warnings) != 1 || !strings.Contains(got.Warnings[0], "public interface") {
-		t.Errorf("ToWarningsJSON warnings = %v", got.Warnings)
+	if len(got.Warnings) != 1 || got.Warnings[0].Code != "W_PUBLIC_BIND" || got.Warnings[0].Severity != "medium" ||
+		!strings.Contains(got.Warnings[0].Message, "public interface") {
+		t.Errorf("WarningsViewOf warnings = %v", got.Warnings)
 	}
 }
 
-// TestToWarningsJSONNilWarningsBecomesEmptyArray ensures downstream
+// TestWarningsViewNilWarningsBecomesEmptyArray ensures downstream
 // consumers always see [] and never null — important for jq scripts that do
 // `.Warnings | length`.
-func TestToWarningsJSONNilWarningsBecomesEmptyArray(t *testing.T) {
+func TestWarningsViewNilWarningsBecomesEmptyArray(t *testing.T) {
 	t.Parallel()
 
 	fx := jsonFixture()
 	fx.Warnings = nil
 
-	s, err := ToWarningsJSON(fx)
-	if err != nil {
-		t.Fatalf("ToWarningsJSON: %v", err)
-	}
-	if !strings.Contains(s, `"Warnings": []`) {
-		t.Errorf("ToWarningsJSON should emit Warnings:[] when nil; got:\n%s", s)
+	if s := mustMarshal(t, WarningsViewOf(fx)); !strings.Contains(s, `"Warnings": []`) {
+		t.Errorf("WarningsViewOf should emit Warnings:[] when nil; got:\n%s", s)
 	}
 }
 
-func TestToEnvJSON(t *testing.T) {
+func TestEnvViewOf(t *testing.T) {
 	t.Parallel()
 
-	s, err := ToEnvJSON(jsonFixture())
-	if err != nil {
-		t.Fatalf("ToEnvJSON: %v", err)
-	}
-
-	var got struct {
-		PID     int
-		Process string
-		Command string
-		Env     []string
-	}
-	if err := json.Unmarshal([]byte(s), &got); err != nil {
-		t.Fatalf("ToEnvJSON not parseable: %v\n%s", err, s)
-	}
-	if got.PID != 1234 {
-		t.Errorf("ToEnvJSON PID = %d, want 1234", got.PID)
+	got := EnvViewOf(jsonFixture())
+	if got.PID != 1234 || got.Process != "nginx" {
+		t.Errorf("EnvViewOf identity = %d %q, want 1234 nginx", got.PID, got.Process)
 	}
 	if len(got.Env) != 2 || got.Env[0] != "FOO=bar" {
-		t.Errorf("ToEnvJSON env = %v", got.Env)
+		t.Errorf("EnvViewOf env = %v", got.Env)
 	}
 }
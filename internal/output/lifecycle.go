@@ -0,0 +1,66 @@
+package output
+
+import (
+	"strconv"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// renderLifecycle prints the Recent Lifecycle section of a systemd unit:
+// how its main process last exited and when it came back, followed by the
+// unit's last journal entries. It is left out for a unit that never
+// restarted or failed, which has nothing to explain.
+func renderLifecycle(out Printer, r model.Result, colorEnabled bool) {
+	lc := r.Source.Lifecycle
+	if lc == nil || (r.RestartCount == 0 && lc.LastExit == nil && (lc.Result == "" || lc.Result == "success")) {
+		return
+	}
+	if colorEnabled {
+		out.Printf("\n%sRecent Lifecycle%s :\n", ColorMagenta, ColorReset)
+	} else {
+		out.Println(ansiString("\nRecent Lifecycle :"))
+	}
+
+	if lc.LastExit != nil {
+		rel, _ := FormatStartedAt(lc.LastExit.Time)
+		exit := formatUnitExit(*lc.LastExit)
+		if colorEnabled {
+			out.Printf("  Last exit : %s%s%s %s\n", ColorRed, exit, ColorReset, rel)
+		} else {
+			out.Printf("  Last exit : %s %s\n", exit, rel)
+		}
+	} else if lc.Result != "" && lc.Result != "success" {
+		out.Printf("  Result    : %s\n", SanitizeTerminal(lc.Result))
+	}
+	if !lc.ActiveSince.IsZero() {
+		rel, _ := FormatStartedAt(lc.ActiveSince)
+		out.Printf("  Active    : since %s\n", rel)
+	}
+	for i, e := range lc.Journal {
+		label := "  Journal   : "
+		if i > 0 {
+			label = "              "
+		}
+		out.Printf("%s%s %s\n", label, e.Time.Local().Format("Jan 02 15:04:05"), SanitizeTerminal(e.Message))
+	}
+}
+
+// formatUnitExit renders how a main process exited: "SIGKILL (oom-kill)",
+// "SIGSEGV, core dumped" or "status 1 (exit-code)".
+func formatUnitExit(e model.UnitExit) string {
+	var s string
+	switch {
+	case e.Signal != "" && e.Code == "dumped":
+		s = e.Signal + ", core dumped"
+	case e.Signal != "":
+		s = e.Signal
+	case e.Code == "killed" || e.Code == "dumped":
+		s = "signal " + strconv.Itoa(e.Status)
+	default:
+		s = "status " + strconv.Itoa(e.Status)
+	}
+	if e.Result != "" {
+		s += " (" + e.Result + ")"
+	}
+	return SanitizeTerminal(s)
+}
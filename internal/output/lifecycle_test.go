@@ -0,0 +1,69 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestRenderLifecycle(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	res := fixedFixture()
+	res.RestartCount = 14
+	res.Source.Lifecycle = &model.UnitLifecycle{
+		Result:      "success",
+		ActiveSince: now.Add(-2 * time.Minute),
+		LastExit:    &model.UnitExit{Time: now.Add(-3 * time.Minute), Code: "killed", Status: 9, Signal: "SIGKILL", Result: "oom-kill"},
+		Journal: []model.JournalEntry{
+			{Time: now.Add(-3 * time.Minute), Message: "nginx.service: Main process exited, code=killed, status=9/KILL"},
+			{Time: now.Add(-3 * time.Minute), Message: "nginx.service: Failed with result 'oom-kill'."},
+		},
+	}
+
+	var buf bytes.Buffer
+	RenderStandard(&buf, res, false, false)
+	out := buf.String()
+	for _, s := range []string{
+		"Restarts    : 14\n",
+		"\nRecent Lifecycle :\n",
+		"  Last exit : SIGKILL (oom-kill) 3 min ago\n",
+		"  Active    : since 2 min ago\n",
+		"  Journal   : " + now.Add(-3*time.Minute).Format("Jan 02 15:04:05") + " nginx.service: Main process exited",
+		"              " + now.Add(-3*time.Minute).Format("Jan 02 15:04:05") + " nginx.service: Failed with result 'oom-kill'.",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("output missing %q\n---\n%s\n---", s, out)
+		}
+	}
+
+	// A healthy unit that never restarted has nothing to explain.
+	res.RestartCount = 0
+	res.Source.Lifecycle = &model.UnitLifecycle{Result: "success", ActiveSince: now.Add(-time.Hour)}
+	buf.Reset()
+	RenderStandard(&buf, res, false, false)
+	if strings.Contains(buf.String(), "Recent Lifecycle") {
+		t.Errorf("a healthy unit should have no Recent Lifecycle section:\n%s", buf.String())
+	}
+}
+
+func TestFormatUnitExit(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		exit model.UnitExit
+		want string
+	}{
+		{model.UnitExit{Code: "exited", Status: 1, Result: "exit-code"}, "status 1 (exit-code)"},
+		{model.UnitExit{Code: "dumped", Status: 11, Signal: "SIGSEGV"}, "SIGSEGV, core dumped"},
+		{model.UnitExit{Code: "killed", Status: 64}, "signal 64"},
+	} {
+		if got := formatUnitExit(tt.exit); got != tt.want {
+			t.Errorf("formatUnitExit(%+v) = %q, want %q", tt.exit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// RenderPortTable prints the ports a --port range found bound, one row per
+// port with its address, owner and source. A NETNS column is added when a
+// port is in another network namespace.
+func RenderPortTable(w io.Writer, t model.PortTable, colorEnabled bool) {
+	p := NewPrinter(w)
+
+	noun := "ports"
+	if len(t.Ports) == 1 {
+		noun = "port"
+	}
+	if colorEnabled {
+		p.Printf("%sPorts%s       : %d bound %s in %s\n\n", ColorCyan, ColorReset, len(t.Ports), noun, SanitizeTerminal(t.Target.Value))
+	} else {
+		p.Printf("Ports       : %d bound %s in %s\n\n", len(t.Ports), noun, SanitizeTerminal(t.Target.Value))
+	}
+
+	withNetns := slices.ContainsFunc(t.Ports, func(bp model.BoundPort) bool { return bp.Netns != "" })
+	header := []string{"PORT", "PROTO", "ADDRESS", "PID", "PROCESS", "SOURCE"}
+	if withNetns {
+		header = slices.Insert(header, 3, "NETNS")
+	}
+	rows := make([][]string, len(t.Ports))
+	widths := make([]int, len(header))
+	for i, h := range header {
+		widths[i] = len(h)
+	}
+	for i, bp := range t.Ports {
+		rows[i] = []string{
+			strconv.Itoa(bp.Port),
+			bp.Protocol,
+			bp.Address,
+			strconv.Itoa(bp.PID),
+			SanitizeTerminal(bp.Process),
+			boundPortSource(bp),
+		}
+		if withNetns {
+			netns := bp.Netns
+			if netns == "" {
+				netns = "-"
+			}
+			rows[i] = slices.Insert(rows[i], 3, netns)
+		}
+		for k, cell := range rows[i] {
+			widths[k] = max(widths[k], len(cell))
+		}
+	}
+
+	line := func(cells []string) string {
+		var b strings.Builder
+		for k, cell := range cells {
+			if k == len(cells)-1 {
+				b.WriteString(cell)
+				break
+			}
+			fmt.Fprintf(&b, "%-*s  ", widths[k], cell)
+		}
+		return b.String()
+	}
+	if colorEnabled {
+		p.Printf("  %s%s%s\n", ColorDim, line(header), ColorReset)
+	} else {
+		p.Printf("  %s\n", line(header))
+	}
+	for _, row := range rows {
+		p.Printf("  %s\n", line(row))
+	}
+}
+
+// boundPortSource names what started the owner of bp, as the Source line
+// does: "nginx.service (systemd)", or the type alone.
+func boundPortSource(bp model.BoundPort) string {
+	label := string(bp.SourceType)
+	if label == "" {
+		label = string(model.SourceUnknown)
+	}
+	name := SanitizeTerminal(bp.SourceName)
+	if name != "" && name != label {
+		return fmt.Sprintf("%s (%s)", name, label)
+	}
+	return label
+}
@@ -0,0 +1,42 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestRenderPortTable(t *testing.T) {
+	table := model.PortTable{
+		Target: model.Target{Type: model.TargetPort, Value: "8000-8100"},
+		Ports: []model.BoundPort{
+			{Port: 8000, Protocol: "TCP", Address: "0.0.0.0", PID: 812, Process: "gunicorn", SourceType: model.SourceSystemd, SourceName: "app.service"},
+			{Port: 8053, Protocol: "UDP6", Address: "::1", PID: 4242, Process: "node", SourceType: model.SourceShell},
+		},
+	}
+
+	var buf bytes.Buffer
+	RenderPortTable(&buf, table, false)
+	want := "Ports       : 2 bound ports in 8000-8100\n\n" +
+		"  PORT  PROTO  ADDRESS  PID   PROCESS   SOURCE\n" +
+		"  8000  TCP    0.0.0.0  812   gunicorn  app.service (systemd)\n" +
+		"  8053  UDP6   ::1      4242  node      shell\n"
+	if buf.String() != want {
+		t.Errorf("RenderPortTable =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	RenderPortTable(&buf, table, true)
+	if !strings.Contains(buf.String(), "gunicorn") || !strings.Contains(buf.String(), string(ColorCyan)) {
+		t.Errorf("colored table lost its rows or color:\n%s", buf.String())
+	}
+
+	table.Ports[1].Netns = "4026532289"
+	buf.Reset()
+	RenderPortTable(&buf, table, false)
+	if !strings.Contains(buf.String(), "  PORT  PROTO  ADDRESS  NETNS       PID ") || !strings.Contains(buf.String(), "0.0.0.0  -           812") {
+		t.Errorf("a port in another namespace should add a NETNS column:\n%s", buf.String())
+	}
+}
@@ -1,6 +1,7 @@
 package output
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/pranshuparmar/witr/pkg/model"
@@ -44,3 +45,17 @@ func ChainName(p model.Process) string {
 	}
 	return "(unknown)"
 }
+
+// pidText labels a process's PID: "pid 48211", or "pid 48211, pid 1 in
+// container" when it is in a nested PID namespace, with its PID there.
+func pidText(p model.Process) string {
+	text := fmt.Sprintf("pid %d", p.PID)
+	if n := len(p.NamespacePIDs); n > 1 && p.NamespacePIDs[n-1] != p.PID {
+		where := "its namespace"
+		if p.Container != "" || p.ContainerID != "" {
+			where = "container"
+		}
+		text += fmt.Sprintf(", pid %d in %s", p.NamespacePIDs[n-1], where)
+	}
+	return text
+}
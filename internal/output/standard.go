@@ -39,14 +39,14 @@ func RenderWarnings(w io.Writer, r model.Result, colorEnabled bool) {
 	proc.Command = SanitizeTerminal(proc.Command)
 
 	if colorEnabled {
-		out.Printf("%sProcess%s     : %s%s%s (%spid %d%s)\n", ColorBlue, ColorReset, ColorGreen, proc.Command, ColorReset, ColorDim, proc.PID, ColorReset)
+		out.Printf("%sProcess%s     : %s%s%s (%s%s%s)\n", ColorBlue, ColorReset, ColorGreen, proc.Command, ColorReset, ColorDim, pidText(proc), ColorReset)
 		if proc.Cmdline != "" {
 			out.Printf("%sCommand%s     : %s\n", ColorBlue, ColorReset, proc.Cmdline)
 		} else {
 			out.Printf("%sCommand%s     : %s\n", ColorBlue, ColorReset, proc.Command)
 		}
 	} else {
-		out.Printf("Process     : %s (pid %d)\n", proc.Command, proc.PID)
+		out.Printf("Process     : %s (%s)\n", proc.Command, pidText(proc))
 		if proc.Cmdline != "" {
 			out.Printf("Command     : %s\n", proc.Cmdline)
 		} else {
@@ -60,20 +60,16 @@ func RenderWarnings(w io.Writer, r model.Result, colorEnabled bool) {
 		} else {
 			out.Println("Warnings    : No warnings.")
 		}
-		return
-	}
-
-	if colorEnabled {
-		out.Printf("%sWarnings%s    :\n", ColorRed, ColorReset)
-		for _, w := range r.Warnings {
-			out.Printf("  • %s\n", SanitizeTerminal(w))
-		}
 	} else {
-		out.Println("Warnings    :")
-		for _, w := range r.Warnings {
-			out.Printf("  • %s\n", SanitizeTerminal(w))
+		if colorEnabled {
+			out.Printf("%sWarnings%s    :\n", ColorRed, ColorReset)
+		} else {
+			out.Println("Warnings    :")
 		}
+		renderWarningList(out, r.Warnings, colorEnabled, true)
 	}
+	renderSuppressed(out, r, colorEnabled, true)
+	renderIncomplete(out, r, colorEnabled)
 }
 
 func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool) {
@@ -100,9 +96,9 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 	proc.GitRepo = SanitizeTerminal(proc.GitRepo)
 	proc.GitBranch = SanitizeTerminal(proc.GitBranch)
 	if colorEnabled {
-		out.Printf("%sProcess%s     : %s%s%s (%spid %d%s)", ColorBlue, ColorReset, ColorGreen, proc.Command, ColorReset, ColorDim, proc.PID, ColorReset)
+		out.Printf("%sProcess%s     : %s%s%s (%s%s%s)", ColorBlue, ColorReset, ColorGreen, proc.Command, ColorReset, ColorDim, pidText(proc), ColorReset)
 	} else {
-		out.Printf("Process     : %s (pid %d)", proc.Command, proc.PID)
+		out.Printf("Process     : %s (%s)", proc.Command, pidText(proc))
 	}
 	// Health status
 	if proc.Health != "" && proc.Health != "healthy" {
@@ -179,7 +175,8 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 		}
 	}
 
-	if schedule, ok := r.Source.Details["schedule"]; ok {
+	if _, ok := r.Source.Details["schedule"]; ok {
+		schedule := scheduleText(r.Source.Details)
 		if colorEnabled {
 			out.Printf("%sSchedule%s    : %s\n", ColorMagenta, ColorReset, schedule)
 		} else {
@@ -197,36 +194,45 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 			if i == len(r.Ancestry)-1 {
 				nameColor = ColorGreen
 			}
-			out.Printf("%s%s%s (%spid %d%s)", nameColor, name, ColorReset, ColorDim, p.PID, ColorReset)
+			out.Printf("%s%s%s (%s%s%s)", nameColor, name, ColorReset, ColorDim, pidText(p), ColorReset)
 			if i < len(r.Ancestry)-1 {
 				out.Printf(" %s\u2192%s ", ColorMagenta, ColorReset)
 			}
 		}
-		out.Print(ansiString("\n\n"))
+		out.Println()
+		renderActivation(out, r, colorEnabled)
+		out.Println()
 	} else {
 		out.Printf("\nWhy It Exists :\n  ")
 		for i, p := range r.Ancestry {
 			name := SanitizeTerminal(ChainName(p))
-			out.Printf("%s (pid %d)", name, p.PID)
+			out.Printf("%s (%s)", name, pidText(p))
 			if i < len(r.Ancestry)-1 {
 				out.Printf(" \u2192 ")
 			}
 		}
-		out.Print(ansiString("\n\n"))
+		out.Println()
+		renderActivation(out, r, colorEnabled)
+		out.Println()
 	}
 
 	// Source
 	sourceLabel := string(r.Source.Type)
 	sourceName := SanitizeTerminal(r.Source.Name)
+	// A user unit is labeled with the user manager it belongs to.
+	sourceKind := sourceLabel
+	if r.Source.UserManager != "" {
+		sourceKind += " --user, " + SanitizeTerminal(r.Source.UserManager)
+	}
 	if colorEnabled {
 		if r.Source.Name != "" && r.Source.Name != sourceLabel {
-			out.Printf("%sSource%s      : %s (%s)\n", ColorCyan, ColorReset, sourceName, sourceLabel)
+			out.Printf("%sSource%s      : %s (%s)\n", ColorCyan, ColorReset, sourceName, sourceKind)
 		} else {
 			out.Printf("%sSource%s      : %s\n", ColorCyan, ColorReset, sourceLabel)
 		}
 	} else {
 		if r.Source.Name != "" && r.Source.Name != sourceLabel {
-			out.Printf("Source      : %s (%s)\n", sourceName, sourceLabel)
+			out.Printf("Source      : %s (%s)\n", sourceName, sourceKind)
 		} else {
 			out.Printf("Source      : %s\n", sourceLabel)
 		}
@@ -260,12 +266,18 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 			pad = " "
 		}
 
+		unitFile := r.Source.UnitFile
+		if r.Source.Unit != nil && r.Source.Unit.FileState != "" {
+			unitFile += " (" + r.Source.Unit.FileState + ")"
+		}
 		if colorEnabled {
-			out.Printf("%s%s%s%s: %s\n", ColorCyan, label, ColorReset, pad, r.Source.UnitFile)
+			out.Printf("%s%s%s%s: %s\n", ColorCyan, label, ColorReset, pad, unitFile)
 		} else {
-			out.Printf("%s%s: %s\n", label, pad, r.Source.UnitFile)
+			out.Printf("%s%s: %s\n", label, pad, unitFile)
 		}
 	}
+	renderUnitConfig(out, r, colorEnabled, verbose)
+	renderSocketActivation(out, r, colorEnabled)
 
 	// Source details (launchd triggers, plist path, etc.)
 	if len(r.Source.Details) > 0 {
@@ -283,6 +295,8 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 		}
 	}
 
+	renderLifecycle(out, r, colorEnabled)
+
 	// Context group
 	if colorEnabled {
 		if proc.WorkingDir != "" && proc.WorkingDir != "unknown" {
@@ -330,20 +344,19 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 		}
 	}
 
+	renderClients(out, r, colorEnabled)
+	renderIncomplete(out, r, colorEnabled)
+
 	// Warnings
 	if len(r.Warnings) > 0 {
 		if colorEnabled {
 			out.Printf("\n%sWarnings%s    :\n", ColorRed, ColorReset)
-			for _, w := range r.Warnings {
-				out.Printf("  • %s\n", SanitizeTerminal(w))
-			}
 		} else {
 			out.Println(ansiString("\nWarnings    :"))
-			for _, w := range r.Warnings {
-				out.Printf("  • %s\n", SanitizeTerminal(w))
-			}
 		}
+		renderWarningList(out, r.Warnings, colorEnabled, verbose)
 	}
+	renderSuppressed(out, r, colorEnabled, verbose)
 
 	// Extended information for verbose mode
 	if verbose {
@@ -424,6 +437,8 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 			}
 		}
 
+		renderCgroup(out, r.CgroupContext, colorEnabled)
+
 		// File context (open files, locks)
 		if r.FileContext != nil {
 			if r.FileContext.OpenFiles > 0 && r.FileContext.FileLimit == 0 {
@@ -586,16 +601,29 @@ func formatBytes(n uint64) string {
 }
 
 // formatSocket renders one row of the Sockets section as
-// "<address>:<port> (<PROTO> | <STATE>)".
+// "<address>:<port> (<PROTO> | <STATE>)", with " → <remote>:<port>" after
+// the address of a connected socket, or for a unix socket
+// "<path> (unix <type> | <STATE>)". A socket in another network namespace
+// ends with "| netns <inode>" inside the parentheses.
 func formatSocket(s model.Socket) string {
+	netns := ""
+	if s.Netns != "" {
+		netns = " | netns " + s.Netns
+	}
+	if s.Path != "" {
+		return fmt.Sprintf("%s (unix %s | %s%s)", s.Path, s.Type, displayState(s.State), netns)
+	}
 	addr := s.Address
 	hostPort := net.JoinHostPort(addr, strconv.Itoa(s.Port))
+	if s.RemotePort > 0 {
+		hostPort += " → " + net.JoinHostPort(s.RemoteAddress, strconv.Itoa(s.RemotePort))
+	}
 	proto := s.Protocol
 	if proto == "" {
 		proto = "?"
 	}
 	state := displayState(s.State)
-	return fmt.Sprintf("%s (%s | %s)", hostPort, proto, state)
+	return fmt.Sprintf("%s (%s | %s%s)", hostPort, proto, state, netns)
 }
 
 // displayState pretty-prints socket states. The kernel-style "LISTEN" reads
@@ -628,11 +656,18 @@ func socketSortRank(state string) int {
 }
 
 // visibleSockets returns sockets that have enough information to be rendered.
-// Anything with a blank address or port-zero is silently dropped.
+// Anything with a blank address or port-zero is silently dropped, and of the
+// unix sockets only the ones the process serves are kept: the connections a
+// listener accepted would repeat its path once per client.
 func visibleSockets(sockets []model.Socket) []model.Socket {
 	out := make([]model.Socket, 0, len(sockets))
 	for _, s := range sockets {
-		if s.Address != "" && s.Port > 0 {
+		switch {
+		case s.Path != "":
+			if s.State != "ESTABLISHED" {
+				out = append(out, s)
+			}
+		case s.Address != "" && s.Port > 0:
 			out = append(out, s)
 		}
 	}
@@ -641,10 +676,17 @@ func visibleSockets(sockets []model.Socket) []model.Socket {
 
 // sortSockets orders sockets for the Sockets section in place: addresses
 // grouped together, ports ascending within an address, LISTEN above
-// ESTABLISHED when they share an address:port pair.
+// ESTABLISHED when they share an address:port pair. Unix sockets follow, by
+// path.
 func sortSockets(sockets []model.Socket) {
 	sort.SliceStable(sockets, func(i, j int) bool {
 		a, b := sockets[i], sockets[j]
+		if (a.Path == "") != (b.Path == "") {
+			return a.Path == ""
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
 		if a.Address != b.Address {
 			return a.Address < b.Address
 		}
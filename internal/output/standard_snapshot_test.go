@@ -163,3 +163,29 @@ func TestRenderStandardOmitsEmptyOptionalSections(t *testing.T) {
 		}
 	}
 }
+
+// TestRenderStandardNamespacedPID shows a process in a nested PID namespace
+// with its PID there, on the Process line and in the chain.
+func TestRenderStandardNamespacedPID(t *testing.T) {
+	t.Parallel()
+
+	res := fixedFixture()
+	res.Process.Command = "redis-server"
+	res.Process.PID = 48211
+	res.Process.Container = "redis"
+	res.Process.NamespacePIDs = []int{48211, 1}
+	shim := model.Process{PID: 48190, Command: "containerd-shim", NamespacePIDs: []int{48190}}
+	res.Ancestry = []model.Process{{PID: 1, Command: "systemd"}, shim, res.Process}
+
+	var buf bytes.Buffer
+	RenderStandard(&buf, res, false, false)
+	out := buf.String()
+	for _, s := range []string{
+		"Process     : redis-server (pid 48211, pid 1 in container)",
+		"systemd (pid 1) → containerd-shim (pid 48190) → redis-server (pid 48211, pid 1 in container)",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("RenderStandard output missing %q\n---\n%s\n---", s, out)
+		}
+	}
+}
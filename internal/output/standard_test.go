@@ -84,6 +84,21 @@ func TestFormatSocket(t *testing.T) {
 			s:    model.Socket{Address: "127.0.0.1", Port: 9999, Protocol: "TCP", State: ""},
 			want: "127.0.0.1:9999 (TCP | ?)",
 		},
+		{
+			name: "a connection shows its peer",
+			s:    model.Socket{Address: "10.0.0.5", Port: 51544, RemoteAddress: "10.2.3.4", RemotePort: 5432, Protocol: "TCP", State: "ESTABLISHED"},
+			want: "10.0.0.5:51544 → 10.2.3.4:5432 (TCP | ESTABLISHED)",
+		},
+		{
+			name: "a socket in another network namespace names it",
+			s:    model.Socket{Address: "0.0.0.0", Port: 8080, Protocol: "TCP", State: "LISTEN", Netns: "4026532289"},
+			want: "0.0.0.0:8080 (TCP | LISTENING | netns 4026532289)",
+		},
+		{
+			name: "unix socket shows its path and type",
+			s:    model.Socket{Protocol: "UNIX", Path: "/run/php/php8.3-fpm.sock", Type: "stream", State: "LISTEN"},
+			want: "/run/php/php8.3-fpm.sock (unix stream | LISTENING)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -105,12 +120,14 @@ func TestVisibleSocketsDropsIncomplete(t *testing.T) {
 		{Address: "", Port: 80, State: "LISTEN"},         // missing address
 		{Address: "127.0.0.1", Port: 0, State: "LISTEN"}, // missing port
 		{Address: "0.0.0.0", Port: 443, State: "LISTEN"},
+		{Path: "/run/app.sock", State: "LISTEN"},
+		{Path: "/run/app.sock", State: "ESTABLISHED"}, // a connection it accepted
 	}
 	got := visibleSockets(in)
-	if len(got) != 2 {
-		t.Fatalf("visibleSockets dropped wrong number: got %d, want 2", len(got))
+	if len(got) != 3 {
+		t.Fatalf("visibleSockets dropped wrong number: got %d, want 3", len(got))
 	}
-	if got[0].Port != 8080 || got[1].Port != 443 {
+	if got[0].Port != 8080 || got[1].Port != 443 || got[2].Path != "/run/app.sock" || got[2].State != "LISTEN" {
 		t.Errorf("visibleSockets unexpected order/content: %+v", got)
 	}
 }
@@ -37,7 +37,7 @@ func richVerboseResult() model.Result {
 		Process:         proc,
 		Ancestry:        []model.Process{{PID: 1, Command: "systemd"}, proc},
 		Source:          model.Source{Type: model.SourceSystemd, Name: "nginx.service"},
-		Warnings:        []string{"Process is running as root"},
+		Warnings:        []model.Warning{{Code: model.WarnRoot, Severity: model.SeverityLow, Message: "Process is running as root"}},
 		ResourceContext: &model.ResourceContext{CPUUsage: 85.0, MemoryUsage: 40 * 1024 * 1024, PreventsSleep: true, ThermalState: "Heavy"},
 		FileContext:     &model.FileContext{OpenFiles: 90, FileLimit: 100, LockedFiles: []string{"/var/run/a.lock", "/var/run/b.lock"}},
 		SocketInfo:      &model.SocketInfo{State: "TIME_WAIT", Explanation: "waiting for delayed packets", Workaround: "use SO_REUSEADDR"},
@@ -2,6 +2,7 @@ package output
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -33,3 +34,45 @@ func FormatStartedAt(t time.Time) (rel, absolute string) {
 	absolute = t.Format("Mon 2006-01-02 15:04:05 -07:00")
 	return rel, absolute
 }
+
+// formatRelativeTime returns a human-friendly relative time string.
+func formatRelativeTime(t time.Time) string {
+	d := time.Since(t)
+	if d < 0 {
+		d = -d
+		switch {
+		case d < time.Minute:
+			return "in <1 min"
+		case d < time.Hour:
+			return fmt.Sprintf("in %d min", int(d.Minutes()))
+		case d < 24*time.Hour:
+			return fmt.Sprintf("in %dh", int(d.Hours()))
+		default:
+			return fmt.Sprintf("in %dd", int(d.Hours()/24))
+		}
+	}
+	switch {
+	case d < time.Minute:
+		return "<1 min ago"
+	case d < time.Hour:
+		return fmt.Sprintf("%d min ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+// scheduleText renders a timer's schedule from a source's details:
+// "*-*-* 06,18:00:00, last: 3h ago, next: in 9h".
+func scheduleText(details map[string]string) string {
+	parts := []string{details["schedule"]}
+	for _, k := range []string{"last-trigger", "next-trigger"} {
+		t, err := time.Parse(time.RFC3339, details[k])
+		if err != nil {
+			continue
+		}
+		parts = append(parts, strings.TrimSuffix(k, "-trigger")+": "+formatRelativeTime(t))
+	}
+	return strings.Join(parts, ", ")
+}
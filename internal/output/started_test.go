@@ -32,3 +32,43 @@ func TestFormatStartedAt(t *testing.T) {
 		}
 	}
 }
+
+func TestFormatRelativeTime(t *testing.T) {
+	now := time.Now()
+	// Durations are buffered off the truncation boundaries so sub-second drift
+	// between now and the time.Since() call inside the function can't flip an
+	// "N min" bucket to "N-1".
+	cases := []struct {
+		d    time.Duration // offset from now; negative = past, positive = future
+		want string
+	}{
+		{-30 * time.Second, "<1 min ago"},
+		{-(5*time.Minute + 30*time.Second), "5 min ago"},
+		{-(3*time.Hour + 30*time.Minute), "3h ago"},
+		{-50 * time.Hour, "2d ago"},
+		{30 * time.Second, "in <1 min"},
+		{5*time.Minute + 30*time.Second, "in 5 min"},
+		{3*time.Hour + 30*time.Minute, "in 3h"},
+		{50 * time.Hour, "in 2d"},
+	}
+	for _, tc := range cases {
+		if got := formatRelativeTime(now.Add(tc.d)); got != tc.want {
+			t.Errorf("formatRelativeTime(now%+v) = %q, want %q", tc.d, got, tc.want)
+		}
+	}
+}
+
+func TestScheduleText(t *testing.T) {
+	now := time.Now()
+	details := map[string]string{
+		"schedule":     "*-*-* 06,18:00:00",
+		"last-trigger": now.Add(-(3*time.Hour + 30*time.Minute)).Format(time.RFC3339),
+		"next-trigger": now.Add(8*time.Hour + 30*time.Minute).Format(time.RFC3339),
+	}
+	if got, want := scheduleText(details), "*-*-* 06,18:00:00, last: 3h ago, next: in 8h"; got != want {
+		t.Errorf("scheduleText = %q, want %q", got, want)
+	}
+	if got := scheduleText(map[string]string{"schedule": "every 1d"}); got != "every 1d" {
+		t.Errorf("scheduleText without trigger times = %q", got)
+	}
+}
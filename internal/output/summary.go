@@ -0,0 +1,107 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// SummarizeMatches groups results, the processes t matched, by the source
+// that started them, the largest group first.
+func SummarizeMatches(t model.Target, results []model.Result) model.MatchSummary {
+	s := model.MatchSummary{Target: t, Groups: []model.MatchGroup{}}
+	for _, r := range results {
+		i := slices.IndexFunc(s.Groups, func(g model.MatchGroup) bool {
+			return g.SourceType == r.Source.Type && g.SourceName == r.Source.Name
+		})
+		if i < 0 {
+			s.Groups = append(s.Groups, model.MatchGroup{SourceType: r.Source.Type, SourceName: r.Source.Name})
+			i = len(s.Groups) - 1
+		}
+		s.Groups[i].PIDs = append(s.Groups[i].PIDs, r.Process.PID)
+	}
+	for i := range s.Groups {
+		slices.Sort(s.Groups[i].PIDs)
+	}
+	slices.SortStableFunc(s.Groups, func(a, b model.MatchGroup) int {
+		if len(a.PIDs) != len(b.PIDs) {
+			return len(b.PIDs) - len(a.PIDs)
+		}
+		return strings.Compare(sourcePhrase(a), sourcePhrase(b))
+	})
+	return s
+}
+
+// sourcePhrase names the source of g so that it reads after "7 from":
+// "gunicorn.service", "cron", "an SSH session" or "SSH sessions".
+func sourcePhrase(g model.MatchGroup) string {
+	one := len(g.PIDs) == 1
+	name := SanitizeTerminal(g.SourceName)
+	switch g.SourceType {
+	case model.SourceSSH:
+		if one {
+			return "an SSH session"
+		}
+		return "SSH sessions"
+	case model.SourceShell:
+		switch {
+		case name == "" && one:
+			return "a shell"
+		case name == "":
+			return "shells"
+		case one:
+			return "a " + name + " shell"
+		}
+		return name + " shells"
+	case model.SourceUnknown, "":
+		if one {
+			return "an unknown source"
+		}
+		return "unknown sources"
+	}
+	if name != "" {
+		return name
+	}
+	return string(g.SourceType)
+}
+
+// RenderMatchSummary prints s: how many processes matched, then for each
+// source how many it started and their PIDs.
+func RenderMatchSummary(w io.Writer, s model.MatchSummary, colorEnabled bool) {
+	p := NewPrinter(w)
+
+	total := 0
+	phrases := make([]string, len(s.Groups))
+	width := 0
+	for i, g := range s.Groups {
+		total += len(g.PIDs)
+		phrases[i] = fmt.Sprintf("%d from %s", len(g.PIDs), sourcePhrase(g))
+		width = max(width, len(phrases[i]))
+	}
+	noun := "processes"
+	if total == 1 {
+		noun = "process"
+	}
+	if colorEnabled {
+		p.Printf("%sSummary%s     : %d matching %s\n", ColorCyan, ColorReset, total, noun)
+	} else {
+		p.Printf("Summary     : %d matching %s\n", total, noun)
+	}
+
+	for i, g := range s.Groups {
+		pids := make([]string, len(g.PIDs))
+		for k, pid := range g.PIDs {
+			pids[k] = strconv.Itoa(pid)
+		}
+		pad := strings.Repeat(" ", width-len(phrases[i]))
+		if colorEnabled {
+			p.Printf("  %s%s  %spid %s%s\n", phrases[i], pad, ColorDim, strings.Join(pids, ", "), ColorReset)
+		} else {
+			p.Printf("  %s%s  pid %s\n", phrases[i], pad, strings.Join(pids, ", "))
+		}
+	}
+}
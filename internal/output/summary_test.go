@@ -0,0 +1,69 @@
+package output
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func matchFrom(pid int, typ model.SourceType, name string) model.Result {
+	return model.Result{
+		Process: model.Process{PID: pid},
+		Source:  model.Source{Type: typ, Name: name},
+	}
+}
+
+func TestSummarizeMatches(t *testing.T) {
+	t.Parallel()
+
+	target := model.Target{Type: model.TargetName, Value: "python"}
+	s := SummarizeMatches(target, []model.Result{
+		matchFrom(412, model.SourceSSH, "sshd"),
+		matchFrom(301, model.SourceCron, "cron"),
+		matchFrom(103, model.SourceSystemd, "gunicorn.service"),
+		matchFrom(101, model.SourceSystemd, "gunicorn.service"),
+		matchFrom(300, model.SourceCron, "cron"),
+		matchFrom(102, model.SourceSystemd, "gunicorn.service"),
+	})
+	want := []model.MatchGroup{
+		{SourceType: model.SourceSystemd, SourceName: "gunicorn.service", PIDs: []int{101, 102, 103}},
+		{SourceType: model.SourceCron, SourceName: "cron", PIDs: []int{300, 301}},
+		{SourceType: model.SourceSSH, SourceName: "sshd", PIDs: []int{412}},
+	}
+	if s.Target != target || !reflect.DeepEqual(s.Groups, want) {
+		t.Fatalf("SummarizeMatches:\n got %+v\nwant %+v", s.Groups, want)
+	}
+
+	var buf bytes.Buffer
+	RenderMatchSummary(&buf, s, false)
+	wantOut := "Summary     : 6 matching processes\n" +
+		"  3 from gunicorn.service  pid 101, 102, 103\n" +
+		"  2 from cron              pid 300, 301\n" +
+		"  1 from an SSH session    pid 412\n"
+	if buf.String() != wantOut {
+		t.Errorf("RenderMatchSummary:\n got %q\nwant %q", buf.String(), wantOut)
+	}
+}
+
+func TestSourcePhrase(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		group model.MatchGroup
+		want  string
+	}{
+		{model.MatchGroup{SourceType: model.SourceSSH, PIDs: []int{1, 2}}, "SSH sessions"},
+		{model.MatchGroup{SourceType: model.SourceShell, SourceName: "zsh", PIDs: []int{1}}, "a zsh shell"},
+		{model.MatchGroup{SourceType: model.SourceShell, SourceName: "zsh", PIDs: []int{1, 2}}, "zsh shells"},
+		{model.MatchGroup{SourceType: model.SourceUnknown, PIDs: []int{1}}, "an unknown source"},
+		{model.MatchGroup{SourceType: model.SourceSupervisor, SourceName: "pm2", PIDs: []int{1}}, "pm2"},
+		{model.MatchGroup{SourceType: model.SourceInit, PIDs: []int{1}}, "init"},
+	}
+	for _, tt := range tests {
+		if got := sourcePhrase(tt.group); got != tt.want {
+			t.Errorf("sourcePhrase(%+v) = %q, want %q", tt.group, got, tt.want)
+		}
+	}
+}
@@ -99,3 +99,28 @@ func TestPrintTreeLastChildUsesElbowConnector(t *testing.T) {
 		t.Errorf("expected last child rendered with └─; output:\n%s", out)
 	}
 }
+
+func TestPrintTreeNamespacedPIDs(t *testing.T) {
+	t.Parallel()
+
+	chain := []model.Process{
+		{PID: 1, Command: "systemd", NamespacePIDs: []int{1}},
+		{PID: 5000, Command: "sh", NamespacePIDs: []int{5000, 1}},
+	}
+	children := []model.Process{
+		{PID: 5001, Command: "sleep", ContainerID: "abc123", NamespacePIDs: []int{5001, 2}},
+	}
+
+	var buf bytes.Buffer
+	PrintTree(&buf, chain, children, false)
+	out := buf.String()
+	for _, s := range []string{
+		"systemd (pid 1)\n",
+		"└─ sh (pid 5000, pid 1 in its namespace)",
+		"└─ sleep (pid 5001, pid 2 in container)",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("PrintTree output missing %q\n---\n%s\n---", s, out)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// renderActivation prints the line under Why It Exists that says which
+// units pulled in the process's systemd unit:
+// "activated by multi-user.target → nginx.service".
+func renderActivation(out Printer, r model.Result, colorEnabled bool) {
+	u := r.Source.Unit
+	if u == nil || len(u.ActivatedBy) == 0 || r.Source.Name == "" {
+		return
+	}
+	units := make([]string, 0, len(u.ActivatedBy)+1)
+	for _, a := range u.ActivatedBy {
+		units = append(units, SanitizeTerminal(a.Unit))
+	}
+	units = append(units, SanitizeTerminal(r.Source.Name))
+	if colorEnabled {
+		out.Printf("  %sactivated by%s %s\n", ColorDim, ColorReset, strings.Join(units, " "+string(ColorMagenta)+"→"+string(ColorReset)+" "))
+	} else {
+		out.Printf("  activated by %s\n", strings.Join(units, " → "))
+	}
+}
+
+// renderUnitConfig prints how the process's systemd unit is configured,
+// below its Unit File: the drop-ins amending it and its Restart= policy,
+// and with verbose, what it runs and as whom.
+func renderUnitConfig(out Printer, r model.Result, colorEnabled, verbose bool) {
+	u := r.Source.Unit
+	if u == nil {
+		return
+	}
+	line := func(label, value string) {
+		if colorEnabled {
+			out.Printf("%s%s%s: %s\n", ColorCyan, label, ColorReset, SanitizeTerminal(value))
+		} else {
+			out.Printf("%s: %s\n", label, SanitizeTerminal(value))
+		}
+	}
+	for i, d := range u.DropIns {
+		if i == 0 {
+			line("Drop-Ins    ", d)
+		} else {
+			out.Printf("              %s\n", SanitizeTerminal(d))
+		}
+	}
+	if u.Restart != "" {
+		line("Restart     ", u.Restart)
+	}
+	if !verbose {
+		return
+	}
+	for i, cmd := range u.ExecStart {
+		if i == 0 {
+			line("Exec Start  ", cmd)
+		} else {
+			out.Printf("              %s\n", SanitizeTerminal(cmd))
+		}
+	}
+	if u.User != "" {
+		runAs := u.User
+		if u.Group != "" {
+			runAs += ":" + u.Group
+		}
+		line("Run As      ", runAs)
+	}
+}
+
+// renderSocketActivation prints the systemd socket unit listening on the
+// queried port: the socket that started the process, or, while PID 1 still
+// holds the port alone, the service that will start on a connection.
+func renderSocketActivation(out Printer, r model.Result, colorEnabled bool) {
+	a := r.SocketActivation
+	if a == nil {
+		return
+	}
+	socket, service := SanitizeTerminal(a.Socket), SanitizeTerminal(a.Service)
+	var text string
+	switch {
+	case !a.Running && a.Accept:
+		text = socket + ", port held by systemd, one " + service + " instance starts per connection"
+	case !a.Running:
+		text = socket + ", port held by systemd, " + service + " starts on first connection"
+	case a.Accept:
+		text = fmt.Sprintf("socket-activated by %s (Accept=yes, %s of %s running)", socket, plural(uint64(len(a.MainPIDs)), "instance"), service)
+	default:
+		text = "socket-activated by " + socket
+	}
+	if colorEnabled {
+		out.Printf("%sActivation%s  : %s\n", ColorCyan, ColorReset, text)
+	} else {
+		out.Printf("Activation  : %s\n", text)
+	}
+}
@@ -0,0 +1,101 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestRenderSystemdUnit(t *testing.T) {
+	t.Parallel()
+
+	res := fixedFixture()
+	res.Source.UnitFile = "/lib/systemd/system/nginx.service"
+	res.Source.Unit = &model.SystemdUnit{
+		ExecStart: []string{"/usr/sbin/nginx -g daemon on;"},
+		User:      "www-data",
+		Group:     "www-data",
+		Restart:   "on-failure",
+		DropIns:   []string{"/etc/systemd/system/nginx.service.d/limits.conf", "/etc/systemd/system/nginx.service.d/override.conf"},
+		FileState: "enabled",
+		ActivatedBy: []model.Activation{
+			{Unit: "multi-user.target", Relation: "wants"},
+			{Unit: "web.target", Relation: "wants"},
+		},
+	}
+
+	var buf bytes.Buffer
+	RenderStandard(&buf, res, false, false)
+	out := buf.String()
+	for _, s := range []string{
+		"systemd (pid 1) → nginx (pid 1234)\n  activated by multi-user.target → web.target → nginx.service\n\nSource",
+		"Unit File   : /lib/systemd/system/nginx.service (enabled)\n",
+		"Drop-Ins    : /etc/systemd/system/nginx.service.d/limits.conf\n              /etc/systemd/system/nginx.service.d/override.conf\n",
+		"Restart     : on-failure\n",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("output missing %q\n---\n%s\n---", s, out)
+		}
+	}
+	if strings.Contains(out, "Exec Start") || strings.Contains(out, "Run As") {
+		t.Errorf("Exec Start and Run As are verbose only:\n%s", out)
+	}
+
+	buf.Reset()
+	RenderStandard(&buf, res, false, true)
+	for _, s := range []string{"Exec Start  : /usr/sbin/nginx -g daemon on;\n", "Run As      : www-data:www-data\n"} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("verbose output missing %q\n---\n%s\n---", s, buf.String())
+		}
+	}
+}
+
+func TestRenderSocketActivation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		act  model.SocketActivation
+		want string
+	}{
+		{"waiting", model.SocketActivation{Socket: "cups.socket", Service: "cups.service"},
+			"Activation  : cups.socket, port held by systemd, cups.service starts on first connection\n"},
+		{"waiting per connection", model.SocketActivation{Socket: "sshd.socket", Accept: true, Service: "sshd@.service"},
+			"Activation  : sshd.socket, port held by systemd, one sshd@.service instance starts per connection\n"},
+		{"running", model.SocketActivation{Socket: "cups.socket", Service: "cups.service", Running: true, MainPIDs: []int{1234}},
+			"Activation  : socket-activated by cups.socket\n"},
+		{"running per connection", model.SocketActivation{Socket: "sshd.socket", Accept: true, Service: "sshd@.service", Running: true, MainPIDs: []int{1234, 1240}},
+			"Activation  : socket-activated by sshd.socket (Accept=yes, 2 instances of sshd@.service running)\n"},
+	}
+	for _, tt := range tests {
+		res := fixedFixture()
+		res.SocketActivation = &tt.act
+		var buf bytes.Buffer
+		RenderStandard(&buf, res, false, false)
+		if !strings.Contains(buf.String(), tt.want) {
+			t.Errorf("%s: output missing %q\n---\n%s\n---", tt.name, tt.want, buf.String())
+		}
+	}
+}
+
+func TestRenderUserUnit(t *testing.T) {
+	t.Parallel()
+
+	res := fixedFixture()
+	res.Source.Name = "syncthing.service"
+	res.Source.UserManager = "user@1000.service"
+	res.Source.UnitFile = "/home/alice/.config/systemd/user/syncthing.service"
+
+	var buf bytes.Buffer
+	RenderStandard(&buf, res, false, false)
+	for _, s := range []string{
+		"Source      : syncthing.service (systemd --user, user@1000.service)\n",
+		"Unit File   : /home/alice/.config/systemd/user/syncthing.service\n",
+	} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("output missing %q\n---\n%s\n---", s, buf.String())
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package output
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// renderWarningList prints one bullet per warning, tagged with its severity
+// and code, and with evidence its supporting facts underneath:
+//
+//   - [high] Process has dangerous capabilities: CAP_SYS_ADMIN (W_DANGEROUS_CAPS)
+//     capabilities: CAP_SYS_ADMIN
+//
+// Warnings saved by an older witr have neither and print as their message. A
+// suppressed warning names what suppressed it after its code.
+func renderWarningList(out Printer, warnings []model.Warning, colorEnabled, evidence bool) {
+	for _, w := range warnings {
+		tag := string(w.Code)
+		if w.SuppressedBy != "" {
+			tag = strings.TrimPrefix(tag+", suppressed by "+w.SuppressedBy, ", ")
+		}
+		switch {
+		case tag == "":
+			out.Printf("  • %s\n", w.Message)
+		case w.Code == "":
+			out.Printf("  • %s (%s)\n", w.Message, tag)
+		case colorEnabled:
+			out.Printf("  • %s[%s]%s %s %s(%s)%s\n", severityColor(w.Severity), w.Severity, ColorReset, w.Message, ColorDim, tag, ColorReset)
+		default:
+			out.Printf("  • [%s] %s (%s)\n", w.Severity, w.Message, tag)
+		}
+		if !evidence {
+			continue
+		}
+		keys := make([]string, 0, len(w.Evidence))
+		for k := range w.Evidence {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if colorEnabled {
+				out.Printf("      %s%s: %s%s\n", ColorDim, k, w.Evidence[k], ColorReset)
+			} else {
+				out.Printf("      %s: %s\n", k, w.Evidence[k])
+			}
+		}
+	}
+}
+
+// renderSuppressed prints the Suppressed section, which is only filled in
+// with --show-suppressed.
+func renderSuppressed(out Printer, r model.Result, colorEnabled, evidence bool) {
+	if len(r.Suppressed) == 0 {
+		return
+	}
+	if colorEnabled {
+		out.Printf("\n%sSuppressed%s  :\n", ColorDim, ColorReset)
+	} else {
+		out.Println(ansiString("\nSuppressed  :"))
+	}
+	renderWarningList(out, r.Suppressed, colorEnabled, evidence)
+}
+
+// renderIncomplete notes the enrichment steps that ran out of time, so a
+// missing section is not mistaken for there being nothing to show.
+func renderIncomplete(out Printer, r model.Result, colorEnabled bool) {
+	if len(r.Incomplete) == 0 {
+		return
+	}
+	steps := strings.Join(r.Incomplete, ", ")
+	if colorEnabled {
+		out.Printf("\n%sIncomplete%s  : stopped early; skipped %s\n", ColorDimYellow, ColorReset, steps)
+	} else {
+		out.Printf("\nIncomplete  : stopped early; skipped %s\n", steps)
+	}
+}
+
+func severityColor(s model.Severity) ansiString {
+	switch s {
+	case model.SeverityHigh:
+		return ColorRed
+	case model.SeverityMedium:
+		return ColorDimYellow
+	default:
+		return ColorDim
+	}
+}
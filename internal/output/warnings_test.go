@@ -0,0 +1,127 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestRenderWarningList(t *testing.T) {
+	t.Parallel()
+
+	warnings := []model.Warning{
+		{
+			Code:     model.WarnDangerousCaps,
+			Severity: model.SeverityHigh,
+			Message:  "Process has dangerous capabilities: CAP_SYS_ADMIN",
+			Evidence: map[string]string{"capabilities": "CAP_SYS_ADMIN"},
+		},
+		{Message: "Process is running as root"}, // saved by an older witr
+	}
+
+	var brief bytes.Buffer
+	renderWarningList(NewPrinter(&brief), warnings, false, false)
+	want := "  • [high] Process has dangerous capabilities: CAP_SYS_ADMIN (W_DANGEROUS_CAPS)\n" +
+		"  • Process is running as root\n"
+	if brief.String() != want {
+		t.Errorf("without evidence:\n%s\nwant:\n%s", brief.String(), want)
+	}
+
+	var full bytes.Buffer
+	renderWarningList(NewPrinter(&full), warnings, false, true)
+	if !strings.Contains(full.String(), "(W_DANGEROUS_CAPS)\n      capabilities: CAP_SYS_ADMIN\n") {
+		t.Errorf("evidence should follow its warning:\n%s", full.String())
+	}
+}
+
+// TestRenderStandardWarningEvidence checks that the standard report shows a
+// warning's evidence only with --verbose, while --warnings always does.
+func TestRenderStandardWarningEvidence(t *testing.T) {
+	t.Parallel()
+
+	res := fixedFixture()
+	res.Warnings = []model.Warning{{
+		Code: model.WarnSuspiciousCwd, Severity: model.SeverityMedium,
+		Message:  "Process is running from a suspicious working directory: /tmp",
+		Evidence: map[string]string{"dir": "/tmp"},
+	}}
+
+	var std, verbose, warn bytes.Buffer
+	RenderStandard(&std, res, false, false)
+	RenderStandard(&verbose, res, false, true)
+	RenderWarnings(&warn, res, false)
+
+	if !strings.Contains(std.String(), "[medium] Process is running from a suspicious working directory: /tmp (W_SUSPICIOUS_CWD)") {
+		t.Errorf("standard output missing the tagged warning:\n%s", std.String())
+	}
+	if strings.Contains(std.String(), "dir: /tmp") {
+		t.Errorf("standard output should not show evidence:\n%s", std.String())
+	}
+	for name, out := range map[string]string{"verbose": verbose.String(), "warnings": warn.String()} {
+		if !strings.Contains(out, "      dir: /tmp") {
+			t.Errorf("%s output missing evidence:\n%s", name, out)
+		}
+	}
+}
+
+func TestRenderSuppressed(t *testing.T) {
+	t.Parallel()
+
+	res := fixedFixture()
+	res.Warnings = nil
+	res.Suppressed = []model.Warning{
+		{Code: model.WarnRoot, Severity: model.SeverityLow, Message: "Process is running as root", SuppressedBy: "config: sshd needs root"},
+		{Code: model.WarnPublicBind, Severity: model.SeverityMedium, Message: "Process is listening on a public interface", SuppressedBy: "baseline"},
+	}
+
+	var std, warn bytes.Buffer
+	RenderStandard(&std, res, false, false)
+	RenderWarnings(&warn, res, false)
+	for name, out := range map[string]string{"standard": std.String(), "warnings": warn.String()} {
+		for _, want := range []string{
+			"\nSuppressed  :\n",
+			"  • [low] Process is running as root (W_ROOT, suppressed by config: sshd needs root)\n",
+			"(W_PUBLIC_BIND, suppressed by baseline)\n",
+		} {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s output missing %q:\n%s", name, want, out)
+			}
+		}
+	}
+	if !strings.Contains(warn.String(), "Warnings    : No warnings.") {
+		t.Errorf("suppressed warnings should not count as warnings:\n%s", warn.String())
+	}
+
+	res.Suppressed = nil
+	std.Reset()
+	RenderStandard(&std, res, false, false)
+	if strings.Contains(std.String(), "Suppressed") {
+		t.Errorf("no Suppressed section without suppressed warnings:\n%s", std.String())
+	}
+}
+
+func TestRenderIncomplete(t *testing.T) {
+	t.Parallel()
+
+	res := fixedFixture()
+	res.Incomplete = []string{"source details", "child processes"}
+
+	var std, warn bytes.Buffer
+	RenderStandard(&std, res, false, false)
+	RenderWarnings(&warn, res, false)
+	want := "\nIncomplete  : stopped early; skipped source details, child processes\n"
+	for name, out := range map[string]string{"standard": std.String(), "warnings": warn.String()} {
+		if !strings.Contains(out, want) {
+			t.Errorf("%s output missing %q:\n%s", name, want, out)
+		}
+	}
+
+	res.Incomplete = nil
+	std.Reset()
+	RenderStandard(&std, res, false, false)
+	if strings.Contains(std.String(), "Incomplete") {
+		t.Errorf("no Incomplete line for a complete result:\n%s", std.String())
+	}
+}
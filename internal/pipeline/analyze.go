@@ -1,7 +1,7 @@
 package pipeline
 
 import (
-	"sort"
+	"context"
 	"strconv"
 	"strings"
 
@@ -14,16 +14,47 @@ type AnalyzeConfig struct {
 	PID     int
 	Verbose bool
 	Tree    bool
+	// Clients finds the local processes connected to the process's
+	// listening sockets. It reads every process's descriptors, so it is
+	// meant for one target shown on its own, not for --all, --watch or a
+	// baseline of every process.
+	Clients bool
 	Target  model.Target
 }
 
-func AnalyzePID(cfg AnalyzeConfig) (model.Result, error) {
-	ancestry, err := procpkg.ResolveAncestry(cfg.PID)
+// AnalyzePID explains why cfg.PID is running. Only the ancestry is required:
+// once ctx is done, the enrichment steps that follow are skipped or cut short,
+// and the result lists them in Incomplete instead of failing.
+func AnalyzePID(ctx context.Context, cfg AnalyzeConfig) (model.Result, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Result{}, err
+	}
+	// The ancestry, children and process reads come from the invocation's
+	// index, so targets sharing ancestors read them once.
+	idx := procpkg.IndexFrom(ctx)
+	ancestry, err := idx.Ancestry(cfg.PID)
 	if err != nil {
 		return model.Result{}, err
 	}
 
-	src := source.Detect(ancestry)
+	var incomplete []string
+	// enrich runs an optional step while ctx is live, and records it as
+	// incomplete when ctx was done before it or ran out during it.
+	enrich := func(step string, fn func()) {
+		if ctx.Err() == nil {
+			fn()
+		}
+		if ctx.Err() != nil {
+			incomplete = append(incomplete, step)
+		}
+	}
+
+	// Detect always runs: what the ancestry shows costs nothing, and only the
+	// service manager lookups behind it watch ctx.
+	src := source.Detect(ctx, ancestry)
+	if ctx.Err() != nil {
+		incomplete = append(incomplete, "source details")
+	}
 
 	// ReadProcess labels lxc.payload cgroups generically as "lxc-based:" since
 	// it can't see the ancestry. source.Detect knows the actual runtime via the
@@ -53,35 +84,37 @@ func AnalyzePID(cfg AnalyzeConfig) (model.Result, error) {
 	// Resolve the target container's healthcheck so the warning only fires when
 	// the runtime confirms none is configured.
 	if proc.ContainerID != "" {
-		hc := procpkg.ContainerHealthcheckStatus(proc.ContainerID, proc.ContainerRuntime)
-		proc.ContainerHealthcheck = hc
-		if len(ancestry) > 0 {
-			ancestry[len(ancestry)-1].ContainerHealthcheck = hc
-		}
+		enrich("container healthcheck", func() {
+			hc := procpkg.ContainerHealthcheckStatus(ctx, proc.ContainerID, proc.ContainerRuntime)
+			proc.ContainerHealthcheck = hc
+			if len(ancestry) > 0 {
+				ancestry[len(ancestry)-1].ContainerHealthcheck = hc
+			}
+		})
 	}
 
 	// Collect child PIDs once and reuse for both extended info and tree output
 	var childPIDs []int
 	var childProcesses []model.Process
 	if (cfg.Verbose || cfg.Tree) && proc.PID > 0 {
-		snapshot, err := procpkg.ListProcessSnapshot()
-		if err == nil {
-			for _, p := range snapshot {
-				if p.PPID == proc.PID {
-					childPIDs = append(childPIDs, p.PID)
-					childProcesses = append(childProcesses, p)
-				}
+		enrich("child processes", func() {
+			children, err := idx.Children(proc.PID)
+			if err != nil {
+				return
 			}
-			sort.Slice(childProcesses, func(i, j int) bool {
-				return childProcesses[i].PID < childProcesses[j].PID
-			})
-			sort.Ints(childPIDs)
-		}
+			for _, p := range children {
+				childPIDs = append(childPIDs, p.PID)
+				childProcesses = append(childProcesses, p)
+			}
+		})
 	}
 
 	if cfg.Verbose && len(ancestry) > 0 {
-		memInfo, ioStats, fileDescs, fdCount, fdLimit, threadCount, err := procpkg.ReadExtendedInfo(cfg.PID)
-		if err == nil {
+		enrich("extended process info", func() {
+			memInfo, ioStats, fileDescs, fdCount, fdLimit, threadCount, err := procpkg.ReadExtendedInfo(cfg.PID)
+			if err != nil {
+				return
+			}
 			proc.Memory = memInfo
 			proc.IO = ioStats
 			proc.FileDescs = fileDescs
@@ -90,14 +123,31 @@ func AnalyzePID(cfg AnalyzeConfig) (model.Result, error) {
 			proc.Children = childPIDs
 			proc.ThreadCount = threadCount
 			ancestry[len(ancestry)-1] = proc
-		}
+		})
+	}
+
+	// The processes connected to it, found only when asked for and it has
+	// accepted connections, since finding them reads every process's
+	// descriptors.
+	var clients []model.Client
+	if cfg.Clients && len(proc.Sockets) > 0 {
+		enrich("connected clients", func() {
+			clients, _ = procpkg.ConnectedClients(ctx, proc.Sockets)
+		})
+	}
+
+	// The limits and pressure of its cgroup: a few small reads, and what the
+	// OOM and limit warnings come from, so not only for --verbose.
+	var cgroupCtx *model.CgroupContext
+	if proc.PID > 0 {
+		enrich("cgroup context", func() { cgroupCtx = procpkg.ReadCgroup(proc.PID) })
 	}
 
 	var resCtx *model.ResourceContext
 	var fileCtx *model.FileContext
 	if cfg.Verbose {
-		resCtx = procpkg.GetResourceContext(cfg.PID)
-		fileCtx = procpkg.GetFileContext(cfg.PID)
+		enrich("resource context", func() { resCtx = procpkg.GetResourceContext(cfg.PID) })
+		enrich("file context", func() { fileCtx = procpkg.GetFileContext(cfg.PID) })
 	}
 
 	restartCount := 0
@@ -116,11 +166,39 @@ func AnalyzePID(cfg AnalyzeConfig) (model.Result, error) {
 		RestartCount:    restartCount,
 		Ancestry:        ancestry,
 		Source:          src,
-		Warnings:        source.Warnings(ancestry, restartCount, src.Type),
+		Warnings:        append(source.Warnings(ancestry, restartCount, src.Type), source.CgroupWarnings(cgroupCtx)...),
 		ResourceContext: resCtx,
 		FileContext:     fileCtx,
+		CgroupContext:   cgroupCtx,
 		Children:        childProcesses,
+		Clients:         clients,
+		Incomplete:      incomplete,
 	}
 
 	return res, nil
 }
+
+// AnnotatePortTarget fills the port-specific parts of a result for a --port
+// lookup: the socket state of the queried port and, when a systemd socket
+// unit listens on it over proto ("tcp", "udp" or empty for either), the
+// socket activation. A port still held by PID 1
+// belongs to a service that has not started yet, whose name becomes the
+// resolved target.
+func AnnotatePortTarget(ctx context.Context, res *model.Result, port int, proto string) {
+	if res == nil || port <= 0 {
+		return
+	}
+	if (res.Process.PID == 1 || res.Source.Type == model.SourceSystemd) && source.IsSystemdRunning() {
+		if act, err := source.SocketActivation(ctx, port, proto); err == nil && act != nil {
+			res.SocketActivation = act
+			if res.Process.PID == 1 {
+				res.ResolvedTarget = strings.TrimSuffix(strings.TrimSuffix(act.Service, ".service"), "@")
+			}
+		}
+		if ctx.Err() != nil {
+			res.Incomplete = append(res.Incomplete, "socket-activated service")
+		}
+	}
+	res.SocketInfo = procpkg.GetSocketStateForPort(port)
+	source.EnrichSocketInfo(res.SocketInfo)
+}
@@ -1,6 +1,7 @@
 package pipeline
 
 import (
+	"context"
 	"os"
 	"testing"
 
@@ -41,7 +42,7 @@ func BenchmarkAnalyzePID(b *testing.B) {
 	self := os.Getpid()
 	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
-		if _, err := AnalyzePID(AnalyzeConfig{PID: self}); err != nil {
+		if _, err := AnalyzePID(context.Background(), AnalyzeConfig{PID: self}); err != nil {
 			b.Fatal(err)
 		}
 	}
@@ -51,7 +52,7 @@ func BenchmarkAnalyzePIDVerbose(b *testing.B) {
 	self := os.Getpid()
 	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
-		if _, err := AnalyzePID(AnalyzeConfig{PID: self, Verbose: true, Tree: true}); err != nil {
+		if _, err := AnalyzePID(context.Background(), AnalyzeConfig{PID: self, Verbose: true, Tree: true}); err != nil {
 			b.Fatal(err)
 		}
 	}
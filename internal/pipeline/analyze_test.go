@@ -1,7 +1,10 @@
 package pipeline
 
 import (
+	"context"
+	"errors"
 	"os"
+	"slices"
 	"testing"
 )
 
@@ -11,7 +14,7 @@ import (
 func TestAnalyzePID_Self(t *testing.T) {
 	self := os.Getpid()
 
-	res, err := AnalyzePID(AnalyzeConfig{PID: self})
+	res, err := AnalyzePID(context.Background(), AnalyzeConfig{PID: self})
 	if err != nil {
 		t.Fatalf("AnalyzePID(self=%d): %v", self, err)
 	}
@@ -39,7 +42,7 @@ func TestAnalyzePID_Self(t *testing.T) {
 func TestAnalyzePID_Verbose(t *testing.T) {
 	self := os.Getpid()
 
-	res, err := AnalyzePID(AnalyzeConfig{PID: self, Verbose: true, Tree: true})
+	res, err := AnalyzePID(context.Background(), AnalyzeConfig{PID: self, Verbose: true, Tree: true})
 	if err != nil {
 		t.Fatalf("verbose AnalyzePID(self=%d): %v", self, err)
 	}
@@ -51,7 +54,55 @@ func TestAnalyzePID_Verbose(t *testing.T) {
 // TestAnalyzePID_Nonexistent confirms the pipeline surfaces an error rather than
 // returning a zero-value result for a PID that doesn't exist.
 func TestAnalyzePID_Nonexistent(t *testing.T) {
-	if _, err := AnalyzePID(AnalyzeConfig{PID: 2147483646}); err == nil {
+	if _, err := AnalyzePID(context.Background(), AnalyzeConfig{PID: 2147483646}); err == nil {
 		t.Error("expected an error for a nonexistent PID")
 	}
 }
+
+// TestAnalyzePID_Cancelled: with nothing done yet there is nothing to show,
+// so a cancelled context is an error rather than an empty result.
+func TestAnalyzePID_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := AnalyzePID(ctx, AnalyzeConfig{PID: os.Getpid()}); !errors.Is(err, context.Canceled) {
+		t.Errorf("AnalyzePID with a cancelled context = %v, want context.Canceled", err)
+	}
+}
+
+// expiresAfter is a context that is live for its first n Err checks and
+// cancelled after, so a test can stop the pipeline once the ancestry is read.
+type expiresAfter struct {
+	context.Context
+	n int
+}
+
+func (c *expiresAfter) Err() error {
+	if c.n > 0 {
+		c.n--
+		return nil
+	}
+	return context.Canceled
+}
+
+// TestAnalyzePID_Incomplete: once the context is done the result still
+// comes back, listing the enrichment steps it skipped.
+func TestAnalyzePID_Incomplete(t *testing.T) {
+	self := os.Getpid()
+	ctx := &expiresAfter{Context: context.Background(), n: 1}
+
+	res, err := AnalyzePID(ctx, AnalyzeConfig{PID: self, Verbose: true, Tree: true})
+	if err != nil {
+		t.Fatalf("AnalyzePID(self=%d) after the ancestry: %v", self, err)
+	}
+	if res.Process.PID != self {
+		t.Errorf("Process.PID = %d, want %d", res.Process.PID, self)
+	}
+	for _, step := range []string{"child processes", "extended process info", "file context"} {
+		if !slices.Contains(res.Incomplete, step) {
+			t.Errorf("Incomplete = %v, missing %q", res.Incomplete, step)
+		}
+	}
+	if res.Children != nil || res.FileContext != nil {
+		t.Error("skipped steps should leave their fields empty")
+	}
+}
@@ -0,0 +1,59 @@
+//go:build linux
+
+package pipeline
+
+import (
+	"context"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pranshuparmar/witr/internal/procfs"
+)
+
+// TestAnalyzePID_Clients checks that the connected clients, which cost a
+// scan of every process's descriptors, are only looked up when asked for.
+func TestAnalyzePID_Clients(t *testing.T) {
+	const header = "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+	const tail = " 00000000:00000000 00:00000000 00000000  1000        0 "
+	sock := func(target string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(target), Mode: fs.ModeSymlink}
+	}
+	// postgres listens on 127.0.0.1:5432 and accepted psql's connection.
+	tree := fstest.MapFS{
+		"proc/net/tcp": {Data: []byte(header +
+			"   0: 0100007F:1538 00000000:0000 0A" + tail + "8001 1\n" +
+			"   1: 0100007F:1538 0100007F:9C40 01" + tail + "8002 1\n" +
+			"   2: 0100007F:9C40 0100007F:1538 01" + tail + "8003 1\n")},
+		"proc/1/stat":      {Data: []byte("1 (systemd) S 0 1 1 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 10 0 0\n")},
+		"proc/310/stat":    {Data: []byte("310 (postgres) S 1 310 310 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 500 0 0\n")},
+		"proc/310/status":  {Data: []byte("Name:\tpostgres\nUid:\t0\t0\t0\t0\n")},
+		"proc/4242/stat":   {Data: []byte("4242 (psql) S 1 4242 4242 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 900 0 0\n")},
+		"proc/310/fd/3":    sock("socket:[8001]"),
+		"proc/310/fd/4":    sock("socket:[8002]"),
+		"proc/4242/fd/3":   sock("socket:[8003]"),
+		"proc/4242/status": {Data: []byte("Name:\tpsql\nUid:\t1000\t1000\t1000\t1000\n")},
+	}
+	prev, prevLive := procfs.Current(), procfs.Live()
+	procfs.Set(procfs.FromFS(tree), false)
+	t.Cleanup(func() { procfs.Set(prev, prevLive) })
+
+	res, err := AnalyzePID(context.Background(), AnalyzeConfig{PID: 310})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Process.Sockets) == 0 {
+		t.Fatal("postgres should have its sockets")
+	}
+	if res.Clients != nil {
+		t.Errorf("Clients = %+v without AnalyzeConfig.Clients, want none", res.Clients)
+	}
+
+	res, err = AnalyzePID(context.Background(), AnalyzeConfig{PID: 310, Clients: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Clients) != 1 || res.Clients[0].PID != 4242 {
+		t.Errorf("Clients = %+v, want psql (4242)", res.Clients)
+	}
+}
@@ -0,0 +1,61 @@
+//go:build linux
+
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	"github.com/pranshuparmar/witr/internal/procfs"
+)
+
+// TestAnalyzePID_TreeNamespacedChildren checks that --tree's children, which
+// come from the lightweight process table, still carry their PIDs in a
+// container's PID namespace.
+func TestAnalyzePID_TreeNamespacedChildren(t *testing.T) {
+	const id = "4f2a9c1e8b7d6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d4c3b2a1f"
+	// A containerd shim on the host, with the container's init and a host
+	// helper as children.
+	tree := fstest.MapFS{
+		"proc/1/stat":      {Data: []byte("1 (systemd) S 0 1 1 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 10 0 0\n")},
+		"proc/500/stat":    {Data: []byte("500 (containerd-shim) S 1 500 500 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 400 0 0\n")},
+		"proc/500/status":  {Data: []byte("Name:\tcontainerd-shim\nUid:\t0\t0\t0\t0\nNStgid:\t500\nNSpid:\t500\n")},
+		"proc/600/stat":    {Data: []byte("600 (redis-server) S 500 600 600 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 450 0 0\n")},
+		"proc/600/status":  {Data: []byte("Name:\tredis-server\nUid:\t999\t999\t999\t999\nNStgid:\t600\t1\nNSpid:\t600\t1\n")},
+		"proc/600/cgroup":  {Data: []byte("0::/system.slice/docker-" + id + ".scope\n")},
+		"proc/601/stat":    {Data: []byte("601 (logger) S 500 601 601 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 460 0 0\n")},
+		"proc/601/status":  {Data: []byte("Name:\tlogger\nUid:\t0\t0\t0\t0\nNStgid:\t601\nNSpid:\t601\n")},
+		"proc/601/cgroup":  {Data: []byte("0::/system.slice/containerd.service\n")},
+		"proc/500/cmdline": {Data: []byte("/usr/bin/containerd-shim-runc-v2\x00")},
+		"proc/600/cmdline": {Data: []byte("redis-server\x00*:6379\x00")},
+		"proc/601/cmdline": {Data: []byte("logger\x00")},
+	}
+	prev, prevLive := procfs.Current(), procfs.Live()
+	procfs.Set(procfs.FromFS(tree), false)
+	t.Cleanup(func() { procfs.Set(prev, prevLive) })
+
+	res, err := AnalyzePID(context.Background(), AnalyzeConfig{PID: 500, Tree: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Children) != 2 {
+		t.Fatalf("Children = %+v, want redis-server and logger", res.Children)
+	}
+	if c := res.Children[0]; !reflect.DeepEqual(c.NamespacePIDs, []int{600, 1}) || c.ContainerID != id {
+		t.Errorf("container child = %+v, want pid 1 in container %s", c, id)
+	}
+	if c := res.Children[1]; !reflect.DeepEqual(c.NamespacePIDs, []int{601}) || c.ContainerID != "" {
+		t.Errorf("host child = %+v, want only its host PID", c)
+	}
+
+	var buf bytes.Buffer
+	output.PrintTree(&buf, res.Ancestry, res.Children, false)
+	if out := buf.String(); !strings.Contains(out, "redis-server (pid 600, pid 1 in container)") || !strings.Contains(out, "logger (pid 601)\n") {
+		t.Errorf("tree:\n%s", out)
+	}
+}
@@ -4,20 +4,21 @@ package proc
 
 import (
 	"bufio"
-	"os"
+	"bytes"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/pranshuparmar/witr/internal/procfs"
 )
 
 func bootTime() time.Time {
-	f, err := os.Open("/proc/stat")
+	data, err := procfs.ReadFile("/proc/stat")
 	if err != nil {
 		return time.Now()
 	}
-	defer f.Close()
 
-	scanner := bufio.NewScanner(f)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
 	for scanner.Scan() {
 		line := scanner.Text()
 		if strings.HasPrefix(line, "btime") {
@@ -4,9 +4,10 @@ package proc
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
+
+	"github.com/pranshuparmar/witr/internal/procfs"
 )
 
 // Capability bit positions from include/uapi/linux/capability.h
@@ -56,7 +57,7 @@ var capNames = map[int]string{
 
 // ReadCapabilities reads the effective capabilities of a process from /proc/<pid>/status.
 func ReadCapabilities(pid int) []string {
-	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
+	data, err := procfs.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
 	if err != nil {
 		return nil
 	}
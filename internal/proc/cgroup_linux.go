@@ -0,0 +1,177 @@
+//go:build linux
+
+package proc
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/internal/procfs"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// cgroupRoot is where the unified (v2) cgroup hierarchy is mounted.
+const cgroupRoot = "/sys/fs/cgroup"
+
+// CgroupFiles are the files of a cgroup directory ReadCgroup reads.
+var CgroupFiles = []string{
+	"memory.current", "memory.max", "memory.events",
+	"cpu.max", "cpu.stat",
+	"pids.current", "pids.max",
+	"cpu.pressure", "memory.pressure", "io.pressure",
+}
+
+// pressureResources are the resources with a .pressure file, in the order
+// they are shown.
+var pressureResources = []string{"cpu", "memory", "io"}
+
+// CgroupDir returns the directory of pid's cgroup v2, or "" when it has none
+// witr can read: the host uses cgroup v1 (or the hybrid layout, whose
+// controllers are all v1), or the process's cgroup is outside witr's cgroup
+// namespace.
+func CgroupDir(pid int) string {
+	if !procfs.Exists(cgroupRoot + "/cgroup.controllers") {
+		return ""
+	}
+	data, err := procfs.ReadFile("/proc/" + strconv.Itoa(pid) + "/cgroup")
+	if err != nil {
+		return ""
+	}
+	path, ok := unifiedCgroupPath(string(data))
+	if !ok {
+		return ""
+	}
+	dir := cgroupRoot + strings.TrimSuffix(path, "/")
+	if !procfs.Exists(dir) {
+		return ""
+	}
+	return dir
+}
+
+// unifiedCgroupPath returns the path of the cgroup v2 ("0::") line of
+// /proc/<pid>/cgroup content. A path climbing out of the cgroup namespace
+// ("/../..") cannot be found under cgroupRoot.
+func unifiedCgroupPath(content string) (string, bool) {
+	for line := range strings.Lines(content) {
+		path, ok := strings.CutPrefix(strings.TrimSpace(line), "0::")
+		if !ok {
+			continue
+		}
+		if !strings.HasPrefix(path, "/") || strings.Contains(path, "/..") {
+			return "", false
+		}
+		return path, true
+	}
+	return "", false
+}
+
+// ReadCgroup returns the limits, usage and pressure of pid's cgroup v2, or
+// nil when it has none witr can read. A controller the cgroup does not have
+// enabled leaves its fields zero.
+func ReadCgroup(pid int) *model.CgroupContext {
+	dir := CgroupDir(pid)
+	if dir == "" {
+		return nil
+	}
+	cg := &model.CgroupContext{Path: strings.TrimPrefix(dir, cgroupRoot)}
+	if cg.Path == "" {
+		cg.Path = "/"
+	}
+
+	cg.MemoryCurrent = readCgroupValue(dir + "/memory.current")
+	cg.MemoryMax = readCgroupValue(dir + "/memory.max")
+	events := readCgroupKeyed(dir + "/memory.events")
+	cg.OOMEvents, cg.OOMKills = events["oom"], events["oom_kill"]
+
+	if data, err := procfs.ReadFile(dir + "/cpu.max"); err == nil {
+		if f := strings.Fields(string(data)); len(f) == 2 {
+			if f[0] != "max" {
+				cg.CPUQuotaUsec, _ = strconv.ParseUint(f[0], 10, 64)
+			}
+			cg.CPUPeriodUsec, _ = strconv.ParseUint(f[1], 10, 64)
+		}
+	}
+	stat := readCgroupKeyed(dir + "/cpu.stat")
+	cg.CPUPeriods, cg.CPUThrottled, cg.CPUThrottledUsec = stat["nr_periods"], stat["nr_throttled"], stat["throttled_usec"]
+
+	cg.PIDsCurrent = readCgroupValue(dir + "/pids.current")
+	cg.PIDsMax = readCgroupValue(dir + "/pids.max")
+
+	for _, res := range pressureResources {
+		data, err := procfs.ReadFile(dir + "/" + res + ".pressure")
+		if err != nil {
+			continue
+		}
+		if cg.Pressure == nil {
+			cg.Pressure = make(map[string]model.Pressure, len(pressureResources))
+		}
+		cg.Pressure[res] = parsePressure(string(data))
+	}
+	return cg
+}
+
+// readCgroupValue reads a single-value cgroup file, or 0 when it is missing.
+// "max", no limit, also reads as 0.
+func readCgroupValue(path string) uint64 {
+	data, err := procfs.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	v, _ := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	return v
+}
+
+// readCgroupKeyed reads a flat-keyed cgroup file of "key value" lines, such
+// as memory.events or cpu.stat.
+func readCgroupKeyed(path string) map[string]uint64 {
+	data, err := procfs.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	out := make(map[string]uint64)
+	for line := range strings.Lines(string(data)) {
+		if f := strings.Fields(line); len(f) == 2 {
+			if v, err := strconv.ParseUint(f[1], 10, 64); err == nil {
+				out[f[0]] = v
+			}
+		}
+	}
+	return out
+}
+
+// parsePressure parses a PSI file:
+//
+//	some avg10=0.12 avg60=0.05 avg300=0.01 total=123456
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func parsePressure(content string) model.Pressure {
+	var p model.Pressure
+	for line := range strings.Lines(content) {
+		f := strings.Fields(line)
+		if len(f) == 0 {
+			continue
+		}
+		var stall *model.PressureStall
+		switch f[0] {
+		case "some":
+			stall = &p.Some
+		case "full":
+			stall = &p.Full
+		default:
+			continue
+		}
+		for _, kv := range f[1:] {
+			k, v, _ := strings.Cut(kv, "=")
+			switch k {
+			case "avg10":
+				stall.Avg10, _ = strconv.ParseFloat(v, 64)
+			case "avg60":
+				stall.Avg60, _ = strconv.ParseFloat(v, 64)
+			case "avg300":
+				stall.Avg300, _ = strconv.ParseFloat(v, 64)
+			case "total":
+				stall.TotalUsec, _ = strconv.ParseUint(v, 10, 64)
+			}
+		}
+	}
+	return p
+}
@@ -0,0 +1,67 @@
+//go:build linux
+
+package proc
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pranshuparmar/witr/internal/procfs"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestReadCgroup(t *testing.T) {
+	const svc = "sys/fs/cgroup/system.slice/redis.service/"
+	tree := fstest.MapFS{
+		"sys/fs/cgroup/cgroup.controllers": {Data: []byte("cpuset cpu io memory pids\n")},
+		"proc/48211/cgroup":                {Data: []byte("0::/system.slice/redis.service\n")},
+		svc + "memory.current":             {Data: []byte("2080374784\n")},
+		svc + "memory.max":                 {Data: []byte("2147483648\n")},
+		svc + "memory.events":              {Data: []byte("low 0\nhigh 0\nmax 412\noom 5\noom_kill 3\noom_group_kill 0\n")},
+		svc + "cpu.max":                    {Data: []byte("50000 100000\n")},
+		svc + "cpu.stat":                   {Data: []byte("usage_usec 9000000\nnr_periods 4000\nnr_throttled 120\nthrottled_usec 12340000\n")},
+		svc + "pids.current":               {Data: []byte("12\n")},
+		svc + "pids.max":                   {Data: []byte("max\n")},
+		svc + "memory.pressure":            {Data: []byte("some avg10=12.50 avg60=4.20 avg300=1.00 total=99000\nfull avg10=3.00 avg60=1.00 avg300=0.25 total=4000\n")},
+		// The root cgroup, whose limits are the machine's.
+		"proc/2/cgroup":              {Data: []byte("0::/\n")},
+		"sys/fs/cgroup/cpu.pressure": {Data: []byte("some avg10=0.00 avg60=0.00 avg300=0.00 total=0\n")},
+		"proc/700/cgroup":            {Data: []byte("0::/../../other.scope\n")},
+		"proc/701/cgroup":            {Data: []byte("0::/gone.scope\n")},
+	}
+	prev, prevLive := procfs.Current(), procfs.Live()
+	procfs.Set(procfs.FromFS(tree), false)
+	t.Cleanup(func() { procfs.Set(prev, prevLive) })
+
+	want := &model.CgroupContext{
+		Path:          "/system.slice/redis.service",
+		MemoryCurrent: 2080374784, MemoryMax: 2147483648,
+		OOMEvents: 5, OOMKills: 3,
+		CPUQuotaUsec: 50000, CPUPeriodUsec: 100000,
+		CPUPeriods: 4000, CPUThrottled: 120, CPUThrottledUsec: 12340000,
+		PIDsCurrent: 12,
+		Pressure: map[string]model.Pressure{"memory": {
+			Some: model.PressureStall{Avg10: 12.5, Avg60: 4.2, Avg300: 1, TotalUsec: 99000},
+			Full: model.PressureStall{Avg10: 3, Avg60: 1, Avg300: 0.25, TotalUsec: 4000},
+		}},
+	}
+	if got := ReadCgroup(48211); !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadCgroup =\n%+v\nwant\n%+v", got, want)
+	}
+	if got := ReadCgroup(2); got == nil || got.Path != "/" || len(got.Pressure) != 1 {
+		t.Errorf("ReadCgroup(root cgroup) = %+v, want path / with cpu pressure", got)
+	}
+	// Outside witr's cgroup namespace, or a cgroup that no longer exists.
+	for _, pid := range []int{700, 701, 999} {
+		if got := ReadCgroup(pid); got != nil {
+			t.Errorf("ReadCgroup(%d) = %+v, want nil", pid, got)
+		}
+	}
+
+	// A cgroup v1 or hybrid host has no unified controllers.
+	delete(tree, "sys/fs/cgroup/cgroup.controllers")
+	if got := ReadCgroup(48211); got != nil {
+		t.Errorf("ReadCgroup without cgroup v2 = %+v, want nil", got)
+	}
+}
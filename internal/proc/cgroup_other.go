@@ -0,0 +1,18 @@
+//go:build !linux
+
+package proc
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+// CgroupFiles is empty: cgroups are Linux only.
+var CgroupFiles []string
+
+// CgroupDir returns "": cgroups are Linux only.
+func CgroupDir(pid int) string {
+	return ""
+}
+
+// ReadCgroup returns nil: cgroups are Linux only.
+func ReadCgroup(pid int) *model.CgroupContext {
+	return nil
+}
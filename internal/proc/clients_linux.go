@@ -0,0 +1,127 @@
+//go:build linux
+
+package proc
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/internal/procfs"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// ConnectedClients returns the processes at the other end of the connections
+// accepted on sockets, the sockets of one process, ordered by PID. The peer
+// of a TCP connection is the socket with the same two ends swapped, so only
+// clients on this host (over loopback or one of its own addresses) are
+// found. The peer of a unix connection comes from UnixPeers, on the live
+// system only.
+func ConnectedClients(ctx context.Context, sockets []model.Socket) ([]model.Client, error) {
+	listening := make(map[int]bool)
+	for _, s := range sockets {
+		if s.State == "LISTEN" && strings.HasPrefix(s.Protocol, "TCP") {
+			listening[s.Port] = true
+		}
+	}
+
+	// The server ends: a connection on a TCP port the process listens on
+	// was accepted, not made, and so were its connected unix sockets, since
+	// only those carry the path.
+	var accepted []model.Socket
+	hasUnix := false
+	for _, s := range sockets {
+		if s.State != "ESTABLISHED" {
+			continue
+		}
+		switch {
+		case s.Protocol == "UNIX":
+			accepted = append(accepted, s)
+			hasUnix = true
+		case strings.HasPrefix(s.Protocol, "TCP") && listening[s.Port]:
+			accepted = append(accepted, s)
+		}
+	}
+	if len(accepted) == 0 {
+		return nil, nil
+	}
+
+	all, err := Sockets()
+	if err != nil {
+		return nil, err
+	}
+	byEnds := make(map[string]string)
+	for inode, s := range all {
+		if s.State == "ESTABLISHED" && s.RemotePort > 0 && strings.HasPrefix(s.Protocol, "TCP") {
+			byEnds[connectionKey(s.Netns, s.Address, s.Port, s.RemoteAddress, s.RemotePort)] = inode
+		}
+	}
+	var unixPeers map[string]string
+	if hasUnix && procfs.Live() {
+		// Without sock_diag the unix clients stay unknown; the TCP ones can
+		// still be found.
+		unixPeers, _ = UnixPeers()
+	}
+
+	peerOf := make(map[string]string)
+	peers := make(map[string]bool)
+	for _, s := range accepted {
+		var peer string
+		if s.Protocol == "UNIX" {
+			peer = unixPeers[s.Inode]
+		} else {
+			peer = byEnds[connectionKey(s.Netns, s.RemoteAddress, s.RemotePort, s.Address, s.Port)]
+		}
+		if peer != "" {
+			peerOf[s.Inode] = peer
+			peers[peer] = true
+		}
+	}
+	if len(peers) == 0 {
+		return nil, nil
+	}
+
+	holders, err := SocketHolders(ctx, peers)
+	if err != nil {
+		return nil, err
+	}
+	idx := IndexFrom(ctx)
+	byPID := make(map[int]*model.Client)
+	for _, s := range accepted {
+		for _, pid := range holders[peerOf[s.Inode]] {
+			c, ok := byPID[pid]
+			if !ok {
+				ancestry, err := idx.Ancestry(pid)
+				if err != nil {
+					// The client exited since its socket was read.
+					continue
+				}
+				c = &model.Client{PID: pid, Ancestry: ancestry}
+				byPID[pid] = c
+			}
+			c.Sockets = append(c.Sockets, s)
+		}
+	}
+
+	clients := make([]model.Client, 0, len(byPID))
+	for _, c := range byPID {
+		clients = append(clients, *c)
+	}
+	sort.Slice(clients, func(i, j int) bool { return clients[i].PID < clients[j].PID })
+	return clients, nil
+}
+
+// connectionKey identifies a TCP connection by its network namespace and
+// its local and remote ends. Addresses are normalized, so an IPv4-mapped
+// address in tcp6 matches the plain one in tcp.
+func connectionKey(netns, addr string, port int, raddr string, rport int) string {
+	norm := func(a string) string {
+		if ip := net.ParseIP(a); ip != nil {
+			return ip.String()
+		}
+		return a
+	}
+	return netns + "|" + net.JoinHostPort(norm(addr), strconv.Itoa(port)) + "|" + net.JoinHostPort(norm(raddr), strconv.Itoa(rport))
+}
@@ -0,0 +1,115 @@
+//go:build linux
+
+package proc
+
+import (
+	"context"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pranshuparmar/witr/internal/procfs"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestConnectedClients(t *testing.T) {
+	const header = "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+	const tail = " 00000000:00000000 00:00000000 00000000  1000        0 "
+	sock := func(target string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(target), Mode: fs.ModeSymlink}
+	}
+	tree := fstest.MapFS{
+		"proc/net/tcp": {Data: []byte(header +
+			// postgres listens on 127.0.0.1:5432 and accepted psql's
+			// connection from port 40000 ...
+			"   0: 0100007F:1538 00000000:0000 0A" + tail + "8001 1\n" +
+			"   1: 0100007F:1538 0100007F:9C40 01" + tail + "8002 1\n" +
+			"   2: 0100007F:9C40 0100007F:1538 01" + tail + "8003 1\n" +
+			// ... and made its own to redis, which is not a client.
+			"   3: 0100007F:A028 0100007F:18EB 01" + tail + "8004 1\n" +
+			"   4: 0100007F:18EB 0100007F:A028 01" + tail + "8005 1\n" +
+			// psql's second connection, accepted on the dual-stack socket.
+			"   5: 0100007F:9C41 0100007F:1538 01" + tail + "8007 1\n")},
+		"proc/net/tcp6": {Data: []byte(header +
+			"   0: 0000000000000000FFFF00000100007F:1538 0000000000000000FFFF00000100007F:9C41 01" + tail + "8006 1\n")},
+		"proc/1/stat":       {Data: []byte("1 (systemd) S 0 1 1 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 10 0 0\n")},
+		"proc/100/stat":     {Data: []byte("100 (bash) S 1 100 100 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 300 0 0\n")},
+		"proc/310/stat":     {Data: []byte("310 (postgres) S 1 310 310 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 500 0 0\n")},
+		"proc/500/stat":     {Data: []byte("500 (redis-server) S 1 500 500 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 400 0 0\n")},
+		"proc/4242/stat":    {Data: []byte("4242 (psql) S 100 4242 4242 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 900 0 0\n")},
+		"proc/310/fd/3":     sock("socket:[8001]"),
+		"proc/310/fd/4":     sock("socket:[8002]"),
+		"proc/310/fd/5":     sock("socket:[8004]"),
+		"proc/310/fd/6":     sock("socket:[8006]"),
+		"proc/500/fd/7":     sock("socket:[8005]"),
+		"proc/4242/fd/3":    sock("socket:[8003]"),
+		"proc/4242/fd/4":    sock("socket:[8007]"),
+		"proc/4242/fd/5":    sock("/dev/pts/0"),
+		"proc/4242/fd/6":    sock("socket:[8003]"),
+		"proc/100/fd/0":     sock("/dev/pts/0"),
+		"proc/4242/cmdline": {Data: []byte("psql\x00-h\x00127.0.0.1\x00")},
+	}
+	prev, prevLive := procfs.Current(), procfs.Live()
+	procfs.Set(procfs.FromFS(tree), false)
+	t.Cleanup(func() { procfs.Set(prev, prevLive) })
+
+	all, err := Sockets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var server []model.Socket
+	for _, inode := range []string{"8001", "8002", "8004", "8006"} {
+		server = append(server, all[inode])
+	}
+
+	ctx := WithIndex(context.Background(), NewIndex())
+	clients, err := ConnectedClients(ctx, server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clients) != 1 {
+		t.Fatalf("ConnectedClients = %+v, want only psql", clients)
+	}
+	c := clients[0]
+	if c.PID != 4242 || len(c.Ancestry) != 3 || c.Ancestry[0].PID != 1 || c.Ancestry[1].PID != 100 {
+		t.Errorf("client = pid %d with %d ancestors, want psql (4242) under bash and systemd", c.PID, len(c.Ancestry))
+	}
+	if len(c.Sockets) != 2 || c.Sockets[0].Inode != "8002" || c.Sockets[1].Inode != "8006" {
+		t.Errorf("client sockets = %+v, want the two accepted connections", c.Sockets)
+	}
+
+	// Without an accepted connection nothing is looked up.
+	if clients, err := ConnectedClients(ctx, []model.Socket{all["8001"], all["8004"]}); err != nil || clients != nil {
+		t.Errorf("ConnectedClients(listener, outbound) = %+v, %v; want none", clients, err)
+	}
+}
+
+func TestSocketHolders(t *testing.T) {
+	sock := func(target string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(target), Mode: fs.ModeSymlink}
+	}
+	prev, prevLive := procfs.Current(), procfs.Live()
+	procfs.Set(procfs.FromFS(fstest.MapFS{
+		// A listener inherited by a worker is held by both.
+		"proc/20/fd/3": sock("socket:[9001]"),
+		"proc/20/fd/4": sock("socket:[9001]"),
+		"proc/7/fd/3":  sock("socket:[9001]"),
+		"proc/7/fd/4":  sock("socket:[9002]"),
+		"proc/8/fd/1":  sock("pipe:[9001]"),
+	}), false)
+	t.Cleanup(func() { procfs.Set(prev, prevLive) })
+
+	holders, err := SocketHolders(context.Background(), map[string]bool{"9001": true, "9002": true, "9003": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h := holders["9001"]; len(h) != 2 || h[0] != 7 || h[1] != 20 {
+		t.Errorf("holders of 9001 = %v, want [7 20]", h)
+	}
+	if h := holders["9002"]; len(h) != 1 || h[0] != 7 {
+		t.Errorf("holders of 9002 = %v, want [7]", h)
+	}
+	if _, ok := holders["9003"]; ok {
+		t.Error("an inode nobody holds should be left out")
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package proc
+
+import (
+	"context"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// ConnectedClients needs the socket peers only Linux exposes; elsewhere no
+// clients are found.
+func ConnectedClients(ctx context.Context, sockets []model.Socket) ([]model.Client, error) {
+	return nil, nil
+}
@@ -4,13 +4,14 @@ package proc
 
 import (
 	"fmt"
-	"os"
 	"strings"
+
+	"github.com/pranshuparmar/witr/internal/procfs"
 )
 
 // GetCmdline returns the command line for a given PID
 func GetCmdline(pid int) string {
-	cmdlineBytes, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	cmdlineBytes, err := procfs.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
 	if err != nil {
 		return "(unknown)"
 	}
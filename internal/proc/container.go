@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
-	"time"
 	"unicode"
 
 	"github.com/pranshuparmar/witr/pkg/model"
@@ -13,12 +12,15 @@ import (
 
 // ResolveContainerByPort queries the Docker CLI for a container publishing
 // the given port. Returns nil if Docker is unavailable or no container matches.
-func ResolveContainerByPort(port int) *model.ContainerMatch {
+func ResolveContainerByPort(ctx context.Context, port int) *model.ContainerMatch {
+	if replaying() {
+		return recordedContainerByPort(port)
+	}
 	if _, err := exec.LookPath("docker"); err != nil {
 		return nil
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, runtimeQueryTimeout)
 	defer cancel()
 
 	format := strings.Join([]string{
@@ -66,6 +68,24 @@ func ResolveContainerByPort(port int) *model.ContainerMatch {
 	}
 }
 
+// recordedContainerByPort is ResolveContainerByPort against recorded docker
+// listings: the Ports column reads like "0.0.0.0:8080->80/tcp".
+func recordedContainerByPort(port int) *model.ContainerMatch {
+	published := fmt.Sprintf(":%d->", port)
+	for _, r := range recorded {
+		if r.Name != "docker" {
+			continue
+		}
+		for _, c := range r.Containers {
+			if strings.Contains(c.Ports, published) {
+				m := c.ContainerMatch
+				return &m
+			}
+		}
+	}
+	return nil
+}
+
 // isValidContainerID reports whether id is a safe container identifier to hand
 // to a runtime CLI: a non-empty token of [A-Za-z0-9_.-] that begins with an
 // alphanumeric. Rejecting a leading dash (and any other metacharacter) keeps a
@@ -91,35 +111,40 @@ func resolveContainerName(id, runtime string) string {
 	if !isValidContainerID(id) {
 		return ""
 	}
+	if replaying() {
+		c := recordedContainer(runtime, id)
+		if c == nil {
+			return ""
+		}
+		return containerLabel(runtime, c.Name, c.ComposeProject, c.ComposeService)
+	}
 	var cmd *exec.Cmd
-	var prefix string
 
-	ctx := context.Background()
+	// Called for every containerized process ReadProcess sees, without a
+	// caller's context, so it gets a deadline of its own.
+	ctx, cancel := context.WithTimeout(context.Background(), runtimeQueryTimeout)
+	defer cancel()
 	switch runtime {
 	case "docker":
 		if _, err := exec.LookPath("docker"); err != nil {
 			return ""
 		}
 		cmd = exec.CommandContext(ctx, "docker", "inspect", "--format", "{{.Name}}|{{index .Config.Labels \"com.docker.compose.project\"}}|{{index .Config.Labels \"com.docker.compose.service\"}}", "--", id)
-		prefix = "docker: "
 	case "podman":
 		if _, err := exec.LookPath("podman"); err != nil {
 			return ""
 		}
 		cmd = commandAsOriginalUser(ctx, "podman", "inspect", "--format", "{{.Name}}", "--", id)
-		prefix = "podman: "
 	case "crictl":
 		if _, err := exec.LookPath("crictl"); err != nil {
 			return ""
 		}
 		cmd = exec.CommandContext(ctx, "crictl", "inspect", id, "-o", "go-template", "--template", "{{.status.metadata.name}}")
-		prefix = "" // crictl names are usually clean
 	case "nerdctl":
 		if _, err := exec.LookPath("nerdctl"); err != nil {
 			return ""
 		}
 		cmd = commandAsOriginalUser(ctx, "nerdctl", "inspect", id, "--format", "{{.Name}}")
-		prefix = "containerd: "
 	default:
 		return ""
 	}
@@ -133,41 +158,54 @@ func resolveContainerName(id, runtime string) string {
 	if runtime == "docker" {
 		parts := strings.Split(output, "|")
 		if len(parts) == 3 {
-			name := strings.TrimPrefix(parts[0], "/")
-			project := parts[1]
-			service := parts[2]
-
-			if project != "" && service != "" {
-				return "docker: " + project + "/" + service + " (" + name + ")"
-			}
-			if name != "" {
-				return "docker: " + name
-			}
-			return ""
+			return containerLabel(runtime, parts[0], parts[1], parts[2])
 		}
 	}
 
-	name := strings.TrimPrefix(output, "/")
-	if name != "" {
-		if prefix != "" {
-			return prefix + name
+	return containerLabel(runtime, output, "", "")
+}
+
+// containerLabel formats a container for the Process.Container line:
+// "docker: project/service (name)" for compose services, otherwise the name
+// behind a runtime prefix.
+func containerLabel(runtime, name, project, service string) string {
+	name = strings.TrimPrefix(name, "/")
+	var prefix string
+	switch runtime {
+	case "docker":
+		if project != "" && service != "" {
+			return "docker: " + project + "/" + service + " (" + name + ")"
 		}
-		return name
+		prefix = "docker: "
+	case "podman":
+		prefix = "podman: "
+	case "nerdctl":
+		prefix = "containerd: "
 	}
-	return ""
+	// crictl names are usually clean and go without a prefix.
+	if name == "" {
+		return ""
+	}
+	return prefix + name
 }
 
 // ContainerHealthcheckStatus reports whether the container runtime has a
 // healthcheck configured: "present", "absent", or "" when undeterminable
 // (runtime unavailable, inspect error, or unsupported runtime).
-func ContainerHealthcheckStatus(id, runtime string) string {
+func ContainerHealthcheckStatus(ctx context.Context, id, runtime string) string {
 	if !isValidContainerID(id) || (runtime != "docker" && runtime != "podman") {
 		return ""
 	}
+	if replaying() {
+		if c := recordedContainer(runtime, id); c != nil {
+			return c.Healthcheck
+		}
+		return ""
+	}
 	if _, err := exec.LookPath(runtime); err != nil {
 		return ""
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), runtimeQueryTimeout)
+	ctx, cancel := context.WithTimeout(ctx, runtimeQueryTimeout)
 	defer cancel()
 	out, err := runtimeCommand(ctx, runtime, "inspect", "--format", "{{if .Config.Healthcheck}}present{{else}}absent{{end}}", "--", id).Output()
 	if err != nil {
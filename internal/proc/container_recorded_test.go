@@ -0,0 +1,105 @@
+package proc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestRecordedContainers(t *testing.T) {
+	const redisID = "3f2a9c1b7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f2a1b0c9d8e7f6a5b4c3d2e1f0a"
+	UseRecordedContainers([]RecordedRuntime{
+		{
+			Name: "docker",
+			Containers: []RecordedContainer{
+				{
+					ContainerMatch: model.ContainerMatch{
+						Runtime: "docker", ID: redisID[:12], Name: "cache-redis-1", Image: "redis:7",
+						Ports: "0.0.0.0:6379->6379/tcp", ComposeProject: "cache", ComposeService: "redis",
+					},
+					HostPID:     4242,
+					Healthcheck: "absent",
+				},
+			},
+		},
+		{
+			Name: "podman",
+			Containers: []RecordedContainer{
+				{ContainerMatch: model.ContainerMatch{Runtime: "podman", ID: "abc123def456", Name: "web"}},
+			},
+		},
+	})
+	t.Cleanup(UseLiveContainers)
+
+	if got := ResolveContainer(context.Background(), "redis", false); len(got) != 1 || got[0].Name != "cache-redis-1" {
+		t.Fatalf("ResolveContainer(redis) = %v, want the recorded redis container", got)
+	}
+	// Callers enrich matches in place; that must not leak into the recording.
+	ResolveContainer(context.Background(), "redis", false)[0].Name = "mutated"
+	if got := ListAllContainers(context.Background()); len(got) != 2 || got[0].Name != "cache-redis-1" {
+		t.Errorf("ListAllContainers() = %v, want both recorded containers unchanged", got)
+	}
+
+	// The cgroup carries the full ID; the recording holds the short one.
+	if pid := ResolveContainerHostPID(context.Background(), "docker", redisID); pid != 4242 {
+		t.Errorf("ResolveContainerHostPID = %d, want 4242", pid)
+	}
+	if got, want := resolveContainerName(redisID, "docker"), "docker: cache/redis (cache-redis-1)"; got != want {
+		t.Errorf("resolveContainerName(docker) = %q, want %q", got, want)
+	}
+	if got, want := resolveContainerName("abc123def456", "podman"), "podman: web"; got != want {
+		t.Errorf("resolveContainerName(podman) = %q, want %q", got, want)
+	}
+	if got := resolveContainerName("ffffffffffff", "docker"); got != "" {
+		t.Errorf("resolveContainerName(unknown) = %q, want empty", got)
+	}
+	if got := ContainerHealthcheckStatus(context.Background(), redisID, "docker"); got != "absent" {
+		t.Errorf("ContainerHealthcheckStatus = %q, want absent", got)
+	}
+	if got := ResolveContainerByPort(context.Background(), 6379); got == nil || got.Name != "cache-redis-1" {
+		t.Errorf("ResolveContainerByPort(6379) = %v, want cache-redis-1", got)
+	}
+	if got := ResolveContainerByPort(context.Background(), 637); got != nil {
+		t.Errorf("ResolveContainerByPort(637) = %v, want nil", got)
+	}
+	if got := resolveDockerProxyContainer("docker-proxy -container-ip 172.17.0.2"); got != "" {
+		t.Errorf("resolveDockerProxyContainer while replaying = %q, want empty", got)
+	}
+}
+
+func TestUseRecordedContainersEmpty(t *testing.T) {
+	UseRecordedContainers(nil)
+	t.Cleanup(UseLiveContainers)
+
+	if !replaying() {
+		t.Fatal("an empty recording must still replace the live runtimes")
+	}
+	if got := ListAllContainers(context.Background()); len(got) != 0 {
+		t.Errorf("ListAllContainers() = %v, want none", got)
+	}
+
+	UseLiveContainers()
+	if replaying() || len(registeredRuntimes) == 0 {
+		t.Error("UseLiveContainers did not restore the registered runtimes")
+	}
+}
+
+func TestContainerLabel(t *testing.T) {
+	tests := []struct {
+		runtime, name, project, service string
+		want                            string
+	}{
+		{"docker", "/web", "", "", "docker: web"},
+		{"docker", "web-1", "shop", "web", "docker: shop/web (web-1)"},
+		{"podman", "db", "", "", "podman: db"},
+		{"nerdctl", "api", "", "", "containerd: api"},
+		{"crictl", "coredns", "", "", "coredns"},
+		{"docker", "", "", "", ""},
+	}
+	for _, tt := range tests {
+		if got := containerLabel(tt.runtime, tt.name, tt.project, tt.service); got != tt.want {
+			t.Errorf("containerLabel(%q, %q, %q, %q) = %q, want %q", tt.runtime, tt.name, tt.project, tt.service, got, tt.want)
+		}
+	}
+}
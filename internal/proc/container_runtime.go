@@ -1,18 +1,20 @@
 package proc
 
 import (
+	"context"
 	"strings"
 
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
 // ContainerRuntime is a backend (docker, podman, …) that can list containers
-// and resolve a container's main host PID.
+// and resolve a container's main host PID. List and HostPID run the
+// runtime's CLI and give up, returning nothing, when ctx is done.
 type ContainerRuntime interface {
 	Name() string
 	Available() bool
-	List() []*model.ContainerMatch
-	HostPID(id string) int
+	List(ctx context.Context) []*model.ContainerMatch
+	HostPID(ctx context.Context, id string) int
 }
 
 var registeredRuntimes []ContainerRuntime
@@ -25,7 +27,7 @@ func registerRuntime(rt ContainerRuntime) {
 // merged set of matches against the query. Match predicate is substring
 // (case-insensitive) across name, image, and command, unless exact is true in
 // which case any of those fields must equal the query.
-func ResolveContainer(query string, exact bool) []*model.ContainerMatch {
+func ResolveContainer(ctx context.Context, query string, exact bool) []*model.ContainerMatch {
 	q := strings.ToLower(query)
 	var out []*model.ContainerMatch
 	seen := make(map[string]bool)
@@ -33,7 +35,7 @@ func ResolveContainer(query string, exact bool) []*model.ContainerMatch {
 		if !rt.Available() {
 			continue
 		}
-		for _, c := range rt.List() {
+		for _, c := range rt.List(ctx) {
 			if !matchContainer(c, q, exact) {
 				continue
 			}
@@ -73,14 +75,14 @@ func matchContainer(c *model.ContainerMatch, query string, exact bool) bool {
 
 // ListAllContainers returns every container reported by every available
 // runtime, deduped by runtime|id. Used by the TUI's Containers tab.
-func ListAllContainers() []*model.ContainerMatch {
+func ListAllContainers(ctx context.Context) []*model.ContainerMatch {
 	var out []*model.ContainerMatch
 	seen := make(map[string]bool)
 	for _, rt := range registeredRuntimes {
 		if !rt.Available() {
 			continue
 		}
-		for _, c := range rt.List() {
+		for _, c := range rt.List(ctx) {
 			key := rt.Name() + "|" + c.ID
 			if seen[key] {
 				continue
@@ -95,10 +97,10 @@ func ListAllContainers() []*model.ContainerMatch {
 // ResolveContainerHostPID returns the PID of the container's main process on
 // the host. Returns 0 if the runtime can't be reached or the PID isn't
 // available (container not running, namespaced PID, etc.).
-func ResolveContainerHostPID(runtime, id string) int {
+func ResolveContainerHostPID(ctx context.Context, runtime, id string) int {
 	for _, rt := range registeredRuntimes {
 		if rt.Name() == runtime && rt.Available() {
-			return rt.HostPID(id)
+			return rt.HostPID(ctx, id)
 		}
 	}
 	return 0
@@ -109,13 +111,13 @@ func ResolveContainerHostPID(runtime, id string) int {
 // resolved and the caller wants richer metadata than the initial list scan
 // produced.
 type enrichingRuntime interface {
-	Enrich(*model.ContainerMatch)
+	Enrich(context.Context, *model.ContainerMatch)
 }
 
 // EnrichContainer asks the originating runtime to fill in any extra fields
 // available via a per-container query (e.g. crictl inspect). No-op when the
 // runtime doesn't expose extra detail.
-func EnrichContainer(match *model.ContainerMatch) {
+func EnrichContainer(ctx context.Context, match *model.ContainerMatch) {
 	if match == nil {
 		return
 	}
@@ -124,8 +126,135 @@ func EnrichContainer(match *model.ContainerMatch) {
 			continue
 		}
 		if e, ok := rt.(enrichingRuntime); ok {
-			e.Enrich(match)
+			e.Enrich(ctx, match)
 		}
 		return
 	}
 }
+
+// RecordedRuntime is one runtime's container listing frozen at a point in
+// time, as stored in a snapshot.
+type RecordedRuntime struct {
+	Name       string
+	Containers []RecordedContainer
+}
+
+// RecordedContainer is a container as its runtime listed and enriched it,
+// together with the per-container answers that would otherwise need another
+// runtime CLI call.
+type RecordedContainer struct {
+	model.ContainerMatch
+	HostPID     int    `json:",omitempty"`
+	Healthcheck string `json:",omitempty"`
+}
+
+// RecordContainers queries every available runtime and returns its listing
+// with each container enriched and its host PID resolved, so that
+// UseRecordedContainers can later answer every container lookup offline.
+func RecordContainers(ctx context.Context) []RecordedRuntime {
+	var out []RecordedRuntime
+	for _, rt := range registeredRuntimes {
+		if !rt.Available() {
+			continue
+		}
+		rec := RecordedRuntime{Name: rt.Name()}
+		for _, c := range rt.List(ctx) {
+			if e, ok := rt.(enrichingRuntime); ok {
+				e.Enrich(ctx, c)
+			}
+			rec.Containers = append(rec.Containers, RecordedContainer{
+				ContainerMatch: *c,
+				HostPID:        rt.HostPID(ctx, c.ID),
+				Healthcheck:    ContainerHealthcheckStatus(ctx, c.ID, rt.Name()),
+			})
+		}
+		out = append(out, rec)
+	}
+	return out
+}
+
+var (
+	liveRuntimes []ContainerRuntime
+	recorded     []RecordedRuntime
+)
+
+// UseRecordedContainers replaces the registered runtimes with recorded
+// listings. From then on no runtime CLI is run: container lookups, names,
+// host PIDs and healthchecks are all answered from recs.
+func UseRecordedContainers(recs []RecordedRuntime) {
+	if !replaying() {
+		liveRuntimes = registeredRuntimes
+	}
+	recorded = recs
+	if recorded == nil {
+		recorded = []RecordedRuntime{}
+	}
+	registeredRuntimes = make([]ContainerRuntime, 0, len(recs))
+	for _, r := range recs {
+		registeredRuntimes = append(registeredRuntimes, recordedRuntime{r})
+	}
+}
+
+// UseLiveContainers undoes UseRecordedContainers.
+func UseLiveContainers() {
+	if !replaying() {
+		return
+	}
+	registeredRuntimes = liveRuntimes
+	liveRuntimes = nil
+	recorded = nil
+}
+
+// replaying reports whether container state comes from a recording.
+func replaying() bool {
+	return recorded != nil
+}
+
+// recordedContainer finds a recorded container by runtime and ID. Either
+// side may be the abbreviated form, since cgroup paths carry full IDs while
+// some runtimes list short ones.
+func recordedContainer(runtime, id string) *RecordedContainer {
+	if id == "" {
+		return nil
+	}
+	for i := range recorded {
+		if recorded[i].Name != runtime {
+			continue
+		}
+		for j := range recorded[i].Containers {
+			c := &recorded[i].Containers[j]
+			if c.ID == "" {
+				continue
+			}
+			if strings.HasPrefix(c.ID, id) || strings.HasPrefix(id, c.ID) {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// recordedRuntime serves a RecordedRuntime through the ContainerRuntime
+// interface. List hands out copies so callers can enrich them freely.
+type recordedRuntime struct {
+	rec RecordedRuntime
+}
+
+func (r recordedRuntime) Name() string    { return r.rec.Name }
+func (r recordedRuntime) Available() bool { return true }
+
+func (r recordedRuntime) List(ctx context.Context) []*model.ContainerMatch {
+	out := make([]*model.ContainerMatch, 0, len(r.rec.Containers))
+	for _, c := range r.rec.Containers {
+		m := c.ContainerMatch
+		out = append(out, &m)
+	}
+	return out
+}
+
+func (r recordedRuntime) HostPID(ctx context.Context, id string) int {
+	if c := recordedContainer(r.rec.Name, id); c != nil {
+		return c.HostPID
+	}
+	return 0
+}
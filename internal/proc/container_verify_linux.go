@@ -4,8 +4,9 @@ package proc
 
 import (
 	"fmt"
-	"os"
 	"strings"
+
+	"github.com/pranshuparmar/witr/internal/procfs"
 )
 
 // PIDBelongsToContainer verifies that the host process at pid actually belongs
@@ -16,7 +17,7 @@ func PIDBelongsToContainer(pid int, containerID string) bool {
 	if pid <= 0 || containerID == "" {
 		return false
 	}
-	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	data, err := procfs.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
 	if err != nil {
 		return false
 	}
@@ -14,7 +14,7 @@ func resolveDockerProxyContainer(cmdline string) string {
 			break
 		}
 	}
-	if containerIP == "" {
+	if containerIP == "" || replaying() {
 		return ""
 	}
 
@@ -3,10 +3,13 @@
 package proc
 
 import (
-	"os"
+	"context"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/pranshuparmar/witr/internal/procfs"
 )
 
 func socketsForPID(pid int) []string {
@@ -14,13 +17,13 @@ func socketsForPID(pid int) []string {
 	seen := make(map[string]bool)
 	fdPath := "/proc/" + strconv.Itoa(pid) + "/fd"
 
-	entries, err := os.ReadDir(fdPath)
+	entries, err := procfs.ReadDir(fdPath)
 	if err != nil {
 		return inodes
 	}
 
 	for _, e := range entries {
-		link, err := os.Readlink(filepath.Join(fdPath, e.Name()))
+		link, err := procfs.ReadLink(filepath.Join(fdPath, e.Name()))
 		if err != nil {
 			continue
 		}
@@ -36,3 +39,46 @@ func socketsForPID(pid int) []string {
 
 	return inodes
 }
+
+// SocketHolders maps each of the socket inodes to the PIDs with a descriptor
+// open on it, in ascending order. Inodes no process holds are left out.
+func SocketHolders(ctx context.Context, inodes map[string]bool) (map[string][]int, error) {
+	holders := make(map[string][]int)
+	entries, _ := procfs.ReadDir("/proc")
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		fdDir := filepath.Join("/proc", entry.Name(), "fd")
+		fds, err := procfs.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			link, err := procfs.ReadLink(filepath.Join(fdDir, fd.Name()))
+			if err != nil {
+				continue
+			}
+			rest, ok := strings.CutPrefix(link, "socket:[")
+			if !ok {
+				continue
+			}
+			inode, ok := strings.CutSuffix(rest, "]")
+			if !ok || !inodes[inode] {
+				continue
+			}
+			if h := holders[inode]; len(h) == 0 || h[len(h)-1] != pid {
+				holders[inode] = append(h, pid)
+			}
+		}
+	}
+	for _, pids := range holders {
+		sort.Ints(pids)
+	}
+	return holders, nil
+}
@@ -4,7 +4,6 @@ package proc
 
 import (
 	"fmt"
-	"os"
 	"sort"
 	"strconv"
 	"strings"
@@ -12,6 +11,7 @@ import (
 
 	"golang.org/x/sys/unix"
 
+	"github.com/pranshuparmar/witr/internal/procfs"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
@@ -21,7 +21,7 @@ func GetFileContext(pid int) *model.FileContext {
 	var fileContext model.FileContext
 
 	fdDir := fmt.Sprintf("/proc/%v/fd", pid)
-	fdFiles, err := os.ReadDir(fdDir)
+	fdFiles, err := procfs.ReadDir(fdDir)
 	if err != nil {
 		return nil
 	}
@@ -37,7 +37,7 @@ func getFileLimit(pid int) int {
 	var linuxDefaultMaxOpenFile = getDefaultMaxOpenFiles()
 
 	// Read /proc/<pid>/limits for file limit
-	data, err := os.ReadFile(fmt.Sprintf("/proc/%v/limits", pid))
+	data, err := procfs.ReadFile(fmt.Sprintf("/proc/%v/limits", pid))
 	if err != nil {
 		return linuxDefaultMaxOpenFile
 	}
@@ -89,7 +89,7 @@ func getDefaultMaxOpenFiles() int {
 // the process's own open fds, keeping the work bounded to this one process.
 // (lslocks resolves every lock on the system and blocks on slow mounts.)
 func getLockedFiles(pid int) []string {
-	data, err := os.ReadFile("/proc/locks")
+	data, err := procfs.ReadFile("/proc/locks")
 	if err != nil {
 		return nil
 	}
@@ -124,23 +124,23 @@ func getLockedFiles(pid int) []string {
 	// lock that can't be matched to an open fd (e.g. an unlinked file).
 	paths := map[fileID]string{}
 	fdDir := fmt.Sprintf("/proc/%d/fd", pid)
-	if entries, err := os.ReadDir(fdDir); err == nil {
+	if entries, err := procfs.ReadDir(fdDir); err == nil {
 		for _, e := range entries {
 			if len(paths) == len(ids) {
 				break
 			}
 			fdPath := fdDir + "/" + e.Name()
-			info, err := os.Stat(fdPath)
+			info, err := procfs.Stat(fdPath)
 			if err != nil {
 				continue
 			}
-			st, ok := info.Sys().(*syscall.Stat_t)
+			st, ok := procfs.StatID(info)
 			if !ok {
 				continue
 			}
-			id := fileID{uint64(st.Dev), uint64(st.Ino)}
+			id := fileID{st.Dev, st.Ino}
 			if _, want := ids[id]; want {
-				if target, err := os.Readlink(fdPath); err == nil {
+				if target, err := procfs.ReadLink(fdPath); err == nil {
 					paths[id] = target
 				}
 			}
@@ -1,9 +1,10 @@
 package proc
 
 import (
-	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/pranshuparmar/witr/internal/procfs"
 )
 
 func detectGitInfo(cwd string) (string, string) {
@@ -14,7 +15,7 @@ func detectGitInfo(cwd string) (string, string) {
 	searchDir := cwd
 	for depth := 0; depth < 10; depth++ {
 		gitPath := filepath.Join(searchDir, ".git")
-		if fi, err := os.Stat(gitPath); err == nil {
+		if fi, err := procfs.Stat(gitPath); err == nil {
 			gitDir := gitPath
 			if !fi.IsDir() {
 				// In a worktree or submodule, .git is a file holding a
@@ -40,7 +41,7 @@ func detectGitInfo(cwd string) (string, string) {
 // worktrees and submodules) and returns the real git directory as an absolute
 // path, or "" if it can't be read.
 func gitDirFromFile(gitFile, baseDir string) string {
-	data, err := os.ReadFile(gitFile)
+	data, err := procfs.ReadFile(gitFile)
 	if err != nil {
 		return ""
 	}
@@ -64,7 +65,7 @@ func gitDirFromFile(gitFile, baseDir string) string {
 // gitBranchFromHEAD reads <gitDir>/HEAD and returns the checked-out branch name,
 // or "" when HEAD is detached or unreadable.
 func gitBranchFromHEAD(gitDir string) string {
-	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	head, err := procfs.ReadFile(filepath.Join(gitDir, "HEAD"))
 	if err != nil {
 		return ""
 	}
@@ -0,0 +1,22 @@
+//go:build linux
+
+package proc
+
+import (
+	"strings"
+
+	"github.com/pranshuparmar/witr/internal/procfs"
+)
+
+// HostIdentity returns the hostname and kernel release of the system being
+// analyzed, which under --proc-root or --snapshot is not the one witr runs on.
+func HostIdentity() (hostname, kernel string) {
+	read := func(name string) string {
+		data, err := procfs.ReadFile(name)
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(data))
+	}
+	return read("/proc/sys/kernel/hostname"), read("/proc/sys/kernel/osrelease")
+}
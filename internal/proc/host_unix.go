@@ -0,0 +1,16 @@
+//go:build darwin || freebsd
+
+package proc
+
+import (
+	"os"
+	"syscall"
+)
+
+// HostIdentity returns the hostname and kernel release of the system being
+// analyzed.
+func HostIdentity() (hostname, kernel string) {
+	hostname, _ = os.Hostname()
+	kernel, _ = syscall.Sysctl("kern.osrelease")
+	return hostname, kernel
+}
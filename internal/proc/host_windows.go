@@ -0,0 +1,18 @@
+//go:build windows
+
+package proc
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// HostIdentity returns the hostname and Windows version ("10.0.22631") of the
+// system being analyzed.
+func HostIdentity() (hostname, kernel string) {
+	hostname, _ = os.Hostname()
+	v := windows.RtlGetVersion()
+	return hostname, fmt.Sprintf("%d.%d.%d", v.MajorVersion, v.MinorVersion, v.BuildNumber)
+}
@@ -0,0 +1,235 @@
+package proc
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Index is the process table of one witr invocation. The table (PID, PPID
+// and command of every process) is read in one pass the first time it is
+// needed; the full ReadProcess view and the command line of a PID are read
+// the first time that PID is asked about. Until the table is read, a chain
+// of parents is walked one stat file at a time where the platform allows. Every target of a run, and every
+// step of each analysis, shares one Index, so a process is read at most once
+// however many lookups touch it.
+//
+// An Index is never refreshed: --watch uses a new one per refresh.
+type Index struct {
+	mu sync.Mutex
+
+	scanned  bool
+	scanErr  error
+	table    []model.Process
+	byPID    map[int]int
+	children map[int][]int
+	nsRead   map[int]bool // table entries whose NamespacePIDs were read
+
+	stats    map[int]model.Process // entries read one by one before the scan
+	procs    map[int]model.Process
+	procErrs map[int]error
+	cmdlines map[int]string
+}
+
+// NewIndex returns an empty Index. Nothing is read until it is queried.
+func NewIndex() *Index {
+	return &Index{
+		stats:    map[int]model.Process{},
+		procs:    map[int]model.Process{},
+		procErrs: map[int]error{},
+		cmdlines: map[int]string{},
+	}
+}
+
+type indexKey struct{}
+
+// WithIndex returns a copy of ctx that carries idx, for the lookups made
+// with it to share.
+func WithIndex(ctx context.Context, idx *Index) context.Context {
+	return context.WithValue(ctx, indexKey{}, idx)
+}
+
+// IndexFrom returns the Index ctx carries, or a new one that only the caller
+// will use.
+func IndexFrom(ctx context.Context) *Index {
+	if idx, ok := ctx.Value(indexKey{}).(*Index); ok {
+		return idx
+	}
+	return NewIndex()
+}
+
+// scan reads the process table once. The caller holds x.mu.
+func (x *Index) scan() error {
+	if x.scanned {
+		return x.scanErr
+	}
+	x.scanned = true
+	table, err := ListProcessSnapshot()
+	if err != nil {
+		x.scanErr = err
+		return err
+	}
+	sortProcesses(table)
+	x.table = table
+	x.byPID = make(map[int]int, len(table))
+	x.children = make(map[int][]int)
+	x.nsRead = make(map[int]bool)
+	for i, p := range table {
+		x.byPID[p.PID] = i
+		if p.PPID != p.PID {
+			x.children[p.PPID] = append(x.children[p.PPID], i)
+		}
+	}
+	return nil
+}
+
+// Processes returns the process table, sorted by PID: the lightweight
+// ListProcessSnapshot view, not ReadProcess's.
+func (x *Index) Processes() ([]model.Process, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if err := x.scan(); err != nil {
+		return nil, err
+	}
+	return append([]model.Process(nil), x.table...), nil
+}
+
+// Children returns the direct children of pid from the process table,
+// sorted by PID, with their PIDs in nested PID namespaces, read the first
+// time each is asked for.
+func (x *Index) Children(pid int) ([]model.Process, error) {
+	if pid <= 0 {
+		return nil, fmt.Errorf("invalid pid")
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if err := x.scan(); err != nil {
+		return nil, err
+	}
+	children := make([]model.Process, 0, len(x.children[pid]))
+	for _, i := range x.children[pid] {
+		if !x.nsRead[i] {
+			fillNamespacePIDs(&x.table[i])
+			x.nsRead[i] = true
+		}
+		children = append(children, x.table[i])
+	}
+	return children, nil
+}
+
+// entry returns the process table entry of pid: from the table once it is
+// read, or else from pid's own stat file, so walking one chain does not read
+// the whole table. The caller holds x.mu.
+func (x *Index) entry(pid int) (model.Process, bool) {
+	if !x.scanned {
+		if p, ok := x.stats[pid]; ok {
+			return p, true
+		}
+		if p, ok, err := readStatSnapshot(pid); ok {
+			if err != nil {
+				return model.Process{}, false
+			}
+			x.stats[pid] = p
+			return p, true
+		}
+	}
+	if x.scan() != nil {
+		return model.Process{}, false
+	}
+	i, ok := x.byPID[pid]
+	if !ok {
+		return model.Process{}, false
+	}
+	return x.table[i], true
+}
+
+// Lineage returns the chain of processes from the oldest ancestor down to
+// pid as the process table has them: PID, PPID and command only, without
+// the full ReadProcess read of any of them.
+func (x *Index) Lineage(pid int) ([]model.Process, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	var chain []model.Process
+	seen := make(map[int]bool)
+	for current := pid; current > 0 && !seen[current]; {
+		seen[current] = true
+		p, ok := x.entry(current)
+		if !ok {
+			break
+		}
+		chain = append(chain, p)
+		if p.PPID == 0 || p.PID == 1 {
+			break
+		}
+		current = p.PPID
+	}
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("no process ancestry found")
+	}
+	slices.Reverse(chain)
+	return chain, nil
+}
+
+// Process returns ReadProcess(pid), reading it only the first time.
+func (x *Index) Process(pid int) (model.Process, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if p, ok := x.procs[pid]; ok {
+		return p, nil
+	}
+	if err, ok := x.procErrs[pid]; ok {
+		return model.Process{}, err
+	}
+	p, err := ReadProcess(pid)
+	if err != nil {
+		x.procErrs[pid] = err
+		return model.Process{}, err
+	}
+	x.procs[pid] = p
+	return p, nil
+}
+
+// Cmdline returns the command line of pid, or "" when it cannot be read. It
+// is read only the first time.
+func (x *Index) Cmdline(pid int) string {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if c, ok := x.cmdlines[pid]; ok {
+		return c
+	}
+	c := GetCmdline(pid)
+	if c == "(unknown)" {
+		c = ""
+	}
+	x.cmdlines[pid] = c
+	return c
+}
+
+// Ancestry returns the chain of processes from the oldest ancestor down to
+// pid, each as ReadProcess reads it, for an analysis to render. The chain
+// is walked with Lineage, and only its processes are read in full. Callers
+// that need only the PIDs or commands of the chain use Lineage.
+func (x *Index) Ancestry(pid int) ([]model.Process, error) {
+	lineage, err := x.Lineage(pid)
+	if err != nil {
+		return nil, err
+	}
+	// Read from pid up, so a process that exited cuts off the chain above
+	// it and not pid itself.
+	chain := make([]model.Process, 0, len(lineage))
+	for i := len(lineage) - 1; i >= 0; i-- {
+		p, err := x.Process(lineage[i].PID)
+		if err != nil {
+			break
+		}
+		chain = append(chain, p)
+	}
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("no process ancestry found")
+	}
+	slices.Reverse(chain)
+	return chain, nil
+}
@@ -0,0 +1,135 @@
+//go:build linux
+
+package proc
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/pranshuparmar/witr/internal/procfs"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestIndex(t *testing.T) {
+	tree := fstest.MapFS{
+		"proc/1/stat":      {Data: []byte("1 (systemd) S 0 1 1 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 10 0 0\n")},
+		"proc/1/cmdline":   {Data: []byte("/sbin/init\x00")},
+		"proc/310/stat":    {Data: []byte("310 (postgres) S 1 310 310 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 500 0 0\n")},
+		"proc/310/cmdline": {Data: []byte("/usr/lib/postgresql/16/bin/postgres\x00-D\x00/var/lib/postgresql\x00")},
+		"proc/312/stat":    {Data: []byte("312 (postgres) S 310 312 312 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 510 0 0\n")},
+		"proc/311/stat":    {Data: []byte("311 (postgres) S 310 311 311 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 505 0 0\n")},
+		"proc/311/cmdline": {Data: []byte("postgres: checkpointer\x00")},
+	}
+	prev, prevLive := procfs.Current(), procfs.Live()
+	procfs.Set(procfs.FromFS(tree), false)
+	t.Cleanup(func() { procfs.Set(prev, prevLive) })
+
+	idx := NewIndex()
+	procs, err := idx.Processes()
+	if err != nil {
+		t.Fatalf("Processes: %v", err)
+	}
+	if got := pids(procs); len(got) != 4 || got[0] != 1 || got[1] != 310 || got[2] != 311 || got[3] != 312 {
+		t.Errorf("Processes = %v, want 1, 310, 311, 312", got)
+	}
+	kids, err := idx.Children(310)
+	if err != nil {
+		t.Fatalf("Children(310): %v", err)
+	}
+	if got := pids(kids); len(got) != 2 || got[0] != 311 || got[1] != 312 {
+		t.Errorf("Children(310) = %v, want 311, 312", got)
+	}
+
+	chain, err := idx.Ancestry(311)
+	if err != nil {
+		t.Fatalf("Ancestry(311): %v", err)
+	}
+	if got := pids(chain); len(got) != 3 || got[0] != 1 || got[2] != 311 {
+		t.Errorf("Ancestry(311) = %v, want 1, 310, 311", got)
+	}
+	lineage, err := idx.Lineage(312)
+	if err != nil {
+		t.Fatalf("Lineage(312): %v", err)
+	}
+	if got := pids(lineage); len(got) != 3 || got[0] != 1 || got[1] != 310 || got[2] != 312 || lineage[1].Command != "postgres" {
+		t.Errorf("Lineage(312) = %+v, want 1, 310 (postgres), 312", lineage)
+	}
+	if _, err := idx.Lineage(999); err == nil {
+		t.Error("Lineage of a PID not in the table should fail")
+	}
+	if cmd := idx.Cmdline(311); cmd != "postgres: checkpointer" {
+		t.Errorf("Cmdline(311) = %q", cmd)
+	}
+	if cmd := idx.Cmdline(312); cmd != "" {
+		t.Errorf("Cmdline(312) = %q, want empty for a missing cmdline", cmd)
+	}
+
+	// Everything read is kept: the process table, the processes and the
+	// command lines are not read again.
+	for name := range tree {
+		delete(tree, name)
+	}
+	if procs, err := idx.Processes(); err != nil || len(procs) != 4 {
+		t.Errorf("Processes after /proc emptied = %d, %v; want the 4 scanned", len(procs), err)
+	}
+	if p, err := idx.Process(310); err != nil || p.PID != 310 {
+		t.Errorf("Process(310) after /proc emptied = %+v, %v; want the cached read", p, err)
+	}
+	if chain, err := idx.Ancestry(311); err != nil || len(chain) != 3 {
+		t.Errorf("Ancestry(311) after /proc emptied = %d, %v; want the cached chain", len(chain), err)
+	}
+	if cmd := idx.Cmdline(311); cmd != "postgres: checkpointer" {
+		t.Errorf("Cmdline(311) after /proc emptied = %q, want the cached command line", cmd)
+	}
+
+	// A fresh index sees the empty /proc.
+	if _, err := NewIndex().Ancestry(311); err == nil {
+		t.Error("a new Index should read /proc again")
+	}
+}
+
+func TestIndexWalksChainWithoutScan(t *testing.T) {
+	tree := fstest.MapFS{
+		"proc/1/stat":       {Data: []byte("1 (systemd) S 0 1 1 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 10 0 0\n")},
+		"proc/310/stat":     {Data: []byte("310 (sshd) S 1 310 310 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 500 0 0\n")},
+		"proc/4211/stat":    {Data: []byte("4211 (bash) S 310 4211 4211 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 900 0 0\n")},
+		"proc/9000/stat":    {Data: []byte("9000 (unrelated) S 1 9000 9000 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 990 0 0\n")},
+		"proc/4211/environ": {Data: []byte("HOME=/home/alice\x00")},
+	}
+	prev, prevLive := procfs.Current(), procfs.Live()
+	procfs.Set(procfs.FromFS(tree), false)
+	t.Cleanup(func() { procfs.Set(prev, prevLive) })
+
+	idx := NewIndex()
+	lineage, err := idx.Lineage(4211)
+	if err != nil {
+		t.Fatalf("Lineage(4211): %v", err)
+	}
+	if got := pids(lineage); len(got) != 3 || got[0] != 1 || got[1] != 310 || got[2] != 4211 {
+		t.Errorf("Lineage(4211) = %v, want 1, 310, 4211", got)
+	}
+	if lineage[2].Env != nil {
+		t.Errorf("Lineage read the environment of 4211: %v", lineage[2].Env)
+	}
+	chain, err := idx.Ancestry(4211)
+	if err != nil {
+		t.Fatalf("Ancestry(4211): %v", err)
+	}
+	if got := pids(chain); len(got) != 3 || got[2] != 4211 || len(chain[2].Env) != 1 {
+		t.Errorf("Ancestry(4211) = %+v, want 1, 310, 4211 read in full", chain)
+	}
+	if idx.scanned {
+		t.Error("walking one chain should not read the whole process table")
+	}
+	if _, ok := idx.procs[9000]; ok {
+		t.Error("a process outside the chain was read in full")
+	}
+}
+
+func pids(procs []model.Process) []int {
+	out := make([]int, 0, len(procs))
+	for _, p := range procs {
+		out = append(out, p.PID)
+	}
+	return out
+}
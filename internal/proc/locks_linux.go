@@ -7,8 +7,8 @@ import (
 	"os"
 	"strconv"
 	"strings"
-	"syscall"
 
+	"github.com/pranshuparmar/witr/internal/procfs"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
@@ -18,11 +18,11 @@ import (
 // Inode collisions across filesystems are theoretically possible but
 // vanishingly rare in practice.
 func statKey(info os.FileInfo) string {
-	st, ok := info.Sys().(*syscall.Stat_t)
+	id, ok := procfs.StatID(info)
 	if !ok {
 		return ""
 	}
-	return fmt.Sprintf("%d", st.Ino)
+	return fmt.Sprintf("%d", id.Ino)
 }
 
 // ListLockedFiles returns every file lock currently held on the system,
@@ -30,7 +30,7 @@ func statKey(info os.FileInfo) string {
 // owning process's /proc/<pid>/fd/* — incomplete coverage is acceptable
 // (anonymous fds, vanished processes, etc. just get the device:inode literal).
 func ListLockedFiles() []*model.LockedFile {
-	data, err := os.ReadFile("/proc/locks")
+	data, err := procfs.ReadFile("/proc/locks")
 	if err != nil {
 		return nil
 	}
@@ -88,16 +88,19 @@ func resolveLockPath(pid int, devInode string, cache map[int]map[string]string)
 	cache[pid] = m
 
 	fdDir := fmt.Sprintf("/proc/%d/fd", pid)
-	entries, err := os.ReadDir(fdDir)
+	entries, err := procfs.ReadDir(fdDir)
 	if err != nil {
 		return ""
 	}
 	for _, entry := range entries {
-		target, err := os.Readlink(fdDir + "/" + entry.Name())
+		fdPath := fdDir + "/" + entry.Name()
+		target, err := procfs.ReadLink(fdPath)
 		if err != nil {
 			continue
 		}
-		info, err := os.Stat(target)
+		// Stat through the fd link rather than the target path: it reaches
+		// unlinked files too, and works when /proc is not the live host's.
+		info, err := procfs.Stat(fdPath)
 		if err != nil {
 			continue
 		}
@@ -112,7 +115,7 @@ func lockProcessName(pid int, cache map[int]string) string {
 	if name, ok := cache[pid]; ok {
 		return name
 	}
-	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/comm", pid))
+	data, err := procfs.ReadFile(fmt.Sprintf("/proc/%d/comm", pid))
 	if err != nil {
 		cache[pid] = ""
 		return ""
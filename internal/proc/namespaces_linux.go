@@ -0,0 +1,112 @@
+//go:build linux
+
+package proc
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/internal/procfs"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// namespaceKinds are the entries of /proc/<pid>/ns witr records. Kernels
+// without time namespaces simply have no "time" link.
+var namespaceKinds = []string{"cgroup", "ipc", "mnt", "net", "pid", "time", "user", "uts"}
+
+// readNamespaces returns the inode of each of pid's namespaces, by kind, or
+// nil when none can be read (another user's process, without privileges).
+func readNamespaces(pid int) map[string]string {
+	dir := "/proc/" + strconv.Itoa(pid) + "/ns/"
+	var ns map[string]string
+	for _, kind := range namespaceKinds {
+		ino := readNamespace(dir+kind, kind)
+		if ino == "" {
+			continue
+		}
+		if ns == nil {
+			ns = make(map[string]string, len(namespaceKinds))
+		}
+		ns[kind] = ino
+	}
+	return ns
+}
+
+// readNamespacePIDs returns pid's PID in each PID namespace it is in, from
+// the one /proc belongs to down to the process's own: the NStgid line of its
+// status, or NSpid, which is the same for a thread group leader. A process in
+// the same PID namespace as /proc has a single entry; kernels before 4.1 have
+// neither line.
+func readNamespacePIDs(pid int) []int {
+	data, err := procfs.ReadFile("/proc/" + strconv.Itoa(pid) + "/status")
+	if err != nil {
+		return nil
+	}
+	var nspid, nstgid []int
+	for line := range strings.Lines(string(data)) {
+		key, rest, ok := strings.Cut(line, ":")
+		if !ok || (key != "NStgid" && key != "NSpid") {
+			continue
+		}
+		var ids []int
+		for _, f := range strings.Fields(rest) {
+			id, err := strconv.Atoi(f)
+			if err != nil {
+				ids = nil
+				break
+			}
+			ids = append(ids, id)
+		}
+		if key == "NStgid" {
+			nstgid = ids
+		} else {
+			nspid = ids
+		}
+	}
+	if nstgid != nil {
+		return nstgid
+	}
+	return nspid
+}
+
+// fillNamespacePIDs sets p's NamespacePIDs and, when it is in a nested PID
+// namespace, the ContainerID its cgroup names, for a process from the
+// lightweight table that ReadProcess did not read. The runtime is not asked
+// for the container's name.
+func fillNamespacePIDs(p *model.Process) {
+	p.NamespacePIDs = readNamespacePIDs(p.PID)
+	if len(p.NamespacePIDs) < 2 {
+		return
+	}
+	data, err := procfs.ReadFile("/proc/" + strconv.Itoa(p.PID) + "/cgroup")
+	if err != nil {
+		return
+	}
+	cgroup := string(data)
+	switch {
+	case strings.Contains(cgroup, "docker"):
+		p.ContainerID = extractContainerID(cgroup, "docker-", "docker/")
+	case strings.Contains(cgroup, "podman"), strings.Contains(cgroup, "libpod"):
+		p.ContainerID = extractContainerID(cgroup, "libpod-", "libpod/")
+	default:
+		p.ContainerID = findLongHexID(cgroup)
+	}
+}
+
+// readNamespace reads a namespace link, "pid:[4026531836]", down to its
+// inode, or "" when it cannot be read or is not a kind namespace.
+func readNamespace(link, kind string) string {
+	target, err := procfs.ReadLink(link)
+	if err != nil {
+		return ""
+	}
+	rest, ok := strings.CutPrefix(target, kind+":[")
+	if !ok {
+		return ""
+	}
+	ino, ok := strings.CutSuffix(rest, "]")
+	if !ok {
+		return ""
+	}
+	return ino
+}
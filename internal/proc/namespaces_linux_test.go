@@ -0,0 +1,48 @@
+//go:build linux
+
+package proc
+
+import (
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pranshuparmar/witr/internal/procfs"
+)
+
+func TestReadNamespacesAndPIDs(t *testing.T) {
+	ns := func(target string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(target), Mode: fs.ModeSymlink}
+	}
+	tree := fstest.MapFS{
+		// redis as PID 1 of its container.
+		"proc/48211/ns/mnt":   ns("mnt:[4026532301]"),
+		"proc/48211/ns/pid":   ns("pid:[4026532304]"),
+		"proc/48211/ns/net":   ns("net:[4026532307]"),
+		"proc/48211/ns/uts":   ns("garbage"),
+		"proc/48211/status":   {Data: []byte("Name:\tredis-server\nTgid:\t48211\nNStgid:\t48211\t1\nPid:\t48211\nNSpid:\t48211\t1\n")},
+		"proc/800/status":     {Data: []byte("Name:\tsshd\nNSpid:\t800\n")},
+		"proc/900/status":     {Data: []byte("Name:\told\nPid:\t900\n")},
+		"proc/901/status":     {Data: []byte("Name:\tbad\nNStgid:\t901\tx\n")},
+		"proc/self/ns/pid":    ns("pid:[4026531836]"),
+		"proc/self/ns/cgroup": ns("cgroup:[4026531835]"),
+	}
+	prev, prevLive := procfs.Current(), procfs.Live()
+	procfs.Set(procfs.FromFS(tree), false)
+	t.Cleanup(func() { procfs.Set(prev, prevLive) })
+
+	want := map[string]string{"mnt": "4026532301", "pid": "4026532304", "net": "4026532307"}
+	if got := readNamespaces(48211); !reflect.DeepEqual(got, want) {
+		t.Errorf("readNamespaces = %v, want %v", got, want)
+	}
+	if got := readNamespaces(800); got != nil {
+		t.Errorf("readNamespaces without ns links = %v, want nil", got)
+	}
+
+	for pid, want := range map[int][]int{48211: {48211, 1}, 800: {800}, 900: nil, 901: nil} {
+		if got := readNamespacePIDs(pid); !reflect.DeepEqual(got, want) {
+			t.Errorf("readNamespacePIDs(%d) = %v, want %v", pid, got, want)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package proc
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+// fillNamespacePIDs does nothing: PID namespaces are Linux only.
+func fillNamespacePIDs(p *model.Process) {}
@@ -4,15 +4,16 @@ package proc
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/hex"
 	"fmt"
 	"net"
-	"os"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/pranshuparmar/witr/internal/procfs"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
@@ -21,6 +22,7 @@ import (
 var (
 	socketCache     map[string]model.Socket
 	socketCacheTime time.Time
+	socketCacheGen  uint64
 	socketCacheMu   sync.Mutex
 	socketCacheTTL  = 2 * time.Second
 )
@@ -29,7 +31,8 @@ func readSocketsCached() (map[string]model.Socket, error) {
 	socketCacheMu.Lock()
 	defer socketCacheMu.Unlock()
 
-	if socketCache != nil && time.Since(socketCacheTime) < socketCacheTTL {
+	gen := procfs.Generation()
+	if socketCache != nil && socketCacheGen == gen && time.Since(socketCacheTime) < socketCacheTTL {
 		return socketCache, nil
 	}
 
@@ -39,6 +42,7 @@ func readSocketsCached() (map[string]model.Socket, error) {
 	}
 	socketCache = sockets
 	socketCacheTime = time.Now()
+	socketCacheGen = gen
 	return sockets, nil
 }
 
@@ -59,14 +63,13 @@ var stateMap = map[string]string{
 func readSockets() (map[string]model.Socket, error) {
 	sockets := make(map[string]model.Socket)
 
-	parse := func(path, proto string, ipv6 bool) {
-		f, err := os.Open(path)
+	parse := func(path, proto string, ipv6 bool, netns string) {
+		data, err := procfs.ReadFile(path)
 		if err != nil {
 			return
 		}
-		defer f.Close()
 
-		scanner := bufio.NewScanner(f)
+		scanner := bufio.NewScanner(bytes.NewReader(data))
 		scanner.Scan() // skip header
 
 		for scanner.Scan() {
@@ -85,24 +88,125 @@ func readSockets() (map[string]model.Socket, error) {
 			}
 
 			addr, port := parseAddr(local, ipv6)
-			sockets[inode] = model.Socket{
+			s := model.Socket{
 				Inode:    inode,
 				Port:     port,
 				Address:  addr,
 				State:    state,
 				Protocol: proto,
+				Netns:    netns,
 			}
+			// A listener or unconnected socket has a zero remote end.
+			if raddr, rport := parseAddr(fields[2], ipv6); rport > 0 {
+				s.RemoteAddress, s.RemotePort = raddr, rport
+			}
+			if tx, rx, ok := strings.Cut(fields[4], ":"); ok {
+				s.TxQueue, _ = strconv.ParseUint(tx, 16, 64)
+				s.RxQueue, _ = strconv.ParseUint(rx, 16, 64)
+			}
+			if uid, err := strconv.Atoi(fields[7]); err == nil {
+				s.UID = &uid
+			}
+			sockets[inode] = s
 		}
 	}
 
-	parse("/proc/net/tcp", "TCP", false)
-	parse("/proc/net/tcp6", "TCP6", true)
-	parse("/proc/net/udp", "UDP", false)
-	parse("/proc/net/udp6", "UDP6", true)
+	// Socket inodes are unique across namespaces, so every namespace's
+	// tables share one map.
+	for _, t := range NetTables() {
+		parse(t.Dir+"/tcp", "TCP", false, t.Netns)
+		parse(t.Dir+"/tcp6", "TCP6", true, t.Netns)
+		parse(t.Dir+"/udp", "UDP", false, t.Netns)
+		parse(t.Dir+"/udp6", "UDP6", true, t.Netns)
+	}
+
+	unixSockets, _ := UnixSockets()
+	for _, s := range unixSockets {
+		sockets[s.Inode] = s
+	}
 
 	return sockets, nil
 }
 
+// Sockets returns the TCP, UDP and unix sockets of every network namespace,
+// keyed by inode.
+// The map is shared with other callers and must not be modified.
+func Sockets() (map[string]model.Socket, error) {
+	return readSocketsCached()
+}
+
+// unixTypes names the socket types of /proc/net/unix.
+var unixTypes = map[string]string{
+	"0001": "stream",
+	"0002": "dgram",
+	"0005": "seqpacket",
+}
+
+// UnixSockets returns the unix sockets of every network namespace that are
+// bound to a path: listeners, bound datagram sockets, and the connections a
+// listener accepted, which carry its path. A client's end of a connection
+// has no path and is left out. It fails only when witr's own namespace's
+// /proc/net/unix cannot be read.
+func UnixSockets() ([]model.Socket, error) {
+	var sockets []model.Socket
+	for i, t := range NetTables() {
+		data, err := procfs.ReadFile(t.Dir + "/unix")
+		if err != nil {
+			if i == 0 {
+				return nil, err
+			}
+			continue
+		}
+		parsed := parseUnixSockets(data)
+		for k := range parsed {
+			parsed[k].Netns = t.Netns
+		}
+		sockets = append(sockets, parsed...)
+	}
+	return sockets, nil
+}
+
+func parseUnixSockets(data []byte) []model.Socket {
+	var sockets []model.Socket
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Scan() // skip header
+	for scanner.Scan() {
+		// Num RefCount Protocol Flags Type St Inode Path
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 {
+			continue
+		}
+		flags, err := strconv.ParseUint(fields[3], 16, 32)
+		if err != nil {
+			continue
+		}
+		typ, ok := unixTypes[fields[4]]
+		if !ok {
+			typ = "unknown"
+		}
+		var state string
+		switch {
+		case flags&unixAcceptCon != 0:
+			state = "LISTEN"
+		case fields[5] == "03":
+			state = "ESTABLISHED"
+		default:
+			state = "UNCONNECTED"
+		}
+		sockets = append(sockets, model.Socket{
+			Inode:    fields[6],
+			State:    state,
+			Protocol: "UNIX",
+			Path:     strings.Join(fields[7:], " "),
+			Type:     typ,
+		})
+	}
+	return sockets
+}
+
+// unixAcceptCon is __SO_ACCEPTCON, the flag of a listening unix socket.
+const unixAcceptCon = 0x10000
+
 func parseAddr(raw string, ipv6 bool) (string, int) {
 	parts := strings.Split(raw, ":")
 	if len(parts) < 2 {
@@ -153,7 +257,7 @@ func ListOpenPorts() ([]model.OpenPort, error) {
 	var openPorts []model.OpenPort
 
 	// Scan proc
-	procs, err := os.ReadDir("/proc")
+	procs, err := procfs.ReadDir("/proc")
 	if err != nil {
 		return nil, err
 	}
@@ -169,25 +273,26 @@ func ListOpenPorts() ([]model.OpenPort, error) {
 
 		// Scan fds
 		fdPath := fmt.Sprintf("/proc/%d/fd", pid)
-		fds, err := os.ReadDir(fdPath)
+		fds, err := procfs.ReadDir(fdPath)
 		if err != nil {
 			continue
 		}
 
 		for _, fd := range fds {
-			link, err := os.Readlink(fmt.Sprintf("%s/%s", fdPath, fd.Name()))
+			link, err := procfs.ReadLink(fmt.Sprintf("%s/%s", fdPath, fd.Name()))
 			if err != nil {
 				continue
 			}
 			if strings.HasPrefix(link, "socket:[") {
 				inode := strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")
-				if s, ok := sockets[inode]; ok {
+				if s, ok := sockets[inode]; ok && s.Path == "" {
 					openPorts = append(openPorts, model.OpenPort{
 						PID:      pid,
 						Port:     s.Port,
 						Address:  s.Address,
 						Protocol: s.Protocol,
 						State:    s.State,
+						Netns:    s.Netns,
 					})
 				}
 			}
@@ -3,10 +3,16 @@
 package proc
 
 import (
+	"encoding/binary"
 	"encoding/hex"
 	"fmt"
 	"net"
+	"reflect"
 	"testing"
+	"testing/fstest"
+
+	"github.com/pranshuparmar/witr/internal/procfs"
+	"github.com/pranshuparmar/witr/pkg/model"
 )
 
 func encodeProcNetTCP6(ip net.IP, port int) string {
@@ -137,3 +143,60 @@ func TestParseAddr(t *testing.T) {
 
 	}
 }
+
+func TestParseUnixSockets(t *testing.T) {
+	data := []byte("Num       RefCount Protocol Flags    Type St Inode Path\n" +
+		"0000000000000000: 00000002 00000000 00010000 0001 01 21001 /run/docker.sock\n" +
+		"0000000000000000: 00000003 00000000 00000000 0001 03 21002 /run/docker.sock\n" +
+		"0000000000000000: 00000003 00000000 00000000 0001 03 21003\n" + // a client: no path
+		"0000000000000000: 00000002 00000000 00000000 0002 01 21004 /run/systemd/notify\n" +
+		"0000000000000000: 00000002 00000000 00010000 0005 01 21005 @/tmp/.X11-unix/X0\n")
+	got := parseUnixSockets(data)
+	want := []model.Socket{
+		{Inode: "21001", State: "LISTEN", Protocol: "UNIX", Path: "/run/docker.sock", Type: "stream"},
+		{Inode: "21002", State: "ESTABLISHED", Protocol: "UNIX", Path: "/run/docker.sock", Type: "stream"},
+		{Inode: "21004", State: "UNCONNECTED", Protocol: "UNIX", Path: "/run/systemd/notify", Type: "dgram"},
+		{Inode: "21005", State: "LISTEN", Protocol: "UNIX", Path: "@/tmp/.X11-unix/X0", Type: "seqpacket"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseUnixSockets:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestParseUnixDiagMsg(t *testing.T) {
+	msg := make([]byte, 16+8)
+	msg[0] = 1 // AF_UNIX
+	binary.NativeEndian.PutUint32(msg[4:], 21002)
+	binary.NativeEndian.PutUint16(msg[16:], 8) // rta_len
+	binary.NativeEndian.PutUint16(msg[18:], unixDiagPeer)
+	binary.NativeEndian.PutUint32(msg[20:], 31007)
+	if ino, peer, ok := parseUnixDiagMsg(msg); !ok || ino != 21002 || peer != 31007 {
+		t.Errorf("parseUnixDiagMsg = %d, %d, %v; want 21002, 31007, true", ino, peer, ok)
+	}
+	if _, _, ok := parseUnixDiagMsg(msg[:16]); ok {
+		t.Error("a socket without a peer attribute has no peer")
+	}
+}
+
+func TestReadSocketsPeerQueuesAndUID(t *testing.T) {
+	const header = "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+	prev, prevLive := procfs.Current(), procfs.Live()
+	procfs.Set(procfs.FromFS(fstest.MapFS{
+		"proc/net/tcp": {Data: []byte(header +
+			"   0: 00000000:1538 00000000:0000 0A 00000000:00000003 00:00000000 00000000   113        0 7001 1 0 100 0 0 10 0\n" +
+			"   1: 0500000A:C958 0403020A:1538 01 00000010:00000000 00:00000000 00000000  1000        0 7002 1 0 20 4 30 10 -1\n")},
+	}), false)
+	t.Cleanup(func() { procfs.Set(prev, prevLive) })
+
+	sockets, err := readSockets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, c := sockets["7001"], sockets["7002"]
+	if l.RemotePort != 0 || l.RemoteAddress != "" || l.RxQueue != 3 || l.UID == nil || *l.UID != 113 {
+		t.Errorf("listener = %+v, want no remote end, 3 waiting and UID 113", l)
+	}
+	if c.RemoteAddress != "10.2.3.4" || c.RemotePort != 5432 || c.TxQueue != 16 || c.UID == nil || *c.UID != 1000 {
+		t.Errorf("connection = %+v, want 10.2.3.4:5432, 16 bytes to send and UID 1000", c)
+	}
+}
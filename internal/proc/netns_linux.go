@@ -0,0 +1,91 @@
+//go:build linux
+
+package proc
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pranshuparmar/witr/internal/procfs"
+)
+
+// NetTable is where one network namespace's socket tables can be read: Dir
+// holds its tcp, tcp6, udp, udp6 and unix files.
+type NetTable struct {
+	Dir string
+	// Netns is the inode of the namespace, empty for witr's own, whose
+	// tables are /proc/net.
+	Netns string
+}
+
+var (
+	netTablesCache []NetTable
+	netTablesTime  time.Time
+	netTablesGen   uint64
+	netTablesMu    sync.Mutex
+)
+
+// NetTables returns the socket tables of every network namespace with a
+// process in it: /proc/net first, then, for each other namespace, the
+// /proc/<pid>/net of the lowest PID in it whose tables can be read.
+// Namespaces whose processes witr may not inspect are left out.
+func NetTables() []NetTable {
+	netTablesMu.Lock()
+	defer netTablesMu.Unlock()
+
+	gen := procfs.Generation()
+	if netTablesCache != nil && netTablesGen == gen && time.Since(netTablesTime) < socketCacheTTL {
+		return netTablesCache
+	}
+
+	own := OwnNetns()
+	members := make(map[string][]int)
+	entries, _ := procfs.ReadDir("/proc")
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		if ns := NetnsOf(pid); ns != "" && ns != own {
+			members[ns] = append(members[ns], pid)
+		}
+	}
+
+	namespaces := make([]string, 0, len(members))
+	for ns := range members {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	tables := []NetTable{{Dir: "/proc/net"}}
+	for _, ns := range namespaces {
+		pids := members[ns]
+		sort.Ints(pids)
+		for _, pid := range pids {
+			dir := "/proc/" + strconv.Itoa(pid) + "/net"
+			if _, err := procfs.Stat(dir + "/tcp"); err == nil {
+				tables = append(tables, NetTable{Dir: dir, Netns: ns})
+				break
+			}
+		}
+	}
+
+	netTablesCache = tables
+	netTablesTime = time.Now()
+	netTablesGen = gen
+	return tables
+}
+
+// NetnsOf returns the inode of pid's network namespace, or "" when it cannot
+// be read.
+func NetnsOf(pid int) string {
+	return readNamespace("/proc/"+strconv.Itoa(pid)+"/ns/net", "net")
+}
+
+// OwnNetns returns the inode of the network namespace /proc/net describes:
+// witr's own, or in a snapshot, that of the process that captured it.
+func OwnNetns() string {
+	return readNamespace("/proc/self/ns/net", "net")
+}
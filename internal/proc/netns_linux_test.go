@@ -0,0 +1,66 @@
+//go:build linux
+
+package proc
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pranshuparmar/witr/internal/procfs"
+)
+
+func TestNetTablesAndNamespacedSockets(t *testing.T) {
+	const header = "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+	ns := func(inode string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte("net:[" + inode + "]"), Mode: fs.ModeSymlink}
+	}
+	tree := fstest.MapFS{
+		"proc/self/ns/net": ns("4026531840"),
+		"proc/1/stat":      {Data: []byte("1 (systemd) S 0 1 1 0 -1\n")},
+		"proc/1/ns/net":    ns("4026531840"),
+		// A container's namespace: its first process cannot be read, the
+		// second can.
+		"proc/700/stat":   {Data: []byte("700 (nginx) S 1 700 700 0 -1\n")},
+		"proc/700/ns/net": ns("4026532289"),
+		"proc/701/stat":   {Data: []byte("701 (nginx) S 700 700 700 0 -1\n")},
+		"proc/701/ns/net": ns("4026532289"),
+		"proc/701/net/tcp": {Data: []byte(header +
+			"   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 9101 1 0 100 0 0 10 0\n")},
+		"proc/701/net/unix": {Data: []byte("Num       RefCount Protocol Flags    Type St Inode Path\n" +
+			"0000000000000000: 00000002 00000000 00010000 0001 01 9102 /run/nginx.sock\n")},
+		// A process whose namespace cannot be read is left out.
+		"proc/900/stat": {Data: []byte("900 (sshd) S 1 900 900 0 -1\n")},
+		"proc/net/tcp": {Data: []byte(header +
+			"   0: 00000000:0016 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 9001 1 0 100 0 0 10 0\n")},
+		"proc/net/unix": {Data: []byte("Num       RefCount Protocol Flags    Type St Inode Path\n")},
+	}
+	prev, prevLive := procfs.Current(), procfs.Live()
+	procfs.Set(procfs.FromFS(tree), false)
+	t.Cleanup(func() { procfs.Set(prev, prevLive) })
+
+	tables := NetTables()
+	if len(tables) != 2 || tables[0] != (NetTable{Dir: "/proc/net"}) || tables[1] != (NetTable{Dir: "/proc/701/net", Netns: "4026532289"}) {
+		t.Fatalf("NetTables = %+v, want /proc/net and the container's through pid 701", tables)
+	}
+	if got := NetnsOf(700); got != "4026532289" {
+		t.Errorf("NetnsOf(700) = %q", got)
+	}
+	if got := NetnsOf(900); got != "" {
+		t.Errorf("NetnsOf(900) = %q, want empty for an unreadable namespace", got)
+	}
+
+	sockets, err := readSockets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := sockets["9001"]; s.Port != 22 || s.Netns != "" {
+		t.Errorf("own namespace socket = %+v, want port 22 without a netns", s)
+	}
+	if s := sockets["9101"]; s.Port != 8080 || s.State != "LISTEN" || s.Netns != "4026532289" {
+		t.Errorf("container socket = %+v, want a listener on 8080 in netns 4026532289", s)
+	}
+	if s := sockets["9102"]; s.Path != "/run/nginx.sock" || s.Netns != "4026532289" {
+		t.Errorf("container unix socket = %+v, want /run/nginx.sock in netns 4026532289", s)
+	}
+}
@@ -0,0 +1,24 @@
+//go:build !linux
+
+package proc
+
+// NetTable is where one network namespace's socket tables can be read.
+type NetTable struct {
+	Dir   string
+	Netns string
+}
+
+// NetTables returns only /proc/net: network namespaces are Linux only.
+func NetTables() []NetTable {
+	return []NetTable{{Dir: "/proc/net"}}
+}
+
+// NetnsOf returns "": network namespaces are Linux only.
+func NetnsOf(pid int) string {
+	return ""
+}
+
+// OwnNetns returns "": network namespaces are Linux only.
+func OwnNetns() string {
+	return ""
+}
@@ -4,10 +4,10 @@ package proc
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 
+	"github.com/pranshuparmar/witr/internal/procfs"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
@@ -20,7 +20,7 @@ import (
 // Type is set to "OPEN" and Mode is derived from /proc/<pid>/fdinfo flags
 // when readable; missing fdinfo just leaves Mode empty.
 func ListAllOpenFiles() []*model.LockedFile {
-	procDirs, err := os.ReadDir("/proc")
+	procDirs, err := procfs.ReadDir("/proc")
 	if err != nil {
 		return nil
 	}
@@ -38,13 +38,13 @@ func ListAllOpenFiles() []*model.LockedFile {
 		}
 
 		fdDir := fmt.Sprintf("/proc/%d/fd", pid)
-		entries, err := os.ReadDir(fdDir)
+		entries, err := procfs.ReadDir(fdDir)
 		if err != nil {
 			continue // permission denied or process gone
 		}
 
 		for _, fd := range entries {
-			target, err := os.Readlink(fdDir + "/" + fd.Name())
+			target, err := procfs.ReadLink(fdDir + "/" + fd.Name())
 			if err != nil {
 				continue
 			}
@@ -85,7 +85,7 @@ func isInterestingFile(target string) bool {
 // fdMode reads /proc/<pid>/fdinfo/<fd> for the O_ACCMODE flag bits and
 // returns "R", "W", or "RW". Returns "" if the file isn't readable.
 func fdMode(pid int, fd string) string {
-	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/fdinfo/%s", pid, fd))
+	data, err := procfs.ReadFile(fmt.Sprintf("/proc/%d/fdinfo/%s", pid, fd))
 	if err != nil {
 		return ""
 	}
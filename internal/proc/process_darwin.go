@@ -11,6 +11,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/pranshuparmar/witr/internal/config"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
@@ -73,11 +74,11 @@ func ReadProcess(pid int) (model.Process, error) {
 		health = "stopped"
 	}
 
-	if health == "healthy" && cpuPct > 90 {
+	th := config.Current().Thresholds
+	if health == "healthy" && cpuPct > th.CPUPercent {
 		health = "high-cpu"
 	}
-	rssMB := rssKB / 1024
-	if health == "healthy" && rssMB > 1024 {
+	if health == "healthy" && rssKB*1024 > float64(th.Memory) {
 		health = "high-mem"
 	}
 
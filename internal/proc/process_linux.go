@@ -10,6 +10,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/pranshuparmar/witr/internal/config"
+	"github.com/pranshuparmar/witr/internal/procfs"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
@@ -18,21 +20,21 @@ func ReadProcess(pid int) (model.Process, error) {
 		return model.Process{}, fmt.Errorf("invalid pid %d", pid)
 	}
 	// Verify process still exists before reading
-	if _, err := os.Stat(fmt.Sprintf("/proc/%d", pid)); os.IsNotExist(err) {
+	if _, err := procfs.Stat(fmt.Sprintf("/proc/%d", pid)); os.IsNotExist(err) {
 		return model.Process{}, fmt.Errorf("process %d does not exist", pid)
 	}
 
 	// Read all proc files in a logical order to minimize TOCTOU issues
 	// Start with stat file which is most likely to fail if process disappears
 	statPath := fmt.Sprintf("/proc/%d/stat", pid)
-	stat, err := os.ReadFile(statPath)
+	stat, err := procfs.ReadFile(statPath)
 	if err != nil {
 		return model.Process{}, fmt.Errorf("process %d disappeared during read", pid)
 	}
 
 	// Read environment variables
 	env := []string{}
-	envBytes, errEnv := os.ReadFile(fmt.Sprintf("/proc/%d/environ", pid))
+	envBytes, errEnv := procfs.ReadFile(fmt.Sprintf("/proc/%d/environ", pid))
 	if errEnv == nil {
 		for _, e := range strings.Split(string(envBytes), "\x00") {
 			if e != "" {
@@ -44,7 +46,7 @@ func ReadProcess(pid int) (model.Process, error) {
 	health := "healthy"
 
 	// Working directory
-	var cwd, cwdErr = os.Readlink(fmt.Sprintf("/proc/%d/cwd", pid))
+	var cwd, cwdErr = procfs.ReadLink(fmt.Sprintf("/proc/%d/cwd", pid))
 	if cwdErr != nil {
 		cwd = "unknown"
 	} else if cwd == "" {
@@ -55,7 +57,7 @@ func ReadProcess(pid int) (model.Process, error) {
 	container := ""
 	var containerID, containerRuntime string
 	cgroupFile := fmt.Sprintf("/proc/%d/cgroup", pid)
-	if cgroupData, err := os.ReadFile(cgroupFile); err == nil {
+	if cgroupData, err := procfs.ReadFile(cgroupFile); err == nil {
 		cgroupStr := string(cgroupData)
 		switch {
 		case strings.Contains(cgroupStr, "docker"):
@@ -184,19 +186,19 @@ func ReadProcess(pid int) (model.Process, error) {
 		health = "stopped"
 	}
 
-	// Flag high CPU (>2h total) and high memory (>1GB RSS).
+	// Flag high CPU and high memory (>2h total, >1GiB RSS by default).
+	th := config.Current().Thresholds
 	utime, _ := strconv.ParseFloat(fields[11], 64)
 	stime, _ := strconv.ParseFloat(fields[12], 64)
 	rssPages, _ := strconv.ParseFloat(fields[21], 64)
 	clkTck := float64(ticksPerSecond())
 	totalCPU := (utime + stime) / clkTck
-	if health == "healthy" && totalCPU > 60*60*2 { // >2h CPU time
+	if health == "healthy" && totalCPU > time.Duration(th.CPUTime).Seconds() {
 		health = "high-cpu"
 	}
 	pageSize := float64(os.Getpagesize())
 	memBytes := rssPages * pageSize
-	memMB := memBytes / (1024 * 1024)
-	if health == "healthy" && memMB > 1024 {
+	if health == "healthy" && memBytes > float64(th.Memory) {
 		health = "high-mem"
 	}
 
@@ -250,7 +252,7 @@ func ReadProcess(pid int) (model.Process, error) {
 	}
 	// Full command line
 	cmdline := ""
-	cmdlineBytes, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	cmdlineBytes, err := procfs.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
 	if err == nil {
 		cmd := strings.ReplaceAll(string(cmdlineBytes), "\x00", " ")
 		cmdline = strings.TrimSpace(cmd)
@@ -289,6 +291,8 @@ func ReadProcess(pid int) (model.Process, error) {
 		Env:              env,
 		ExeDeleted:       isBinaryDeleted(pid),
 		Capabilities:     ReadCapabilities(pid),
+		Namespaces:       readNamespaces(pid),
+		NamespacePIDs:    readNamespacePIDs(pid),
 	}, nil
 }
 
@@ -302,7 +306,7 @@ var (
 // once rather than re-read on every ancestry hop.
 func totalMemoryBytes() uint64 {
 	totalMemOnce.Do(func() {
-		data, err := os.ReadFile("/proc/meminfo")
+		data, err := procfs.ReadFile("/proc/meminfo")
 		if err != nil {
 			return
 		}
@@ -321,7 +325,7 @@ func totalMemoryBytes() uint64 {
 }
 
 func isBinaryDeleted(pid int) bool {
-	exePath, err := os.Readlink(fmt.Sprintf("/proc/%d/exe", pid))
+	exePath, err := procfs.ReadLink(fmt.Sprintf("/proc/%d/exe", pid))
 	if err != nil {
 		return false
 	}
@@ -343,7 +347,7 @@ func processState(fields []string) string {
 // isDualStackEnabled checks if /proc/sys/net/ipv6/bindv6only is 0 (or missing),
 // which implies that IPv6 sockets can handle IPv4 traffic by default.
 func isDualStackEnabled() bool {
-	data, err := os.ReadFile("/proc/sys/net/ipv6/bindv6only")
+	data, err := procfs.ReadFile("/proc/sys/net/ipv6/bindv6only")
 	if err != nil {
 		return true
 	}
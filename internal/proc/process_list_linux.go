@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pranshuparmar/witr/internal/procfs"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
@@ -17,7 +18,7 @@ import (
 // reads /proc directly instead of forking `ps -axo`, computing the same
 // lifetime-average CPU% that ps reports — no subprocess per refresh.
 func ListProcesses() ([]model.Process, error) {
-	entries, err := os.ReadDir("/proc")
+	entries, err := procfs.ReadDir("/proc")
 	if err != nil {
 		return nil, fmt.Errorf("read /proc: %w", err)
 	}
@@ -48,7 +49,7 @@ func ListProcesses() ([]model.Process, error) {
 // mirroring the fields the old `ps -axo` invocation produced. Returns ok=false
 // when the process vanished mid-read or its stat is malformed.
 func readProcessListEntry(pid, ticks int, boot time.Time, totalMem, pageSize float64) (model.Process, bool) {
-	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	stat, err := procfs.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
 	if err != nil {
 		return model.Process{}, false
 	}
@@ -84,7 +85,7 @@ func readProcessListEntry(pid, ticks int, boot time.Time, totalMem, pageSize flo
 	}
 
 	cmdline := ""
-	if b, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid)); err == nil {
+	if b, err := procfs.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid)); err == nil {
 		cmdline = strings.TrimSpace(strings.ReplaceAll(string(b), "\x00", " "))
 	}
 	displayName := deriveDisplayCommand(comm, cmdline)
@@ -112,7 +113,7 @@ func readProcessListEntry(pid, ticks int, boot time.Time, totalMem, pageSize flo
 // for child/descendant discovery. We avoid full ReadProcess calls to keep
 // this path fast and to reduce permission-sensitive reads.
 func ListProcessSnapshot() ([]model.Process, error) {
-	entries, err := os.ReadDir("/proc")
+	entries, err := procfs.ReadDir("/proc")
 	if err != nil {
 		return nil, fmt.Errorf("read /proc: %w", err)
 	}
@@ -129,7 +130,7 @@ func ListProcessSnapshot() ([]model.Process, error) {
 		}
 
 		statPath := fmt.Sprintf("/proc/%d/stat", pid)
-		stat, err := os.ReadFile(statPath)
+		stat, err := procfs.ReadFile(statPath)
 		if err != nil {
 			continue
 		}
@@ -145,6 +146,18 @@ func ListProcessSnapshot() ([]model.Process, error) {
 	return processes, nil
 }
 
+// readStatSnapshot returns the ListProcessSnapshot entry of pid alone, from
+// its stat file. ok is false when the platform has no such per-process
+// read; it always has one here.
+func readStatSnapshot(pid int) (p model.Process, ok bool, err error) {
+	stat, err := procfs.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return model.Process{}, true, err
+	}
+	p, err = parseStatSnapshot(pid, stat)
+	return p, true, err
+}
+
 func parseStatSnapshot(pid int, stat []byte) (model.Process, error) {
 	raw := string(stat)
 	open := strings.Index(raw, "(")
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package proc
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+// readStatSnapshot is not available here: listing processes is one call to
+// ps or the toolhelp snapshot, so an Index reads the whole table instead.
+func readStatSnapshot(int) (model.Process, bool, error) {
+	return model.Process{}, false, nil
+}
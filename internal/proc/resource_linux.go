@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/godbus/dbus/v5"
+	"github.com/pranshuparmar/witr/internal/procfs"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
@@ -35,10 +36,10 @@ func GetResourceContext(pid int) *model.ResourceContext {
 func getThermalState() string {
 
 	path := "/sys/class/thermal/thermal_zone0/temp"
-	if _, err := os.Stat(path); os.IsNotExist(err) {
+	if _, err := procfs.Stat(path); os.IsNotExist(err) {
 		return ""
 	}
-	readText, err := os.ReadFile(path)
+	readText, err := procfs.ReadFile(path)
 	if err != nil {
 		return ""
 	}
@@ -97,7 +98,7 @@ func checkPreventsSleep(pid int) bool {
 // detect if process is in a stopped/suspended state
 func getAppNapped(pid int) bool {
 	statFile := fmt.Sprintf("/proc/%d/stat", pid)
-	data, err := os.ReadFile(statFile)
+	data, err := procfs.ReadFile(statFile)
 	if err != nil {
 		return false
 	}
@@ -141,6 +142,11 @@ func GetCPUPercent(pid int, usePs ...bool) (float64, error) {
 
 	shouldUsePs := len(usePs) > 0 && usePs[0]
 
+	// ps and top sample the running host, not a proc root or snapshot.
+	if !procfs.Live() {
+		return 0, fmt.Errorf("cpu sampling needs the live host")
+	}
+
 	if shouldUsePs {
 		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 		defer cancel()
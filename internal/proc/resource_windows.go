@@ -8,6 +8,7 @@ import (
 	"time"
 	"unsafe"
 
+	"github.com/pranshuparmar/witr/internal/config"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
@@ -100,12 +101,13 @@ func windowsProcMetrics(pid int) (rss uint64, cpu float64, cpuTime time.Duration
 
 // windowsHealth derives a health status from a process's resident memory and
 // total CPU time. Windows has no zombie/stopped equivalent, so it reports the
-// resource conditions, matching the Unix >2h CPU and >1GiB RSS thresholds.
+// resource conditions, with the same CPU time and RSS thresholds as Linux.
 func windowsHealth(rss uint64, cpuTime time.Duration) string {
+	th := config.Current().Thresholds
 	switch {
-	case cpuTime > 2*time.Hour:
+	case cpuTime > time.Duration(th.CPUTime):
 		return "high-cpu"
-	case rss > 1<<30: // 1 GiB
+	case rss > uint64(th.Memory):
 		return "high-mem"
 	default:
 		return "healthy"
@@ -17,8 +17,8 @@ type crictlRuntime struct{}
 func (crictlRuntime) Name() string    { return "k8s" }
 func (crictlRuntime) Available() bool { return binAvailable("crictl") }
 
-func (crictlRuntime) List() []*model.ContainerMatch {
-	ctx, cancel := context.WithTimeout(context.Background(), runtimeQueryTimeout)
+func (crictlRuntime) List(ctx context.Context) []*model.ContainerMatch {
+	ctx, cancel := context.WithTimeout(ctx, runtimeQueryTimeout)
 	defer cancel()
 	out, err := exec.CommandContext(ctx, "crictl", "ps", "-o", "json").Output()
 	if err != nil {
@@ -56,16 +56,16 @@ func (crictlRuntime) List() []*model.ContainerMatch {
 	return matches
 }
 
-func (crictlRuntime) HostPID(id string) int {
-	info, _ := crictlInspect(id)
+func (crictlRuntime) HostPID(ctx context.Context, id string) int {
+	info, _ := crictlInspect(ctx, id)
 	return info.Info.Pid
 }
 
 // Enrich populates Command, Mounts, and a more precise StartedAt by calling
 // `crictl inspect` for the resolved container. Skips fields the inspect
 // payload doesn't carry; partial enrichment is fine.
-func (crictlRuntime) Enrich(match *model.ContainerMatch) {
-	payload, ok := crictlInspect(match.ID)
+func (crictlRuntime) Enrich(ctx context.Context, match *model.ContainerMatch) {
+	payload, ok := crictlInspect(ctx, match.ID)
 	if !ok {
 		return
 	}
@@ -109,9 +109,9 @@ type crictlInspectPayload struct {
 	} `json:"info"`
 }
 
-func crictlInspect(id string) (crictlInspectPayload, bool) {
+func crictlInspect(ctx context.Context, id string) (crictlInspectPayload, bool) {
 	var p crictlInspectPayload
-	ctx, cancel := context.WithTimeout(context.Background(), runtimeQueryTimeout)
+	ctx, cancel := context.WithTimeout(ctx, runtimeQueryTimeout)
 	defer cancel()
 	out, err := exec.CommandContext(ctx, "crictl", "inspect", id).Output()
 	if err != nil {
@@ -32,8 +32,8 @@ var rootlessBins = map[string]bool{
 	"nerdctl": true,
 }
 
-func dockerLikeList(bin, runtime string) []*model.ContainerMatch {
-	ctx, cancel := context.WithTimeout(context.Background(), runtimeQueryTimeout)
+func dockerLikeList(ctx context.Context, bin, runtime string) []*model.ContainerMatch {
+	ctx, cancel := context.WithTimeout(ctx, runtimeQueryTimeout)
 	defer cancel()
 
 	// {{.Labels}} returns the full label map as comma-separated key=value
@@ -136,8 +136,8 @@ func parseDockerTime(s string) time.Time {
 	return time.Time{}
 }
 
-func dockerLikeHostPID(bin, id string) int {
-	ctx, cancel := context.WithTimeout(context.Background(), runtimeQueryTimeout)
+func dockerLikeHostPID(ctx context.Context, bin, id string) int {
+	ctx, cancel := context.WithTimeout(ctx, runtimeQueryTimeout)
 	defer cancel()
 	out, err := runtimeCommand(ctx, bin, "inspect", "-f", "{{.State.Pid}}", id).Output()
 	if err != nil {
@@ -151,11 +151,11 @@ func dockerLikeHostPID(bin, id string) int {
 // `<bin> inspect --format '{{.State.StartedAt}}'`. The list scan only gives
 // us creation time, which is misleading for any container that was stopped
 // and restarted later.
-func dockerLikeEnrich(bin string, match *model.ContainerMatch) {
+func dockerLikeEnrich(ctx context.Context, bin string, match *model.ContainerMatch) {
 	if match == nil || match.ID == "" {
 		return
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), runtimeQueryTimeout)
+	ctx, cancel := context.WithTimeout(ctx, runtimeQueryTimeout)
 	defer cancel()
 	out, err := runtimeCommand(ctx, bin, "inspect", "-f", "{{.State.StartedAt}}", match.ID).Output()
 	if err != nil {
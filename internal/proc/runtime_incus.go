@@ -1,13 +1,23 @@
 package proc
 
-import "github.com/pranshuparmar/witr/pkg/model"
+import (
+	"context"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
 
 func init() { registerRuntime(incusRuntime{}) }
 
 type incusRuntime struct{}
 
-func (incusRuntime) Name() string                       { return "incus" }
-func (incusRuntime) Available() bool                    { return binAvailable("incus") }
-func (incusRuntime) List() []*model.ContainerMatch      { return lxdLikeList("incus", "incus") }
-func (incusRuntime) HostPID(id string) int              { return lxdLikeHostPID("incus", id) }
-func (incusRuntime) Enrich(match *model.ContainerMatch) { lxdLikeEnrich("incus", match) }
+func (incusRuntime) Name() string    { return "incus" }
+func (incusRuntime) Available() bool { return binAvailable("incus") }
+func (incusRuntime) List(ctx context.Context) []*model.ContainerMatch {
+	return lxdLikeList(ctx, "incus", "incus")
+}
+func (incusRuntime) HostPID(ctx context.Context, id string) int {
+	return lxdLikeHostPID(ctx, "incus", id)
+}
+func (incusRuntime) Enrich(ctx context.Context, match *model.ContainerMatch) {
+	lxdLikeEnrich(ctx, "incus", match)
+}
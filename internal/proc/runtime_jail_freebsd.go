@@ -19,17 +19,17 @@ type jailRuntime struct{}
 func (jailRuntime) Name() string    { return "jail" }
 func (jailRuntime) Available() bool { return binAvailable("jls") }
 
-func (jailRuntime) List() []*model.ContainerMatch {
-	if matches, ok := jailListJSON(); ok {
+func (jailRuntime) List(ctx context.Context) []*model.ContainerMatch {
+	if matches, ok := jailListJSON(ctx); ok {
 		return matches
 	}
-	return jailListText()
+	return jailListText(ctx)
 }
 
 // jailListJSON uses libxo's JSON encoder (available on modern FreeBSD) so
 // values containing whitespace are parsed unambiguously.
-func jailListJSON() ([]*model.ContainerMatch, bool) {
-	ctx, cancel := context.WithTimeout(context.Background(), runtimeQueryTimeout)
+func jailListJSON(ctx context.Context) ([]*model.ContainerMatch, bool) {
+	ctx, cancel := context.WithTimeout(ctx, runtimeQueryTimeout)
 	defer cancel()
 	out, err := exec.CommandContext(ctx, "jls", "--libxo=json", "jid", "name", "host.hostname", "path", "dying").Output()
 	if err != nil {
@@ -66,8 +66,8 @@ func jailListJSON() ([]*model.ContainerMatch, bool) {
 
 // jailListText is the legacy whitespace-parsing fallback for FreeBSD
 // releases without libxo support in jls.
-func jailListText() []*model.ContainerMatch {
-	ctx, cancel := context.WithTimeout(context.Background(), runtimeQueryTimeout)
+func jailListText(ctx context.Context) []*model.ContainerMatch {
+	ctx, cancel := context.WithTimeout(ctx, runtimeQueryTimeout)
 	defer cancel()
 	out, err := exec.CommandContext(ctx, "jls", "-h", "jid", "name", "host.hostname", "path", "dying").Output()
 	if err != nil {
@@ -101,8 +101,8 @@ func jailListText() []*model.ContainerMatch {
 	return matches
 }
 
-func (jailRuntime) HostPID(id string) int {
-	ctx, cancel := context.WithTimeout(context.Background(), runtimeQueryTimeout)
+func (jailRuntime) HostPID(ctx context.Context, id string) int {
+	ctx, cancel := context.WithTimeout(ctx, runtimeQueryTimeout)
 	defer cancel()
 	out, err := exec.CommandContext(ctx, "ps", "-J", id, "-o", "pid=").Output()
 	if err != nil {
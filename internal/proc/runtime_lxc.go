@@ -21,8 +21,8 @@ func (lxcRuntime) Available() bool { return binAvailable("lxc-ls") }
 // record with name/state/ipv4/ipv6/groups/autostart/unprivileged. Image and
 // PID aren't part of the listing — PID is filled in lazily via HostPID, image
 // is left empty since classic LXC doesn't track image metadata after create.
-func (lxcRuntime) List() []*model.ContainerMatch {
-	ctx, cancel := context.WithTimeout(context.Background(), runtimeQueryTimeout)
+func (lxcRuntime) List(ctx context.Context) []*model.ContainerMatch {
+	ctx, cancel := context.WithTimeout(ctx, runtimeQueryTimeout)
 	defer cancel()
 	out, err := exec.CommandContext(ctx, "lxc-ls", "--fancy", "--format", "json").Output()
 	if err != nil {
@@ -31,8 +31,8 @@ func (lxcRuntime) List() []*model.ContainerMatch {
 	return parseLXCList(out)
 }
 
-func (lxcRuntime) HostPID(id string) int {
-	ctx, cancel := context.WithTimeout(context.Background(), runtimeQueryTimeout)
+func (lxcRuntime) HostPID(ctx context.Context, id string) int {
+	ctx, cancel := context.WithTimeout(ctx, runtimeQueryTimeout)
 	defer cancel()
 	out, err := exec.CommandContext(ctx, "lxc-info", "-n", id, "-p", "-H").Output()
 	if err != nil {
@@ -1,6 +1,10 @@
 package proc
 
-import "github.com/pranshuparmar/witr/pkg/model"
+import (
+	"context"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
 
 func init() { registerRuntime(lxdRuntime{}) }
 
@@ -12,7 +16,11 @@ func (lxdRuntime) Name() string { return "lxd" }
 // We require both `lxc` and the `lxd` daemon binary to be present so we don't
 // accidentally call into classic LXC's tooling (which doesn't take a `list`
 // subcommand) on a system that has lxc-* installed but no LXD.
-func (lxdRuntime) Available() bool                    { return binAvailable("lxc") && binAvailable("lxd") }
-func (lxdRuntime) List() []*model.ContainerMatch      { return lxdLikeList("lxc", "lxd") }
-func (lxdRuntime) HostPID(id string) int              { return lxdLikeHostPID("lxc", id) }
-func (lxdRuntime) Enrich(match *model.ContainerMatch) { lxdLikeEnrich("lxc", match) }
+func (lxdRuntime) Available() bool { return binAvailable("lxc") && binAvailable("lxd") }
+func (lxdRuntime) List(ctx context.Context) []*model.ContainerMatch {
+	return lxdLikeList(ctx, "lxc", "lxd")
+}
+func (lxdRuntime) HostPID(ctx context.Context, id string) int { return lxdLikeHostPID(ctx, "lxc", id) }
+func (lxdRuntime) Enrich(ctx context.Context, match *model.ContainerMatch) {
+	lxdLikeEnrich(ctx, "lxc", match)
+}
@@ -39,8 +39,8 @@ type lxdLikeNetworkEntry struct {
 	} `json:"addresses"`
 }
 
-func lxdLikeList(bin, runtime string) []*model.ContainerMatch {
-	ctx, cancel := context.WithTimeout(context.Background(), runtimeQueryTimeout)
+func lxdLikeList(ctx context.Context, bin, runtime string) []*model.ContainerMatch {
+	ctx, cancel := context.WithTimeout(ctx, runtimeQueryTimeout)
 	defer cancel()
 	out, err := exec.CommandContext(ctx, bin, "list", "--format", "json").Output()
 	if err != nil {
@@ -49,11 +49,11 @@ func lxdLikeList(bin, runtime string) []*model.ContainerMatch {
 	return parseLXDLikeList(out, runtime)
 }
 
-func lxdLikeHostPID(bin, id string) int {
+func lxdLikeHostPID(ctx context.Context, bin, id string) int {
 	if !isValidContainerID(id) {
 		return 0
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), runtimeQueryTimeout)
+	ctx, cancel := context.WithTimeout(ctx, runtimeQueryTimeout)
 	defer cancel()
 	out, err := exec.CommandContext(ctx, bin, "list", id, "--format", "json").Output()
 	if err != nil {
@@ -74,11 +74,11 @@ func lxdLikeHostPID(bin, id string) int {
 	return 0
 }
 
-func lxdLikeEnrich(bin string, match *model.ContainerMatch) {
+func lxdLikeEnrich(ctx context.Context, bin string, match *model.ContainerMatch) {
 	if match == nil || match.Name == "" || !isValidContainerID(match.Name) {
 		return
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), runtimeQueryTimeout)
+	ctx, cancel := context.WithTimeout(ctx, runtimeQueryTimeout)
 	defer cancel()
 	out, err := exec.CommandContext(ctx, bin, "list", match.Name, "--format", "json").Output()
 	if err != nil {
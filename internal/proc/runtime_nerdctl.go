@@ -1,13 +1,23 @@
 package proc
 
-import "github.com/pranshuparmar/witr/pkg/model"
+import (
+	"context"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
 
 func init() { registerRuntime(nerdctlRuntime{}) }
 
 type nerdctlRuntime struct{}
 
-func (nerdctlRuntime) Name() string                       { return "containerd" }
-func (nerdctlRuntime) Available() bool                    { return binAvailable("nerdctl") }
-func (nerdctlRuntime) List() []*model.ContainerMatch      { return dockerLikeList("nerdctl", "containerd") }
-func (nerdctlRuntime) HostPID(id string) int              { return dockerLikeHostPID("nerdctl", id) }
-func (nerdctlRuntime) Enrich(match *model.ContainerMatch) { dockerLikeEnrich("nerdctl", match) }
+func (nerdctlRuntime) Name() string    { return "containerd" }
+func (nerdctlRuntime) Available() bool { return binAvailable("nerdctl") }
+func (nerdctlRuntime) List(ctx context.Context) []*model.ContainerMatch {
+	return dockerLikeList(ctx, "nerdctl", "containerd")
+}
+func (nerdctlRuntime) HostPID(ctx context.Context, id string) int {
+	return dockerLikeHostPID(ctx, "nerdctl", id)
+}
+func (nerdctlRuntime) Enrich(ctx context.Context, match *model.ContainerMatch) {
+	dockerLikeEnrich(ctx, "nerdctl", match)
+}
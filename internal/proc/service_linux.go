@@ -4,8 +4,9 @@ package proc
 
 import (
 	"fmt"
-	"os"
 	"strings"
+
+	"github.com/pranshuparmar/witr/internal/procfs"
 )
 
 // serviceFromCgroup derives the systemd .service unit owning pid from its cgroup
@@ -14,7 +15,7 @@ import (
 // Returns "" when the process belongs to a .scope (login session, app scope) or
 // to no systemd unit.
 func serviceFromCgroup(pid int) string {
-	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	data, err := procfs.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
 	if err != nil {
 		return ""
 	}
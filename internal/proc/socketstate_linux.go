@@ -4,19 +4,24 @@ package proc
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 
+	"github.com/pranshuparmar/witr/internal/procfs"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
 // GetSocketStateForPort returns the socket state for a port
-// Linux implementation using /proc/net/tcp and /proc/net/tcp6
+// Linux implementation using the tcp and tcp6 tables of every network
+// namespace
 func GetSocketStateForPort(port int) *model.SocketInfo {
 	// Check both IPv4 and IPv6
-	files := []string{"/proc/net/tcp", "/proc/net/tcp6"}
+	var files []string
+	for _, t := range NetTables() {
+		files = append(files, t.Dir+"/tcp", t.Dir+"/tcp6")
+	}
 
 	var states []model.SocketInfo
 
@@ -24,13 +29,12 @@ func GetSocketStateForPort(port int) *model.SocketInfo {
 		isIPv6 := strings.HasSuffix(file, "tcp6")
 
 		func() {
-			f, err := os.Open(file)
+			data, err := procfs.ReadFile(file)
 			if err != nil {
 				return
 			}
-			defer f.Close()
 
-			scanner := bufio.NewScanner(f)
+			scanner := bufio.NewScanner(bytes.NewReader(data))
 			scanner.Scan()
 
 			for scanner.Scan() {
@@ -0,0 +1,92 @@
+//go:build linux
+
+package proc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	unixDiagShowPeer = 0x4 // UDIAG_SHOW_PEER
+	unixDiagPeer     = 2   // UNIX_DIAG_PEER
+)
+
+// UnixPeers maps the inode of every connected unix socket on the host to the
+// inode of the socket at the other end. /proc/net/unix does not record
+// peers, so they come from the kernel's sock_diag netlink interface, which
+// only describes the live system.
+func UnixPeers() (map[string]string, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, unix.NETLINK_SOCK_DIAG)
+	if err != nil {
+		return nil, fmt.Errorf("sock_diag: %w", err)
+	}
+	defer unix.Close(fd)
+
+	// nlmsghdr followed by unix_diag_req: every state, with peers.
+	req := make([]byte, unix.NLMSG_HDRLEN+24)
+	binary.NativeEndian.PutUint32(req[0:], uint32(len(req)))
+	binary.NativeEndian.PutUint16(req[4:], unix.SOCK_DIAG_BY_FAMILY)
+	binary.NativeEndian.PutUint16(req[6:], unix.NLM_F_REQUEST|unix.NLM_F_DUMP)
+	body := req[unix.NLMSG_HDRLEN:]
+	body[0] = unix.AF_UNIX
+	binary.NativeEndian.PutUint32(body[4:], 0xffffffff)
+	binary.NativeEndian.PutUint32(body[12:], unixDiagShowPeer)
+	if err := unix.Sendto(fd, req, 0, &unix.SockaddrNetlink{Family: unix.AF_NETLINK}); err != nil {
+		return nil, fmt.Errorf("sock_diag: %w", err)
+	}
+
+	peers := make(map[string]string)
+	buf := make([]byte, 64*1024)
+	for {
+		n, _, err := unix.Recvfrom(fd, buf, 0)
+		if err != nil {
+			return nil, fmt.Errorf("sock_diag: %w", err)
+		}
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			return nil, fmt.Errorf("sock_diag: %w", err)
+		}
+		for _, m := range msgs {
+			switch m.Header.Type {
+			case unix.NLMSG_DONE:
+				return peers, nil
+			case unix.NLMSG_ERROR:
+				return nil, fmt.Errorf("sock_diag: request refused")
+			}
+			if ino, peer, ok := parseUnixDiagMsg(m.Data); ok {
+				peers[strconv.FormatUint(uint64(ino), 10)] = strconv.FormatUint(uint64(peer), 10)
+			}
+		}
+	}
+}
+
+// parseUnixDiagMsg reads the inode of a unix_diag_msg and its
+// UNIX_DIAG_PEER attribute, when it has one.
+func parseUnixDiagMsg(data []byte) (ino, peer uint32, ok bool) {
+	const msgLen = 16 // family, type, state, pad, ino, cookie[2]
+	if len(data) < msgLen {
+		return 0, 0, false
+	}
+	ino = binary.NativeEndian.Uint32(data[4:])
+	for attrs := data[msgLen:]; len(attrs) >= unix.SizeofRtAttr; {
+		l := int(binary.NativeEndian.Uint16(attrs[0:]))
+		typ := binary.NativeEndian.Uint16(attrs[2:])
+		if l < unix.SizeofRtAttr || l > len(attrs) {
+			break
+		}
+		if typ == unixDiagPeer && l >= unix.SizeofRtAttr+4 {
+			return ino, binary.NativeEndian.Uint32(attrs[unix.SizeofRtAttr:]), true
+		}
+		next := (l + unix.RTA_ALIGNTO - 1) &^ (unix.RTA_ALIGNTO - 1)
+		if next >= len(attrs) {
+			break
+		}
+		attrs = attrs[next:]
+	}
+	return ino, 0, false
+}
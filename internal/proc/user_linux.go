@@ -3,11 +3,11 @@
 package proc
 
 import (
-	"os"
 	"strconv"
 	"strings"
 	"sync"
-	"syscall"
+
+	"github.com/pranshuparmar/witr/internal/procfs"
 )
 
 var (
@@ -19,7 +19,7 @@ func loadUserCache() map[int]string {
 	cache := make(map[int]string)
 	cache[0] = "root"
 
-	data, err := os.ReadFile("/etc/passwd")
+	data, err := procfs.ReadFile("/etc/passwd")
 	if err != nil {
 		return cache
 	}
@@ -36,20 +36,27 @@ func loadUserCache() map[int]string {
 }
 
 func readUser(pid int) string {
-	path := "/proc/" + strconv.Itoa(pid)
-
-	info, err := os.Stat(path)
+	// The effective UID from status, rather than the owner of /proc/<pid>,
+	// so that a snapshot or synthetic proc tree reports the same user.
+	data, err := procfs.ReadFile("/proc/" + strconv.Itoa(pid) + "/status")
 	if err != nil {
 		return "unknown"
 	}
 
-	stat, ok := info.Sys().(*syscall.Stat_t)
-	if !ok {
+	uid := -1
+	for line := range strings.Lines(string(data)) {
+		if rest, ok := strings.CutPrefix(line, "Uid:"); ok {
+			fields := strings.Fields(rest)
+			if len(fields) > 1 {
+				uid, _ = strconv.Atoi(fields[1])
+			}
+			break
+		}
+	}
+	if uid < 0 {
 		return "unknown"
 	}
 
-	uid := int(stat.Uid)
-
 	userCacheOnce.Do(func() {
 		userCache = loadUserCache()
 	})
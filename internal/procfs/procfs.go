@@ -0,0 +1,173 @@
+// Package procfs is the filesystem witr reads kernel process state from:
+// /proc, /sys, and the few host files (/etc/passwd, /run/systemd, a process's
+// working directory) needed to interpret them. By default it reads the running
+// host. SetRoot points it at a mounted host filesystem (e.g. /host inside a
+// debug container), and Set swaps in any FS, which lets tests feed synthetic
+// procfs trees.
+//
+// Paths are always written as on the host ("/proc/1/stat"); the FS decides
+// where they live. Symlink targets (fd links, cwd, exe) are returned verbatim
+// and are therefore host paths, not paths under the root.
+package procfs
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// FS reads files by absolute host path.
+type FS interface {
+	ReadFile(name string) ([]byte, error)
+	ReadDir(name string) ([]fs.DirEntry, error)
+	ReadLink(name string) (string, error)
+	// Stat follows symlinks, like os.Stat.
+	Stat(name string) (fs.FileInfo, error)
+}
+
+var (
+	mu         sync.RWMutex
+	current    FS = HostFS("")
+	live          = true
+	generation uint64
+)
+
+// Set replaces the filesystem every reader uses. isLive reports whether fsys
+// is the running host, which gates work that only makes sense against the
+// live system: ignoring witr's own PID, D-Bus queries, and runtime CLIs.
+func Set(fsys FS, isLive bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = fsys
+	live = isLive
+	generation++
+}
+
+// SetRoot reads the host's /proc, /sys and /etc from under root instead of
+// from /. An empty root or "/" restores the default.
+func SetRoot(root string) error {
+	if root == "" || root == "/" {
+		Set(HostFS(""), true)
+		return nil
+	}
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(filepath.Join(abs, "proc")); err != nil || !info.IsDir() {
+		return fmt.Errorf("invalid proc root %q: no proc directory under it", root)
+	}
+	Set(HostFS(abs), false)
+	return nil
+}
+
+// Current returns the active filesystem.
+func Current() FS {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Live reports whether the active filesystem is the running host.
+func Live() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return live
+}
+
+// Generation changes every time Set is called, so callers that cache parsed
+// files can tell their cache belongs to a previous filesystem.
+func Generation() uint64 {
+	mu.RLock()
+	defer mu.RUnlock()
+	return generation
+}
+
+// ReadFile reads name from the active filesystem.
+func ReadFile(name string) ([]byte, error) { return Current().ReadFile(name) }
+
+// ReadDir lists name in the active filesystem.
+func ReadDir(name string) ([]fs.DirEntry, error) { return Current().ReadDir(name) }
+
+// ReadLink returns the target of the symlink name in the active filesystem.
+func ReadLink(name string) (string, error) { return Current().ReadLink(name) }
+
+// Stat stats name in the active filesystem, following symlinks.
+func Stat(name string) (fs.FileInfo, error) { return Current().Stat(name) }
+
+// Exists reports whether name exists in the active filesystem.
+func Exists(name string) bool {
+	_, err := Stat(name)
+	return err == nil
+}
+
+// HostFS reads the operating system's filesystem with every path prefixed by
+// the root it names; HostFS("") is the host itself.
+type HostFS string
+
+func (h HostFS) path(name string) string {
+	if h == "" {
+		return name
+	}
+	return filepath.Join(string(h), name)
+}
+
+func (h HostFS) ReadFile(name string) ([]byte, error)       { return os.ReadFile(h.path(name)) }
+func (h HostFS) ReadDir(name string) ([]fs.DirEntry, error) { return os.ReadDir(h.path(name)) }
+func (h HostFS) ReadLink(name string) (string, error)       { return os.Readlink(h.path(name)) }
+func (h HostFS) Stat(name string) (fs.FileInfo, error)      { return os.Stat(h.path(name)) }
+
+// FromFS adapts an io/fs filesystem whose root is the host's "/" — such as
+// an fstest.MapFS with "proc/1/stat" entries — to FS.
+func FromFS(fsys fs.FS) FS {
+	return ioFS{fsys}
+}
+
+type ioFS struct{ fsys fs.FS }
+
+func (f ioFS) name(name string) string {
+	name = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(name)), "/")
+	if name == "" {
+		return "."
+	}
+	return name
+}
+
+func (f ioFS) ReadFile(name string) ([]byte, error) { return fs.ReadFile(f.fsys, f.name(name)) }
+func (f ioFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(f.fsys, f.name(name))
+}
+func (f ioFS) ReadLink(name string) (string, error) { return fs.ReadLink(f.fsys, f.name(name)) }
+
+// Stat follows a symlink whose target lies inside the filesystem; a link to a
+// host path outside it (an fd pointing at /var/log/x) stats as the link.
+func (f ioFS) Stat(name string) (fs.FileInfo, error) {
+	info, err := fs.Stat(f.fsys, f.name(name))
+	if err == nil {
+		return info, nil
+	}
+	if linfo, lerr := fs.Lstat(f.fsys, f.name(name)); lerr == nil {
+		return linfo, nil
+	}
+	return nil, err
+}
+
+// FileID identifies a file by device and inode number. Filesystems that do
+// not sit on the host (snapshots) return it from FileInfo.Sys for the files
+// that fd links point at.
+type FileID struct {
+	Dev uint64
+	Ino uint64
+}
+
+// StatID returns the device and inode behind info, whether it came from the
+// host's stat(2) or from a filesystem that records a FileID.
+func StatID(info fs.FileInfo) (FileID, bool) {
+	if id, ok := info.Sys().(FileID); ok {
+		return id, true
+	}
+	return hostStatID(info)
+}
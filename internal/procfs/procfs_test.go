@@ -0,0 +1,124 @@
+package procfs
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+// swap installs fsys for the duration of the test.
+func swap(t *testing.T, fsys FS, isLive bool) {
+	t.Helper()
+	prev, prevLive := Current(), Live()
+	Set(fsys, isLive)
+	t.Cleanup(func() { Set(prev, prevLive) })
+}
+
+func TestHostFSPrefixesRoot(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "proc", "1"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "proc", "1", "comm"), []byte("init\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("/usr/sbin/init", filepath.Join(root, "proc", "1", "exe")); err != nil {
+		t.Fatal(err)
+	}
+
+	h := HostFS(root)
+	data, err := h.ReadFile("/proc/1/comm")
+	if err != nil || string(data) != "init\n" {
+		t.Fatalf("ReadFile = %q, %v; want %q", data, err, "init\n")
+	}
+	entries, err := h.ReadDir("/proc")
+	if err != nil || len(entries) != 1 || entries[0].Name() != "1" {
+		t.Fatalf("ReadDir(/proc) = %v, %v; want [1]", entries, err)
+	}
+	// Link targets are host paths and must come back unprefixed.
+	if link, err := h.ReadLink("/proc/1/exe"); err != nil || link != "/usr/sbin/init" {
+		t.Fatalf("ReadLink = %q, %v; want /usr/sbin/init", link, err)
+	}
+}
+
+func TestFromFS(t *testing.T) {
+	fsys := FromFS(fstest.MapFS{
+		"proc/42/comm":  {Data: []byte("nginx\n")},
+		"proc/42/cwd":   {Data: []byte("/srv/www"), Mode: fs.ModeSymlink},
+		"proc/42/fd/3":  {Data: []byte("socket:[1234]"), Mode: fs.ModeSymlink},
+		"proc/net/tcp6": {Data: []byte("header\n")},
+	})
+
+	if data, err := fsys.ReadFile("/proc/42/comm"); err != nil || string(data) != "nginx\n" {
+		t.Errorf("ReadFile = %q, %v", data, err)
+	}
+	if entries, err := fsys.ReadDir("/proc/42/fd"); err != nil || len(entries) != 1 {
+		t.Errorf("ReadDir = %v, %v; want one fd", entries, err)
+	}
+	if link, err := fsys.ReadLink("/proc/42/fd/3"); err != nil || link != "socket:[1234]" {
+		t.Errorf("ReadLink = %q, %v", link, err)
+	}
+	if entries, err := fsys.ReadDir("/"); err != nil || len(entries) != 1 {
+		t.Errorf("ReadDir(/) = %v, %v; want [proc]", entries, err)
+	}
+
+	// A link pointing outside the tree stats as the link itself.
+	info, err := fsys.Stat("/proc/42/cwd")
+	if err != nil {
+		t.Fatalf("Stat(dangling link): %v", err)
+	}
+	if info.Mode()&fs.ModeSymlink == 0 {
+		t.Errorf("Stat(dangling link) mode = %v, want a symlink", info.Mode())
+	}
+	if _, err := fsys.Stat("/proc/43"); err == nil {
+		t.Error("Stat of a missing path should fail")
+	}
+}
+
+func TestSetRoot(t *testing.T) {
+	swap(t, HostFS(""), true)
+
+	if err := SetRoot(t.TempDir()); err == nil {
+		t.Error("SetRoot should reject a directory without proc/")
+	}
+	if !Live() {
+		t.Error("a rejected root must leave the live filesystem in place")
+	}
+
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "proc"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	gen := Generation()
+	if err := SetRoot(root); err != nil {
+		t.Fatalf("SetRoot(%s): %v", root, err)
+	}
+	if Live() {
+		t.Error("Live() = true under a proc root")
+	}
+	if Current() != HostFS(root) {
+		t.Errorf("Current() = %v, want HostFS(%q)", Current(), root)
+	}
+	if Generation() == gen {
+		t.Error("Generation() did not change after SetRoot")
+	}
+
+	for _, r := range []string{"", "/"} {
+		if err := SetRoot(r); err != nil || !Live() || Current() != HostFS("") {
+			t.Errorf("SetRoot(%q) did not restore the host filesystem", r)
+		}
+	}
+}
+
+func TestExists(t *testing.T) {
+	swap(t, FromFS(fstest.MapFS{"run/systemd/system": {Mode: fs.ModeDir}}), false)
+
+	if !Exists("/run/systemd/system") {
+		t.Error("Exists(/run/systemd/system) = false")
+	}
+	if Exists("/run/openrc") {
+		t.Error("Exists(/run/openrc) = true")
+	}
+}
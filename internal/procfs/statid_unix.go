@@ -0,0 +1,16 @@
+//go:build !windows
+
+package procfs
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+func hostStatID(info fs.FileInfo) (FileID, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return FileID{}, false
+	}
+	return FileID{Dev: uint64(st.Dev), Ino: uint64(st.Ino)}, true
+}
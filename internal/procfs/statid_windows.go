@@ -0,0 +1,7 @@
+//go:build windows
+
+package procfs
+
+import "io/fs"
+
+func hostStatID(fs.FileInfo) (FileID, bool) { return FileID{}, false }
@@ -0,0 +1,158 @@
+// Package schema derives a JSON Schema (draft 2020-12) from Go types by
+// reflection, following encoding/json's rules for field names, omitempty and
+// nil values, so a published schema cannot drift from what the types marshal
+// to. Objects are left open to unknown properties: adding a field to a type
+// must not break validators pinned to an older schema.
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON Schema dialect Generate emits.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// Generate returns the schema of v's type as a JSON-ready map. The root type
+// is described inline; every other named struct goes in $defs and is
+// referenced by name.
+func Generate(v any) map[string]any {
+	g := &generator{defs: map[string]any{}}
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	root := map[string]any{"$schema": Draft}
+	var body map[string]any
+	if t.Kind() == reflect.Struct {
+		g.seen = map[reflect.Type]bool{t: true}
+		body = g.object(t)
+	} else {
+		body = g.schema(t)
+	}
+	for k, v := range body {
+		root[k] = v
+	}
+	if len(g.defs) > 0 {
+		root["$defs"] = g.defs
+	}
+	return root
+}
+
+type generator struct {
+	defs map[string]any
+	seen map[reflect.Type]bool
+}
+
+// schema describes a value of type t that is never nil.
+func (g *generator) schema(t reflect.Type) map[string]any {
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schema(t.Elem())
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		if !g.seen[t] {
+			g.seen[t] = true
+			g.defs[t.Name()] = g.object(t)
+		}
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	}
+	// Interfaces and anything else: any JSON value.
+	return map[string]any{}
+}
+
+// object describes a struct's properties. Fields that are never omitted are
+// required; those that marshal nil values (pointers, slices, maps) may be
+// null.
+func (g *generator) object(t reflect.Type) map[string]any {
+	props := map[string]any{}
+	var required []string
+	g.fields(t, props, &required)
+
+	obj := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		obj["required"] = required
+	}
+	return obj
+}
+
+func (g *generator) fields(t reflect.Type, props map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		// encoding/json never omits a struct value, omitempty or not.
+		omitempty := strings.Contains(","+opts+",", ",omitempty,") && f.Type.Kind() != reflect.Struct
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.fields(ft, props, required)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		s := g.schema(f.Type)
+		if !omitempty {
+			*required = append(*required, name)
+			switch f.Type.Kind() {
+			case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+				s = nullable(s)
+			}
+		}
+		props[name] = s
+	}
+}
+
+func nullable(s map[string]any) map[string]any {
+	if typ, ok := s["type"].(string); ok {
+		out := make(map[string]any, len(s))
+		for k, v := range s {
+			out[k] = v
+		}
+		out["type"] = []string{typ, "null"}
+		return out
+	}
+	if len(s) == 0 {
+		return s
+	}
+	return map[string]any{"anyOf": []any{s, map[string]any{"type": "null"}}}
+}
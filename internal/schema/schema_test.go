@@ -0,0 +1,96 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type inner struct {
+	N int
+}
+
+type sample struct {
+	Name     string
+	Renamed  string `json:"alias"`
+	Skipped  string `json:"-"`
+	Optional string `json:",omitempty"`
+	Count    uint64
+	Ratio    float64
+	When     time.Time
+	Tags     []string
+	Labels   map[string]string
+	Ptr      *inner
+	Nested   inner
+	Kept     inner `json:",omitempty"`
+	Many     []inner
+	hidden   int
+}
+
+func TestGenerate(t *testing.T) {
+	got := roundTrip(t, Generate(sample{}))
+
+	want := roundTrip(t, map[string]any{
+		"$schema": Draft,
+		"type":    "object",
+		"properties": map[string]any{
+			"Name":     map[string]any{"type": "string"},
+			"alias":    map[string]any{"type": "string"},
+			"Optional": map[string]any{"type": "string"},
+			"Count":    map[string]any{"type": "integer", "minimum": 0},
+			"Ratio":    map[string]any{"type": "number"},
+			"When":     map[string]any{"type": "string", "format": "date-time"},
+			"Tags":     map[string]any{"type": []string{"array", "null"}, "items": map[string]any{"type": "string"}},
+			"Labels":   map[string]any{"type": []string{"object", "null"}, "additionalProperties": map[string]any{"type": "string"}},
+			"Ptr":      map[string]any{"anyOf": []any{map[string]any{"$ref": "#/$defs/inner"}, map[string]any{"type": "null"}}},
+			"Nested":   map[string]any{"$ref": "#/$defs/inner"},
+			"Kept":     map[string]any{"$ref": "#/$defs/inner"},
+			"Many":     map[string]any{"type": []string{"array", "null"}, "items": map[string]any{"$ref": "#/$defs/inner"}},
+		},
+		"required": []string{"Name", "alias", "Count", "Ratio", "When", "Tags", "Labels", "Ptr", "Nested", "Kept", "Many"},
+		"$defs": map[string]any{
+			"inner": map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"N": map[string]any{"type": "integer"}},
+				"required":   []string{"N"},
+			},
+		},
+	})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Generate() =\n%s\nwant\n%s", pretty(got), pretty(want))
+	}
+}
+
+func TestGenerateFlattensEmbedded(t *testing.T) {
+	type wrapper struct {
+		inner
+		Extra bool
+	}
+	s := Generate(wrapper{})
+	props := s["properties"].(map[string]any)
+	if _, ok := props["N"]; !ok {
+		t.Errorf("embedded fields should be promoted, got %v", props)
+	}
+	if _, ok := s["$defs"]; ok {
+		t.Errorf("an embedded struct should not get a $defs entry: %v", s["$defs"])
+	}
+}
+
+func roundTrip(t *testing.T, v any) any {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func pretty(v any) string {
+	data, _ := json.MarshalIndent(v, "", "  ")
+	return string(data)
+}
@@ -0,0 +1,130 @@
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// compression is the outer encoding of a snapshot archive.
+type compression int
+
+const (
+	compressNone compression = iota
+	compressGzip
+	compressZstd
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// compressionFor picks the encoding from the file name: .tar.gz/.tgz,
+// .tar.zst/.tzst, anything else is a plain tar.
+func compressionFor(name string) compression {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".gz"), strings.HasSuffix(lower, ".tgz"):
+		return compressGzip
+	case strings.HasSuffix(lower, ".zst"), strings.HasSuffix(lower, ".tzst"):
+		return compressZstd
+	}
+	return compressNone
+}
+
+// CaptureFile captures a snapshot into the file name, compressed according
+// to its extension. The file is readable by its owner only: it holds every
+// process's environment, secrets included.
+func CaptureFile(name string, opts Options) (Meta, error) {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return Meta{}, err
+	}
+	w, err := compressWriter(f, compressionFor(name))
+	if err != nil {
+		f.Close()
+		os.Remove(name)
+		return Meta{}, err
+	}
+
+	meta, err := Capture(w, opts)
+	if cerr := w.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(name)
+		return Meta{}, err
+	}
+	return meta, nil
+}
+
+func compressWriter(f *os.File, c compression) (io.WriteCloser, error) {
+	switch c {
+	case compressGzip:
+		return gzip.NewWriter(f), nil
+	case compressZstd:
+		return zstd.NewWriter(f)
+	}
+	return nopWriteCloser{f}, nil
+}
+
+// openArchive opens name and undoes its compression, detected from the
+// content rather than the extension.
+func openArchive(name string) (io.ReadCloser, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	br := bufio.NewReader(f)
+	head, _ := br.Peek(len(zstdMagic))
+
+	switch {
+	case bytes.HasPrefix(head, gzipMagic):
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		return readCloser{zr, f}, nil
+	case bytes.HasPrefix(head, zstdMagic):
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		return zstdReader{zr, f}, nil
+	}
+	return readCloser{br, f}, nil
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+type readCloser struct {
+	io.Reader
+	c io.Closer
+}
+
+func (r readCloser) Close() error { return r.c.Close() }
+
+// zstdReader releases the decoder's goroutines along with the file.
+type zstdReader struct {
+	*zstd.Decoder
+	f *os.File
+}
+
+func (r zstdReader) Close() error {
+	r.Decoder.Close()
+	return r.f.Close()
+}
@@ -0,0 +1,323 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/procfs"
+)
+
+// hostFiles are the system-wide files the pipeline reads.
+var hostFiles = []string{
+	"/proc/stat",
+	"/proc/uptime",
+	"/proc/meminfo",
+	"/proc/loadavg",
+	"/proc/locks",
+	"/proc/net/tcp",
+	"/proc/net/tcp6",
+	"/proc/net/udp",
+	"/proc/net/udp6",
+	"/proc/net/unix",
+	"/proc/sys/kernel/hostname",
+	"/proc/sys/kernel/osrelease",
+	"/proc/sys/net/ipv6/bindv6only",
+	"/sys/class/thermal/thermal_zone0/temp",
+	"/sys/fs/cgroup/cgroup.controllers",
+	"/etc/passwd",
+	"/etc/group",
+}
+
+// hostDirs are directories whose existence alone is meaningful.
+var hostDirs = []string{
+	"/run/systemd/system",
+}
+
+// pidFiles are the per-process files the pipeline reads, besides stat, which
+// is read first to tell whether the process still exists.
+var pidFiles = []string{
+	"status",
+	"comm",
+	"cmdline",
+	"environ",
+	"cgroup",
+	"io",
+	"statm",
+	"limits",
+}
+
+// pidLinks are the per-process symlinks the pipeline reads.
+var pidLinks = []string{
+	"cwd",
+	"exe",
+	"root",
+	"ns/cgroup",
+	"ns/ipc",
+	"ns/mnt",
+	"ns/net",
+	"ns/pid",
+	"ns/time",
+	"ns/user",
+	"ns/uts",
+}
+
+// netFiles are the socket tables of a network namespace, recorded for
+// every namespace but the capturing process's own, whose are in hostFiles.
+var netFiles = []string{"tcp", "tcp6", "udp", "udp6", "unix"}
+
+// Options controls Capture.
+type Options struct {
+	// WitrVersion is recorded in the snapshot metadata.
+	WitrVersion string
+	// Now stamps the snapshot; zero means time.Now.
+	Now time.Time
+}
+
+// Capture writes an uncompressed snapshot of the active procfs to w and
+// returns its metadata. Processes that exit mid-capture and files the caller
+// may not read (another user's environ without root) are skipped, exactly as
+// the live readers skip them.
+func Capture(w io.Writer, opts Options) (Meta, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	c := &capturer{tw: tar.NewWriter(w), now: now, dirs: map[string]bool{"/": true}}
+
+	for _, name := range hostFiles {
+		if err := c.file(name); err != nil {
+			return Meta{}, err
+		}
+	}
+	for _, name := range hostDirs {
+		if info, err := procfs.Stat(name); err == nil && info.IsDir() {
+			if err := c.dir(name); err != nil {
+				return Meta{}, err
+			}
+		}
+	}
+
+	// The namespace /proc/net describes, to tell it from the others.
+	if err := c.link("/proc/self/ns/net", false); err != nil {
+		return Meta{}, err
+	}
+
+	entries, err := procfs.ReadDir("/proc")
+	if err != nil {
+		return Meta{}, fmt.Errorf("read /proc: %w", err)
+	}
+	processes := 0
+	cgroups := map[string]bool{}
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		ok, err := c.process("/proc/" + e.Name())
+		if err != nil {
+			return Meta{}, err
+		}
+		if ok {
+			processes++
+			if dir := procpkg.CgroupDir(pid); dir != "" {
+				cgroups[dir] = true
+			}
+		}
+	}
+	// The limits and pressure of every cgroup a process is in.
+	for _, dir := range slices.Sorted(maps.Keys(cgroups)) {
+		for _, name := range procpkg.CgroupFiles {
+			if err := c.file(dir + "/" + name); err != nil {
+				return Meta{}, err
+			}
+		}
+	}
+	for _, t := range procpkg.NetTables()[1:] {
+		for _, name := range netFiles {
+			if err := c.file(t.Dir + "/" + name); err != nil {
+				return Meta{}, err
+			}
+		}
+	}
+
+	meta := Meta{
+		FormatVersion: formatVersion,
+		WitrVersion:   opts.WitrVersion,
+		Hostname:      readTrimmed("/proc/sys/kernel/hostname"),
+		Kernel:        readTrimmed("/proc/sys/kernel/osrelease"),
+		CapturedAt:    now,
+		Processes:     processes,
+		Containers:    procpkg.RecordContainers(context.Background()),
+	}
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return Meta{}, err
+	}
+	if err := c.write("/"+metaName, data); err != nil {
+		return Meta{}, err
+	}
+	if err := c.tw.Close(); err != nil {
+		return Meta{}, err
+	}
+	return meta, nil
+}
+
+type capturer struct {
+	tw   *tar.Writer
+	now  time.Time
+	dirs map[string]bool
+}
+
+// process records one /proc/<pid> directory. It reports false when the
+// process vanished before its stat file could be read.
+func (c *capturer) process(dir string) (bool, error) {
+	stat, err := procfs.ReadFile(dir + "/stat")
+	if err != nil {
+		return false, nil
+	}
+	if err := c.write(dir+"/stat", stat); err != nil {
+		return false, err
+	}
+	for _, name := range pidFiles {
+		if err := c.file(dir + "/" + name); err != nil {
+			return false, err
+		}
+	}
+	for _, name := range pidLinks {
+		if err := c.link(dir+"/"+name, false); err != nil {
+			return false, err
+		}
+	}
+
+	if fds, err := procfs.ReadDir(dir + "/fd"); err == nil {
+		if err := c.dir(dir + "/fd"); err != nil {
+			return false, err
+		}
+		for _, fd := range fds {
+			if err := c.link(dir+"/fd/"+fd.Name(), true); err != nil {
+				return false, err
+			}
+		}
+	}
+	if infos, err := procfs.ReadDir(dir + "/fdinfo"); err == nil {
+		for _, fd := range infos {
+			if err := c.file(dir + "/fdinfo/" + fd.Name()); err != nil {
+				return false, err
+			}
+		}
+	}
+	return true, nil
+}
+
+// file records name if it can be read.
+func (c *capturer) file(name string) error {
+	data, err := procfs.ReadFile(name)
+	if errors.Is(err, fs.ErrPermission) {
+		return c.denied(name)
+	}
+	if err != nil {
+		return nil
+	}
+	return c.write(name, data)
+}
+
+// link records the symlink name if it can be read. withTarget also records
+// the stat data of the file it points at, for fd links.
+func (c *capturer) link(name string, withTarget bool) error {
+	target, err := procfs.ReadLink(name)
+	if errors.Is(err, fs.ErrPermission) {
+		// The entry still counts: an fd the caller may not resolve is
+		// still an open fd.
+		return c.denied(name)
+	}
+	if err != nil {
+		return nil
+	}
+	if err := c.dir(path.Dir(name)); err != nil {
+		return err
+	}
+	hdr := &tar.Header{
+		Typeflag: tar.TypeSymlink,
+		Name:     strings.TrimPrefix(name, "/"),
+		Linkname: target,
+		Mode:     0o777,
+		ModTime:  c.now,
+	}
+	if withTarget {
+		if info, err := procfs.Stat(name); err == nil {
+			if id, ok := procfs.StatID(info); ok {
+				hdr.PAXRecords = targetToPAX(info, id)
+			}
+		}
+	}
+	return c.tw.WriteHeader(hdr)
+}
+
+func (c *capturer) write(name string, data []byte) error {
+	if err := c.dir(path.Dir(name)); err != nil {
+		return err
+	}
+	hdr := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     strings.TrimPrefix(name, "/"),
+		Size:     int64(len(data)),
+		Mode:     0o444,
+		ModTime:  c.now,
+	}
+	if err := c.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := c.tw.Write(data)
+	return err
+}
+
+// denied records that name exists but could not be read, so the replayed
+// read fails the same way.
+func (c *capturer) denied(name string) error {
+	if err := c.dir(path.Dir(name)); err != nil {
+		return err
+	}
+	return c.tw.WriteHeader(&tar.Header{
+		Typeflag:   tar.TypeReg,
+		Name:       strings.TrimPrefix(name, "/"),
+		ModTime:    c.now,
+		PAXRecords: map[string]string{paxDenied: "1"},
+	})
+}
+
+// dir records name and any parents not yet written.
+func (c *capturer) dir(name string) error {
+	if c.dirs[name] {
+		return nil
+	}
+	if err := c.dir(path.Dir(name)); err != nil {
+		return err
+	}
+	c.dirs[name] = true
+	return c.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     strings.TrimPrefix(name, "/") + "/",
+		Mode:     int64(fs.ModePerm &^ 0o222),
+		ModTime:  c.now,
+	})
+}
+
+func readTrimmed(name string) string {
+	data, err := procfs.ReadFile(name)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,166 @@
+package snapshot
+
+import (
+	"io/fs"
+	"path"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/pranshuparmar/witr/internal/procfs"
+)
+
+// maxLinkHops bounds symlink resolution inside a snapshot, as ELOOP does.
+const maxLinkHops = 8
+
+// node is one file, directory or symlink of a snapshot.
+type node struct {
+	name    string
+	mode    fs.FileMode
+	modTime time.Time
+	data    []byte
+	link    string
+	// target describes the file a symlink pointed at when it was captured
+	// (the file behind an fd link); nil when it could not be stat'ed.
+	target *targetInfo
+	// denied entries are listed by ReadDir but fail every other call with
+	// fs.ErrPermission, as they did for the user who captured them.
+	denied bool
+}
+
+type targetInfo struct {
+	mode fs.FileMode
+	size int64
+	id   procfs.FileID
+}
+
+// memFS is an in-memory procfs.FS holding a loaded snapshot. Paths are
+// absolute host paths, as procfs callers write them.
+type memFS struct {
+	nodes    map[string]*node
+	children map[string][]string
+}
+
+func newMemFS() *memFS {
+	fsys := &memFS{nodes: map[string]*node{}, children: map[string][]string{}}
+	fsys.nodes["/"] = &node{name: "/", mode: fs.ModeDir | 0o555}
+	return fsys
+}
+
+// add inserts n at name, creating missing parent directories.
+func (m *memFS) add(name string, n *node) {
+	name = path.Clean("/" + name)
+	if name == "/" {
+		return
+	}
+	n.name = path.Base(name)
+	if _, exists := m.nodes[name]; !exists {
+		parent := path.Dir(name)
+		if _, ok := m.nodes[parent]; !ok {
+			m.add(parent, &node{mode: fs.ModeDir | 0o555, modTime: n.modTime})
+		}
+		m.children[parent] = append(m.children[parent], n.name)
+	}
+	m.nodes[name] = n
+}
+
+// finish sorts directory listings once every node is loaded.
+func (m *memFS) finish() {
+	for _, names := range m.children {
+		sort.Strings(names)
+	}
+}
+
+// resolve follows symlinks whose targets lie inside the snapshot.
+func (m *memFS) resolve(name string) (*node, string) {
+	name = path.Clean("/" + name)
+	for hops := 0; hops < maxLinkHops; hops++ {
+		n, ok := m.nodes[name]
+		if !ok || n.mode&fs.ModeSymlink == 0 {
+			return n, name
+		}
+		next := n.link
+		if !path.IsAbs(next) {
+			next = path.Join(path.Dir(name), next)
+		}
+		if _, ok := m.nodes[path.Clean(next)]; !ok {
+			return n, name
+		}
+		name = path.Clean(next)
+	}
+	return nil, name
+}
+
+func (m *memFS) ReadFile(name string) ([]byte, error) {
+	n, _ := m.resolve(name)
+	switch {
+	case n != nil && n.denied:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+	case n == nil, n.mode&fs.ModeSymlink != 0:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	case n.mode.IsDir():
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrInvalid}
+	}
+	return slices.Clone(n.data), nil
+}
+
+func (m *memFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	n, dir := m.resolve(name)
+	if n == nil || !n.mode.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	names := m.children[dir]
+	entries := make([]fs.DirEntry, 0, len(names))
+	for _, child := range names {
+		entries = append(entries, fs.FileInfoToDirEntry(fileInfo{m.nodes[path.Join(dir, child)]}))
+	}
+	return entries, nil
+}
+
+func (m *memFS) ReadLink(name string) (string, error) {
+	n, ok := m.nodes[path.Clean("/"+name)]
+	if !ok {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrNotExist}
+	}
+	if n.denied {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrPermission}
+	}
+	if n.mode&fs.ModeSymlink == 0 {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+	return n.link, nil
+}
+
+// Stat follows links inside the snapshot. A link to a host file reports the
+// file as it was at capture time, which is what os.Stat on an fd link gives.
+func (m *memFS) Stat(name string) (fs.FileInfo, error) {
+	n, _ := m.resolve(name)
+	if n == nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	if n.denied {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrPermission}
+	}
+	if n.target != nil {
+		return targetFileInfo{n}, nil
+	}
+	return fileInfo{n}, nil
+}
+
+type fileInfo struct{ n *node }
+
+func (fi fileInfo) Name() string       { return fi.n.name }
+func (fi fileInfo) Size() int64        { return int64(len(fi.n.data)) }
+func (fi fileInfo) Mode() fs.FileMode  { return fi.n.mode }
+func (fi fileInfo) ModTime() time.Time { return fi.n.modTime }
+func (fi fileInfo) IsDir() bool        { return fi.n.mode.IsDir() }
+func (fi fileInfo) Sys() any           { return nil }
+
+type targetFileInfo struct{ n *node }
+
+func (fi targetFileInfo) Name() string       { return fi.n.name }
+func (fi targetFileInfo) Size() int64        { return fi.n.target.size }
+func (fi targetFileInfo) Mode() fs.FileMode  { return fi.n.target.mode }
+func (fi targetFileInfo) ModTime() time.Time { return fi.n.modTime }
+func (fi targetFileInfo) IsDir() bool        { return fi.n.target.mode.IsDir() }
+func (fi targetFileInfo) Sys() any           { return fi.n.target.id }
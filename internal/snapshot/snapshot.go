@@ -0,0 +1,200 @@
+// Package snapshot freezes the procfs state witr reads into a tar archive and
+// replays it later, so a box can be captured once in production and analyzed
+// somewhere else. Capture walks the active procfs (the live host, or a
+// --proc-root) and records every file the pipeline reads plus the container
+// runtimes' listings; Open loads an archive back into an in-memory procfs.FS.
+//
+// Archive layout: host paths without the leading slash ("proc/1/stat"),
+// symlinks as tar symlinks, and metadata in witr/snapshot.json. The file an
+// fd link pointed at is recorded on the link as WITR.* PAX records, because
+// the lock and file-context readers identify files by device and inode.
+package snapshot
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/procfs"
+)
+
+// formatVersion is bumped when the archive layout changes incompatibly.
+const formatVersion = 1
+
+const metaName = "witr/snapshot.json"
+
+// WITR.* PAX records: the stat data of an fd link's target, and a marker
+// for entries that exist but could not be read.
+const (
+	paxDev    = "WITR.dev"
+	paxIno    = "WITR.ino"
+	paxMode   = "WITR.mode"
+	paxSize   = "WITR.size"
+	paxDenied = "WITR.denied"
+)
+
+// maxFileSize caps any single archive member on load; no procfs file the
+// pipeline reads comes near it.
+const maxFileSize = 64 << 20
+
+// maxTotalSize caps the files of a whole archive on load, all of which are
+// held in memory. A var so tests can lower it.
+var maxTotalSize int64 = 1 << 30
+
+// Meta describes where and when a snapshot was taken.
+type Meta struct {
+	FormatVersion int
+	WitrVersion   string
+	Hostname      string
+	Kernel        string
+	CapturedAt    time.Time
+	Processes     int
+	Containers    []procpkg.RecordedRuntime `json:",omitempty"`
+}
+
+// Snapshot is a loaded archive, ready to Install.
+type Snapshot struct {
+	Meta Meta
+	fs   *memFS
+}
+
+// FS returns the snapshot's files as a procfs.FS.
+func (s *Snapshot) FS() procfs.FS { return s.fs }
+
+// Install makes every procfs reader and container lookup use the snapshot
+// for the rest of the process.
+func (s *Snapshot) Install() {
+	procfs.Set(s.fs, false)
+	procpkg.UseRecordedContainers(s.Meta.Containers)
+}
+
+// Open loads the snapshot archive at path, compressed or not.
+func Open(name string) (*Snapshot, error) {
+	r, err := openArchive(name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	s, err := Read(r)
+	if err != nil {
+		return nil, fmt.Errorf("read snapshot %s: %w", name, err)
+	}
+	return s, nil
+}
+
+// Read loads an uncompressed snapshot tar stream.
+func Read(r io.Reader) (*Snapshot, error) {
+	fsys := newMemFS()
+	var meta *Meta
+
+	var total int64
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		name := path.Clean("/" + hdr.Name)
+		if hdr.Size > maxFileSize {
+			return nil, fmt.Errorf("%s: member too large (%d bytes)", hdr.Name, hdr.Size)
+		}
+		if total += hdr.Size; total > maxTotalSize {
+			return nil, fmt.Errorf("archive too large: more than %d bytes of files", maxTotalSize)
+		}
+
+		if name == "/"+path.Dir(metaName) || strings.HasPrefix(name, "/"+path.Dir(metaName)+"/") {
+			// witr's own files, not part of the host's filesystem
+			if name == "/"+metaName {
+				meta = &Meta{}
+				if err := json.NewDecoder(tr).Decode(meta); err != nil {
+					return nil, fmt.Errorf("%s: %w", metaName, err)
+				}
+			}
+			continue
+		}
+
+		n := &node{
+			mode:    fs.FileMode(hdr.Mode) & fs.ModePerm,
+			modTime: hdr.ModTime,
+			denied:  hdr.PAXRecords[paxDenied] != "",
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			n.mode |= fs.ModeDir
+		case tar.TypeSymlink:
+			n.mode |= fs.ModeSymlink
+			n.link = hdr.Linkname
+			n.target = targetFromPAX(hdr.PAXRecords)
+		case tar.TypeReg:
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", hdr.Name, err)
+			}
+			n.data = data
+		default:
+			continue
+		}
+		fsys.add(name, n)
+	}
+
+	if meta == nil {
+		return nil, fmt.Errorf("not a witr snapshot (no %s)", metaName)
+	}
+	if meta.FormatVersion > formatVersion {
+		return nil, fmt.Errorf("snapshot format %d is newer than this witr supports (%d); upgrade witr", meta.FormatVersion, formatVersion)
+	}
+	fsys.finish()
+	return &Snapshot{Meta: *meta, fs: fsys}, nil
+}
+
+func targetFromPAX(recs map[string]string) *targetInfo {
+	ino, err := strconv.ParseUint(recs[paxIno], 10, 64)
+	if err != nil {
+		return nil
+	}
+	dev, _ := strconv.ParseUint(recs[paxDev], 10, 64)
+	mode, _ := strconv.ParseUint(recs[paxMode], 8, 32)
+	size, _ := strconv.ParseInt(recs[paxSize], 10, 64)
+	return &targetInfo{
+		mode: fs.FileMode(mode),
+		size: size,
+		id:   procfs.FileID{Dev: dev, Ino: ino},
+	}
+}
+
+func targetToPAX(info fs.FileInfo, id procfs.FileID) map[string]string {
+	return map[string]string{
+		paxDev:  strconv.FormatUint(id.Dev, 10),
+		paxIno:  strconv.FormatUint(id.Ino, 10),
+		paxMode: strconv.FormatUint(uint64(info.Mode()), 8),
+		paxSize: strconv.FormatInt(info.Size(), 10),
+	}
+}
+
+// Describe is a one-line summary of where a snapshot came from.
+func (m Meta) Describe() string {
+	var b strings.Builder
+	b.WriteString("snapshot")
+	if m.Hostname != "" {
+		b.WriteString(" of " + m.Hostname)
+	}
+	if !m.CapturedAt.IsZero() {
+		b.WriteString(" taken " + m.CapturedAt.Local().Format("2006-01-02 15:04:05 MST"))
+	}
+	if m.Kernel != "" {
+		b.WriteString(" (Linux " + m.Kernel + ")")
+	}
+	return b.String()
+}
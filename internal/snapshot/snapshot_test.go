@@ -0,0 +1,331 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/procfs"
+)
+
+// useFS serves procfs from fsys for the rest of the test.
+func useFS(t *testing.T, fsys procfs.FS) {
+	t.Helper()
+	prev, prevLive := procfs.Current(), procfs.Live()
+	procfs.Set(fsys, false)
+	t.Cleanup(func() { procfs.Set(prev, prevLive) })
+}
+
+func link(target string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(target), Mode: fs.ModeSymlink}
+}
+
+func sourceTree() fstest.MapFS {
+	return fstest.MapFS{
+		"proc/stat":                        {Data: []byte("cpu 1 2 3\nbtime 1760000000\n")},
+		"proc/net/tcp":                     {Data: []byte("  sl  local_address\n")},
+		"proc/sys/kernel/hostname":         {Data: []byte("db-01\n")},
+		"proc/sys/kernel/osrelease":        {Data: []byte("6.8.0-45-generic\n")},
+		"etc/passwd":                       {Data: []byte("root:x:0:0::/root:/bin/sh\npostgres:x:113:120::/var/lib/postgresql:/bin/sh\n")},
+		"run/systemd/system":               {Mode: fs.ModeDir},
+		"proc/1/stat":                      {Data: []byte("1 (systemd) S 0 1 1 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 10 0 0\n")},
+		"proc/1/comm":                      {Data: []byte("systemd\n")},
+		"proc/310/stat":                    {Data: []byte("310 (postgres) S 1 310 310 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 500 0 0\n")},
+		"proc/310/comm":                    {Data: []byte("postgres\n")},
+		"proc/310/cmdline":                 {Data: []byte("/usr/lib/postgresql/16/bin/postgres\x00-D\x00/var/lib/postgresql\x00")},
+		"proc/310/environ":                 {Data: []byte("PGDATA=/var/lib/postgresql\x00")},
+		"proc/310/cwd":                     link("/var/lib/postgresql"),
+		"proc/310/exe":                     link("/usr/lib/postgresql/16/bin/postgres"),
+		"proc/310/fd/0":                    link("/dev/null"),
+		"proc/310/fd/5":                    link("socket:[5001]"),
+		"proc/310/fdinfo/5":                {Data: []byte("pos:\t0\nflags:\t02004002\n")},
+		"proc/self/stat":                   {Data: []byte("not a pid directory")},
+		"proc/self/ns/net":                 link("net:[4026531840]"),
+		"proc/1/ns/net":                    link("net:[4026531840]"),
+		"proc/310/ns/net":                  link("net:[4026532289]"),
+		"proc/310/net/tcp":                 {Data: []byte("  sl  local_address in the container\n")},
+		"proc/310/cgroup":                  {Data: []byte("0::/system.slice/postgresql.service\n")},
+		"sys/fs/cgroup/cgroup.controllers": {Data: []byte("cpu io memory pids\n")},
+		"sys/fs/cgroup/system.slice/postgresql.service/memory.max":      {Data: []byte("2147483648\n")},
+		"sys/fs/cgroup/system.slice/postgresql.service/memory.pressure": {Data: []byte("some avg10=0.00 avg60=0.00 avg300=0.00 total=0\n")},
+		"sys/fs/cgroup/system.slice/nginx.service/memory.max":           {Data: []byte("max\n")},
+		"proc/4242/cmdline":           {Data: []byte("exited before its stat was read")},
+		"home/alice/notes.txt":        {Data: []byte("not part of a snapshot")},
+		"proc/310/task/310/comm":      {Data: []byte("not captured either")},
+		"proc/sys/kernel/random/uuid": {Data: []byte("nor this")},
+	}
+}
+
+func capture(t *testing.T, opts Options) ([]byte, Meta) {
+	t.Helper()
+	var buf bytes.Buffer
+	meta, err := Capture(&buf, opts)
+	if err != nil {
+		t.Fatalf("Capture: %v", err)
+	}
+	return buf.Bytes(), meta
+}
+
+func TestCaptureAndRead(t *testing.T) {
+	useFS(t, procfs.FromFS(sourceTree()))
+	captured := time.Date(2026, 3, 14, 9, 26, 53, 0, time.UTC)
+
+	data, meta := capture(t, Options{WitrVersion: "v1.2.3", Now: captured})
+	if meta.Processes != 2 {
+		t.Errorf("Meta.Processes = %d, want 2 (pid 4242 has no stat)", meta.Processes)
+	}
+	if meta.Hostname != "db-01" || meta.Kernel != "6.8.0-45-generic" {
+		t.Errorf("Meta host = %q/%q, want db-01/6.8.0-45-generic", meta.Hostname, meta.Kernel)
+	}
+
+	snap, err := Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if snap.Meta.WitrVersion != "v1.2.3" || !snap.Meta.CapturedAt.Equal(captured) || snap.Meta.FormatVersion != formatVersion {
+		t.Errorf("round-tripped Meta = %+v", snap.Meta)
+	}
+	fsys := snap.FS()
+
+	for name, want := range map[string]string{
+		"/proc/310/cmdline":  "/usr/lib/postgresql/16/bin/postgres\x00-D\x00/var/lib/postgresql\x00",
+		"/proc/310/environ":  "PGDATA=/var/lib/postgresql\x00",
+		"/proc/310/fdinfo/5": "pos:\t0\nflags:\t02004002\n",
+		"/proc/stat":         "cpu 1 2 3\nbtime 1760000000\n",
+		"/proc/310/net/tcp":  "  sl  local_address in the container\n",
+		// The cgroups processes are in, for their limits.
+		"/sys/fs/cgroup/system.slice/postgresql.service/memory.max": "2147483648\n",
+		"/etc/passwd": "root:x:0:0::/root:/bin/sh\npostgres:x:113:120::/var/lib/postgresql:/bin/sh\n",
+	} {
+		got, err := fsys.ReadFile(name)
+		if err != nil || string(got) != want {
+			t.Errorf("ReadFile(%s) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	for name, want := range map[string]string{
+		"/proc/310/cwd":  "/var/lib/postgresql",
+		"/proc/310/fd/5": "socket:[5001]",
+		// The namespaces, so the container's socket tables can be told
+		// from the capturing process's own.
+		"/proc/self/ns/net": "net:[4026531840]",
+		"/proc/310/ns/net":  "net:[4026532289]",
+	} {
+		if got, err := fsys.ReadLink(name); err != nil || got != want {
+			t.Errorf("ReadLink(%s) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if entries, err := fsys.ReadDir("/proc/310/fd"); err != nil || len(entries) != 2 {
+		t.Errorf("ReadDir(fd) = %v, %v; want 2 fds", entries, err)
+	}
+	if info, err := fsys.Stat("/run/systemd/system"); err != nil || !info.IsDir() {
+		t.Errorf("Stat(/run/systemd/system) = %v, %v; want a directory", info, err)
+	}
+
+	for _, name := range []string{
+		"/home/alice/notes.txt",
+		"/proc/self/stat",
+		"/proc/4242/cmdline",
+		"/proc/310/task/310/comm",
+		"/proc/sys/kernel/random/uuid",
+		"/sys/fs/cgroup/system.slice/nginx.service/memory.max",
+	} {
+		if _, err := fsys.ReadFile(name); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("ReadFile(%s) = %v, want it left out of the snapshot", name, err)
+		}
+	}
+
+	// Nothing but the witr metadata lives outside the host's paths.
+	if _, err := fsys.Stat("/witr"); err == nil {
+		t.Error("the metadata file should not appear in the replayed filesystem")
+	}
+}
+
+func TestCaptureRecordsFdTargets(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs symlinks and stat(2) device/inode numbers")
+	}
+	root := t.TempDir()
+	lockFile := filepath.Join(root, "postmaster.pid")
+	if err := os.WriteFile(lockFile, []byte("310\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fdDir := filepath.Join(root, "proc", "310", "fd")
+	if err := os.MkdirAll(fdDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "proc", "310", "stat"), []byte("310 (postgres) S 1"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(lockFile, filepath.Join(fdDir, "6")); err != nil {
+		t.Fatal(err)
+	}
+	hostInfo, err := os.Stat(lockFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostID, ok := procfs.StatID(hostInfo)
+	if !ok {
+		t.Skip("no device/inode numbers on this platform")
+	}
+
+	useFS(t, procfs.HostFS(root))
+	data, _ := capture(t, Options{})
+	snap, err := Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+
+	info, err := snap.FS().Stat("/proc/310/fd/6")
+	if err != nil {
+		t.Fatalf("Stat(fd link): %v", err)
+	}
+	if !info.Mode().IsRegular() || info.Size() != 4 {
+		t.Errorf("Stat(fd link) = mode %v size %d, want the regular 4-byte target", info.Mode(), info.Size())
+	}
+	if id, ok := procfs.StatID(info); !ok || id != hostID {
+		t.Errorf("StatID(fd link) = %+v, %v; want %+v", id, ok, hostID)
+	}
+}
+
+func TestDeniedEntries(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	meta, _ := json.Marshal(Meta{FormatVersion: formatVersion})
+	for _, hdr := range []*tar.Header{
+		{Typeflag: tar.TypeReg, Name: "proc/1/environ", PAXRecords: map[string]string{paxDenied: "1"}},
+		{Typeflag: tar.TypeReg, Name: "proc/1/fd/7", PAXRecords: map[string]string{paxDenied: "1"}},
+		{Typeflag: tar.TypeReg, Name: metaName, Size: int64(len(meta))},
+	} {
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tw.Write(meta)
+	tw.Close()
+
+	snap, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	fsys := snap.FS()
+	if entries, err := fsys.ReadDir("/proc/1/fd"); err != nil || len(entries) != 1 {
+		t.Errorf("ReadDir = %v, %v; a denied fd still counts", entries, err)
+	}
+	if _, err := fsys.ReadLink("/proc/1/fd/7"); !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("ReadLink(denied) = %v, want fs.ErrPermission", err)
+	}
+	if _, err := fsys.ReadFile("/proc/1/environ"); !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("ReadFile(denied) = %v, want fs.ErrPermission", err)
+	}
+	if _, err := fsys.Stat("/proc/1/environ"); !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("Stat(denied) = %v, want fs.ErrPermission", err)
+	}
+}
+
+func TestReadRejects(t *testing.T) {
+	tarWith := func(name string, data []byte) *bytes.Buffer {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Size: int64(len(data))})
+		tw.Write(data)
+		tw.Close()
+		return &buf
+	}
+
+	if _, err := Read(tarWith("proc/1/stat", []byte("1 (init) S 0"))); err == nil || !strings.Contains(err.Error(), "not a witr snapshot") {
+		t.Errorf("Read without metadata = %v, want a not-a-snapshot error", err)
+	}
+
+	future, _ := json.Marshal(Meta{FormatVersion: formatVersion + 1})
+	if _, err := Read(tarWith(metaName, future)); err == nil || !strings.Contains(err.Error(), "upgrade witr") {
+		t.Errorf("Read of a newer format = %v, want an upgrade hint", err)
+	}
+
+	prevMax := maxTotalSize
+	maxTotalSize = 16
+	t.Cleanup(func() { maxTotalSize = prevMax })
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, name := range []string{"proc/1/environ", "proc/2/environ"} {
+		tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Size: 10})
+		tw.Write([]byte("TOKEN=abcd"))
+	}
+	tw.Close()
+	if _, err := Read(&buf); err == nil || !strings.Contains(err.Error(), "archive too large") {
+		t.Errorf("Read past the total cap = %v, want an archive too large error", err)
+	}
+}
+
+func TestCaptureFileCompression(t *testing.T) {
+	useFS(t, procfs.FromFS(sourceTree()))
+
+	for _, name := range []string{"box.tar", "box.tar.gz", "box.tgz", "box.tar.zst"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if _, err := CaptureFile(path, Options{}); err != nil {
+				t.Fatalf("CaptureFile: %v", err)
+			}
+			if info, err := os.Stat(path); err != nil {
+				t.Fatal(err)
+			} else if runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+				t.Errorf("archive mode = %v, want 0600, readable by its owner only", info.Mode())
+			}
+			snap, err := Open(path)
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			if got, err := snap.FS().ReadFile("/proc/1/comm"); err != nil || string(got) != "systemd\n" {
+				t.Errorf("ReadFile(/proc/1/comm) = %q, %v", got, err)
+			}
+		})
+	}
+}
+
+func TestCompressionFor(t *testing.T) {
+	tests := map[string]compression{
+		"box.tar":     compressNone,
+		"box":         compressNone,
+		"box.tar.gz":  compressGzip,
+		"BOX.TGZ":     compressGzip,
+		"box.tar.zst": compressZstd,
+		"box.tzst":    compressZstd,
+	}
+	for name, want := range tests {
+		if got := compressionFor(name); got != want {
+			t.Errorf("compressionFor(%q) = %d, want %d", name, got, want)
+		}
+	}
+}
+
+func TestInstall(t *testing.T) {
+	useFS(t, procfs.FromFS(sourceTree()))
+	data, _ := capture(t, Options{})
+	snap, err := Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+
+	snap.Install()
+	t.Cleanup(procpkg.UseLiveContainers)
+
+	if procfs.Live() {
+		t.Error("procfs.Live() = true after installing a snapshot")
+	}
+	if got, err := procfs.ReadFile("/proc/310/comm"); err != nil || string(got) != "postgres\n" {
+		t.Errorf("procfs.ReadFile after Install = %q, %v", got, err)
+	}
+	if got := procpkg.ListAllContainers(context.Background()); len(got) != 0 {
+		t.Errorf("ListAllContainers() = %v, want the snapshot's (empty) listing", got)
+	}
+}
@@ -0,0 +1,52 @@
+package source
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pranshuparmar/witr/internal/config"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// CgroupWarnings returns what looks wrong about the cgroup a process runs
+// in: memory or tasks close to the cgroup's limit, and processes the OOM
+// killer has killed in it.
+func CgroupWarnings(cg *model.CgroupContext) []model.Warning {
+	if cg == nil {
+		return nil
+	}
+	th := config.Current().Thresholds
+	limit := strconv.FormatFloat(th.CgroupUsage, 'g', -1, 64) + "%"
+
+	var w []model.Warning
+	if pct, ok := usage(cg.MemoryCurrent, cg.MemoryMax); ok && pct >= th.CgroupUsage {
+		w = append(w, warning(model.WarnCgroupMemory, model.SeverityMedium,
+			fmt.Sprintf("Cgroup memory at %.0f%% of limit", pct),
+			"cgroup", cg.Path, "current", config.ByteSize(cg.MemoryCurrent).String(),
+			"max", config.ByteSize(cg.MemoryMax).String(), "threshold", limit))
+	}
+	if pct, ok := usage(cg.PIDsCurrent, cg.PIDsMax); ok && pct >= th.CgroupUsage {
+		w = append(w, warning(model.WarnCgroupPIDs, model.SeverityMedium,
+			fmt.Sprintf("Cgroup tasks at %.0f%% of pids limit", pct),
+			"cgroup", cg.Path, "current", strconv.FormatUint(cg.PIDsCurrent, 10),
+			"max", strconv.FormatUint(cg.PIDsMax, 10), "threshold", limit))
+	}
+	if cg.OOMKills > 0 {
+		kills := "1 OOM kill"
+		if cg.OOMKills > 1 {
+			kills = fmt.Sprintf("%d OOM kills", cg.OOMKills)
+		}
+		w = append(w, warning(model.WarnOOMKills, model.SeverityMedium,
+			"Process cgroup has had "+kills,
+			"cgroup", cg.Path, "oom_kill", strconv.FormatUint(cg.OOMKills, 10)))
+	}
+	return overrideSeverities(w)
+}
+
+// usage returns current as a percentage of max, when there is a limit.
+func usage(current, max uint64) (float64, bool) {
+	if max == 0 {
+		return 0, false
+	}
+	return float64(current) / float64(max) * 100, true
+}
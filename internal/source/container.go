@@ -1,16 +1,16 @@
 package source
 
 import (
-	"os"
 	"strconv"
 	"strings"
 
+	"github.com/pranshuparmar/witr/internal/procfs"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
 func detectContainer(ancestry []model.Process) *model.Source {
 	for _, p := range ancestry {
-		data, err := os.ReadFile("/proc/" + itoa(p.PID) + "/cgroup")
+		data, err := procfs.ReadFile("/proc/" + itoa(p.PID) + "/cgroup")
 		if err != nil {
 			continue
 		}
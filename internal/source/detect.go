@@ -1,57 +1,23 @@
 package source
 
 import (
+	"context"
 	"fmt"
 	"runtime"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/pranshuparmar/witr/internal/config"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
-var suspiciousDirs = map[string]bool{"/": true, "/tmp": true, "/var/tmp": true}
-
-var dangerousCapabilities = map[string]bool{
-	"CAP_SYS_ADMIN":       true,
-	"CAP_SYS_PTRACE":      true,
-	"CAP_NET_RAW":         true,
-	"CAP_DAC_OVERRIDE":    true,
-	"CAP_DAC_READ_SEARCH": true,
-	"CAP_FOWNER":          true,
-	"CAP_SYS_MODULE":      true,
-	"CAP_SYS_RAWIO":       true,
-}
-
-func isDangerousCapability(cap string) bool {
-	return dangerousCapabilities[cap]
-}
-
-type envSuspiciousRule struct {
-	pattern     string
-	match       func(key, pattern string) bool
-	warning     string
-	includeKeys bool
-}
-
-var (
-	envVarRules = []envSuspiciousRule{
-		{
-			pattern: "LD_PRELOAD",
-			match:   func(key, pattern string) bool { return key == pattern },
-			warning: "Process sets LD_PRELOAD (potential library injection)",
-		},
-
-		{
-			pattern:     "DYLD_",
-			match:       strings.HasPrefix,
-			warning:     "Process sets DYLD_* variables (potential library injection)",
-			includeKeys: true,
-		},
-	}
-)
-
-func Detect(ancestry []model.Process) model.Source {
+// Detect works out what started the last process of ancestry. Service
+// manager lookups (systemd's D-Bus, launchctl, sc) stop when ctx is done; the
+// source is then reported from what the ancestry alone shows.
+func Detect(ctx context.Context, ancestry []model.Process) model.Source {
 	// Detection order prioritizes platform-specific init systems
 	// over generic supervisor detection to avoid false positives
 	if src := detectContainer(ancestry); src != nil {
@@ -63,10 +29,10 @@ func Detect(ancestry []model.Process) model.Source {
 	if src := detectShell(ancestry); src != nil {
 		return *src
 	}
-	if src := detectSystemd(ancestry); src != nil {
+	if src := detectSystemd(ctx, ancestry); src != nil {
 		return *src
 	}
-	if src := detectLaunchd(ancestry); src != nil {
+	if src := detectLaunchd(ctx, ancestry); src != nil {
 		return *src
 	}
 	if src := detectBsdRc(ancestry); src != nil {
@@ -78,7 +44,7 @@ func Detect(ancestry []model.Process) model.Source {
 	if src := detectCron(ancestry); src != nil {
 		return *src
 	}
-	if src := detectWindowsService(ancestry); src != nil {
+	if src := detectWindowsService(ctx, ancestry); src != nil {
 		return *src
 	}
 	if src := detectInit(ancestry); src != nil {
@@ -91,16 +57,9 @@ func Detect(ancestry []model.Process) model.Source {
 }
 
 // env suspicious warnings returns warnings for known env based library injection patterns
-func envSuspiciousWarnings(env []string) []string {
-	matched := make([]bool, len(envVarRules))
-	matchedKeys := make([]map[string]struct{}, len(envVarRules))
-
-	// init per rule key capture only for rules that include keys
-	for i, rule := range envVarRules {
-		if rule.includeKeys {
-			matchedKeys[i] = map[string]struct{}{}
-		}
-	}
+func envSuspiciousWarnings(env []string) []model.Warning {
+	rules := config.Current().EnvRules
+	matchedKeys := make([]map[string]struct{}, len(rules))
 
 	// scan env entries and record which rules match
 	for _, entry := range env {
@@ -110,26 +69,22 @@ func envSuspiciousWarnings(env []string) []string {
 		}
 
 		// check this key against each configured rule
-		for i, rule := range envVarRules {
-			if !rule.match(key, rule.pattern) {
+		for i, rule := range rules {
+			if !rule.Matches(key) {
 				continue
 			}
-			matched[i] = true
-			if rule.includeKeys {
-				matchedKeys[i][key] = struct{}{}
+			if matchedKeys[i] == nil {
+				matchedKeys[i] = map[string]struct{}{}
 			}
+			matchedKeys[i][key] = struct{}{}
 		}
 	}
 
-	var warnings []string
+	var warnings []model.Warning
 
-	// emit warnings in the same order as envVarRules
-	for i, rule := range envVarRules {
-		if !matched[i] {
-			continue
-		}
-		if !rule.includeKeys {
-			warnings = append(warnings, rule.warning)
+	// emit warnings in the same order as the configured rules
+	for i, rule := range rules {
+		if matchedKeys[i] == nil {
 			continue
 		}
 
@@ -139,54 +94,101 @@ func envSuspiciousWarnings(env []string) []string {
 			keys = append(keys, key)
 		}
 		sort.Strings(keys)
-		warnings = append(warnings, rule.warning+": "+strings.Join(keys, ", "))
+
+		msg := rule.Warning
+		if rule.IncludeKeys {
+			msg += ": " + strings.Join(keys, ", ")
+		}
+		code, sev := model.WarningCode(rule.Code), model.Severity(rule.Severity)
+		if code == "" {
+			code = model.WarnSuspiciousEnv
+		}
+		if sev == "" {
+			sev = model.SeverityMedium
+		}
+		warnings = append(warnings, warning(code, sev, msg, "variables", strings.Join(keys, ", ")))
 	}
 
 	return warnings
 }
 
-func Warnings(p []model.Process, restartCount int, srcType ...model.SourceType) []string {
+// warning builds a Warning; evidence is key, value pairs.
+func warning(code model.WarningCode, sev model.Severity, msg string, evidence ...string) model.Warning {
+	w := model.Warning{Code: code, Severity: sev, Message: msg}
+	for i := 0; i+1 < len(evidence); i += 2 {
+		if w.Evidence == nil {
+			w.Evidence = map[string]string{}
+		}
+		w.Evidence[evidence[i]] = evidence[i+1]
+	}
+	return w
+}
+
+// Warnings returns what looks wrong about the last process of the ancestry,
+// each with a stable code and a severity; the configuration's severities
+// override the built-in ones.
+func Warnings(p []model.Process, restartCount int, srcType ...model.SourceType) []model.Warning {
 	if len(p) == 0 {
 		return nil
 	}
 
-	var w []string
+	var w []model.Warning
 
 	last := p[len(p)-1]
+	cfg := config.Current()
+	th := cfg.Thresholds
 
 	// Warn on a service that has restarted many times. restartCount is the real
 	// count from the service manager (e.g. systemd NRestarts), or 0 when unknown.
-	if restartCount > 5 {
-		w = append(w, fmt.Sprintf("Service has restarted %d times", restartCount))
+	if restartCount > th.Restarts {
+		w = append(w, warning(model.WarnRestarts, model.SeverityMedium,
+			fmt.Sprintf("Service has restarted %d times", restartCount),
+			"restarts", strconv.Itoa(restartCount), "threshold", strconv.Itoa(th.Restarts)))
 	}
 
 	// Health warnings
 	switch last.Health {
 	case "zombie":
-		w = append(w, "Process is a zombie (defunct)")
+		w = append(w, warning(model.WarnZombie, model.SeverityMedium, "Process is a zombie (defunct)"))
 	case "stopped":
-		w = append(w, "Process is stopped (T state)")
+		w = append(w, warning(model.WarnStopped, model.SeverityMedium, "Process is stopped (T state)"))
 	case "high-cpu":
-		w = append(w, "Process is using high CPU (>2h total)")
+		// macOS and FreeBSD flag CPU usage; Linux and Windows, CPU time.
+		if runtime.GOOS == "darwin" || runtime.GOOS == "freebsd" {
+			limit := strconv.FormatFloat(th.CPUPercent, 'g', -1, 64) + "%"
+			w = append(w, warning(model.WarnHighCPU, model.SeverityLow,
+				"Process is using high CPU (>"+limit+")", "threshold", limit))
+		} else {
+			w = append(w, warning(model.WarnHighCPU, model.SeverityLow,
+				fmt.Sprintf("Process is using high CPU (>%s total)", th.CPUTime), "threshold", th.CPUTime.String()))
+		}
 	case "high-mem":
-		w = append(w, "Process is using high memory (>1GB RSS)")
+		ev := []string{"threshold", th.Memory.String()}
+		if last.MemoryRSS > 0 {
+			ev = append(ev, "rss", config.ByteSize(last.MemoryRSS).String())
+		}
+		w = append(w, warning(model.WarnHighMemory, model.SeverityLow,
+			fmt.Sprintf("Process is using high memory (>%s RSS)", th.Memory), ev...))
 	}
 
 	if IsPublicBind(last.Sockets) {
-		w = append(w, "Process is listening on a public interface")
+		w = append(w, warning(model.WarnPublicBind, model.SeverityMedium,
+			"Process is listening on a public interface", "addresses", publicBinds(last.Sockets)))
 	}
 
 	if last.User == "root" {
-		w = append(w, "Process is running as root")
+		w = append(w, warning(model.WarnRoot, model.SeverityLow, "Process is running as root", "user", last.User))
 	} else if len(last.Capabilities) > 0 {
 		var dangerous []string
 		for _, cap := range last.Capabilities {
-			if isDangerousCapability(cap) {
+			if slices.Contains(cfg.DangerousCapabilities, cap) {
 				dangerous = append(dangerous, cap)
 			}
 		}
 		if len(dangerous) > 0 {
-			w = append(w, "Process has dangerous capabilities: "+strings.Join(dangerous, ", "))
+			caps := strings.Join(dangerous, ", ")
+			w = append(w, warning(model.WarnDangerousCaps, model.SeverityHigh,
+				"Process has dangerous capabilities: "+caps, "capabilities", caps))
 		}
 	}
 
@@ -194,31 +196,36 @@ func Warnings(p []model.Process, restartCount int, srcType ...model.SourceType)
 	if len(srcType) > 0 {
 		st = srcType[0]
 	} else {
-		st = Detect(p).Type
+		st = Detect(context.Background(), p).Type
 	}
 	// On Windows the ancestry frequently truncates at an orphaned process
 	// (Windows leaves a stale PPID instead of reparenting to an init process),
 	// so an unknown source is normal there — not a reliable "unsupervised"
 	// signal — and this warning would fire on most user processes.
 	if st == model.SourceUnknown && runtime.GOOS != "windows" {
-		w = append(w, "No known supervisor or service manager detected")
+		w = append(w, warning(model.WarnNoSupervisor, model.SeverityLow, "No known supervisor or service manager detected"))
 	}
 
-	// Warn if process is very old (>90 days). A zero start time means we
-	// couldn't read it (e.g. protected Windows processes), not that the process
-	// is ancient — skip the warning rather than emit a false positive.
-	if !last.StartedAt.IsZero() && time.Since(last.StartedAt).Hours() > 90*24 {
-		w = append(w, "Process has been running for over 90 days")
+	// Warn if process is very old (>90 days by default). A zero start time
+	// means we couldn't read it (e.g. protected Windows processes), not that
+	// the process is ancient — skip the warning rather than emit a false
+	// positive.
+	if !last.StartedAt.IsZero() && time.Since(last.StartedAt) > time.Duration(th.Age) {
+		w = append(w, warning(model.WarnLongRunning, model.SeverityInfo,
+			"Process has been running for over "+th.Age.Describe(),
+			"started", last.StartedAt.UTC().Format(time.RFC3339), "threshold", th.Age.String()))
 	}
 
-	if suspiciousDirs[last.WorkingDir] {
-		w = append(w, "Process is running from a suspicious working directory: "+last.WorkingDir)
+	if last.WorkingDir != "" && slices.Contains(cfg.SuspiciousDirs, last.WorkingDir) {
+		w = append(w, warning(model.WarnSuspiciousCwd, model.SeverityMedium,
+			"Process is running from a suspicious working directory: "+last.WorkingDir, "dir", last.WorkingDir))
 	}
 
 	// Warn only when the runtime confirms no healthcheck is configured. Unknown
 	// ("") — snap/flatpak, unprobed runtimes, non-Linux — does not warn.
 	if last.ContainerHealthcheck == "absent" {
-		w = append(w, "Container has no healthcheck configured")
+		w = append(w, warning(model.WarnNoHealthcheck, model.SeverityInfo,
+			"Container has no healthcheck configured", "container", last.Container))
 	}
 
 	// Warn if service name and process name are genuinely unrelated
@@ -236,21 +243,52 @@ func Warnings(p []model.Process, restartCount int, srcType ...model.SourceType)
 		svcCore = strings.ToLower(svcCore)
 		cmdBase := strings.ToLower(last.Command)
 		if !strings.Contains(svcCore, cmdBase) && !strings.Contains(cmdBase, svcCore) {
-			w = append(w, "Service name and process name do not match")
+			w = append(w, warning(model.WarnServiceMismatch, model.SeverityLow,
+				"Service name and process name do not match", "service", last.Service, "command", last.Command))
 		}
 	}
 
 	// Warn if binary is deleted
 	if last.ExeDeleted {
-		w = append(w, "Process is running from a deleted binary (potential library injection or pending update)")
+		w = append(w, warning(model.WarnDeletedExe, model.SeverityHigh,
+			"Process is running from a deleted binary (potential library injection or pending update)", "exe", last.Exe))
 	}
 
 	// Include warnings based on suspicious env variables
 	w = append(w, envSuspiciousWarnings(last.Env)...)
 
+	return overrideSeverities(w)
+}
+
+// overrideSeverities applies the configuration's severities to w.
+func overrideSeverities(w []model.Warning) []model.Warning {
+	sevs := config.Current().Severities
+	for i := range w {
+		if sev, ok := sevs[w[i].Code]; ok {
+			w[i].Severity = sev
+		}
+	}
 	return w
 }
 
+// publicBinds lists the listening sockets bound to every interface:
+// "0.0.0.0:80, [::]:443".
+func publicBinds(sockets []model.Socket) string {
+	var out []string
+	for _, s := range sockets {
+		if s.State != "LISTEN" {
+			continue
+		}
+		switch s.Address {
+		case "0.0.0.0":
+			out = append(out, fmt.Sprintf("0.0.0.0:%d", s.Port))
+		case "::":
+			out = append(out, fmt.Sprintf("[::]:%d", s.Port))
+		}
+	}
+	return strings.Join(out, ", ")
+}
+
 // EnrichSocketInfo provides human-readable explanations and workarounds for socket states
 func EnrichSocketInfo(si *model.SocketInfo) {
 	if si == nil {
@@ -1,6 +1,7 @@
 package source
 
 import (
+	"context"
 	"slices"
 	"strings"
 	"testing"
@@ -18,10 +19,10 @@ func TestDetectShellWindowsCaseInsensitive(t *testing.T) {
 			{PID: 100, Command: shell},
 			{PID: 200, PPID: 100, Command: "Claude.exe"},
 		}
-		if got := Detect(ancestry).Type; got != model.SourceShell {
+		if got := Detect(context.Background(), ancestry).Type; got != model.SourceShell {
 			t.Errorf("Detect with %q ancestor = %v; want SourceShell", shell, got)
 		}
-		if slices.Contains(Warnings(ancestry, 0), "No known supervisor or service manager detected") {
+		if slices.Contains(messages(Warnings(ancestry, 0)), "No known supervisor or service manager detected") {
 			t.Errorf("%q ancestor should not raise the no-supervisor warning", shell)
 		}
 	}
@@ -36,10 +37,10 @@ func TestDetectWindowsSystemKernel(t *testing.T) {
 		{PID: 4, Command: "System"},
 		{PID: 4108, PPID: 4, Command: "Memory Compression"},
 	}
-	if got := Detect(ancestry).Type; got != model.SourceInit {
+	if got := Detect(context.Background(), ancestry).Type; got != model.SourceInit {
 		t.Errorf("Detect with System (pid 4) root = %v; want SourceInit", got)
 	}
-	if slices.Contains(Warnings(ancestry, 0), "No known supervisor or service manager detected") {
+	if slices.Contains(messages(Warnings(ancestry, 0)), "No known supervisor or service manager detected") {
 		t.Errorf("System-rooted process should not raise the no-supervisor warning")
 	}
 }
@@ -57,7 +58,7 @@ func TestWarningsDetectsLDPreload(t *testing.T) {
 		},
 	}
 
-	warnings := Warnings(p, 0)
+	warnings := messages(Warnings(p, 0))
 	if !slices.Contains(warnings, "Process sets LD_PRELOAD (potential library injection)") {
 		t.Fatalf("expected LD_PRELOAD warning, got: %v", warnings)
 	}
@@ -79,7 +80,7 @@ func TestWarningsDetectsDYLDVars(t *testing.T) {
 		},
 	}
 
-	warnings := Warnings(p, 0)
+	warnings := messages(Warnings(p, 0))
 	want := "Process sets DYLD_* variables (potential library injection): DYLD_INSERT_LIBRARIES, DYLD_LIBRARY_PATH"
 	if !slices.Contains(warnings, want) {
 		t.Fatalf("expected DYLD warning %q, got: %v", want, warnings)
@@ -102,7 +103,7 @@ func TestWarningsIgnoresEmptyPreloadVars(t *testing.T) {
 		},
 	}
 
-	warnings := Warnings(p, 0)
+	warnings := messages(Warnings(p, 0))
 	if slices.Contains(warnings, "Process sets LD_PRELOAD (potential library injection)") {
 		t.Fatalf("did not expect LD_PRELOAD warning, got: %v", warnings)
 	}
@@ -129,8 +130,8 @@ func FuzzEnvSuspiciousWarningsDeterministic(f *testing.F) {
 			}
 		}
 
-		w1 := envSuspiciousWarnings(parts)
-		w2 := envSuspiciousWarnings(parts)
+		w1 := messages(envSuspiciousWarnings(parts))
+		w2 := messages(envSuspiciousWarnings(parts))
 		if !slices.Equal(w1, w2) {
 			t.Fatalf("expected deterministic output, got %v vs %v", w1, w2)
 		}
@@ -178,7 +179,7 @@ func TestEnvSuspiciousWarnings(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := envSuspiciousWarnings(tt.env)
+			got := messages(envSuspiciousWarnings(tt.env))
 			if !slices.Equal(got, tt.want) {
 				t.Fatalf("got %v, want %v", got, tt.want)
 			}
@@ -222,7 +223,7 @@ func TestWarningsDetectsDeletedExecutable(t *testing.T) {
 		},
 	}
 
-	warnings := Warnings(p, 0)
+	warnings := messages(Warnings(p, 0))
 	want := "Process is running from a deleted binary (potential library injection or pending update)"
 	if !slices.Contains(warnings, want) {
 		t.Fatalf("expected deleted binary warning, got: %v", warnings)
@@ -3,13 +3,14 @@
 package source
 
 import (
+	"context"
 	"strings"
 
 	"github.com/pranshuparmar/witr/internal/launchd"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
-func detectLaunchd(ancestry []model.Process) *model.Source {
+func detectLaunchd(ctx context.Context, ancestry []model.Process) *model.Source {
 	// Check if the ancestry includes launchd (PID 1)
 	hasLaunchd := false
 	for _, p := range ancestry {
@@ -29,8 +30,12 @@ func detectLaunchd(ancestry []model.Process) *model.Source {
 	}
 	target := ancestry[len(ancestry)-1]
 
-	// Try to get detailed launchd info for the target process
+	// Try to get detailed launchd info for the target process, unless there
+	// is no time left to ask launchctl.
 	info, err := launchd.GetLaunchdInfo(target.PID)
+	if ctx.Err() != nil {
+		err = ctx.Err()
+	}
 	if err != nil {
 		// Fall back to basic launchd detection
 		return &model.Source{
@@ -2,8 +2,12 @@
 
 package source
 
-import "github.com/pranshuparmar/witr/pkg/model"
+import (
+	"context"
 
-func detectLaunchd(_ []model.Process) *model.Source {
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func detectLaunchd(_ context.Context, _ []model.Process) *model.Source {
 	return nil
 }
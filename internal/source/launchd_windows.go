@@ -2,8 +2,12 @@
 
 package source
 
-import "github.com/pranshuparmar/witr/pkg/model"
+import (
+	"context"
 
-func detectLaunchd(ancestry []model.Process) *model.Source {
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func detectLaunchd(_ context.Context, ancestry []model.Process) *model.Source {
 	return nil
 }
@@ -0,0 +1,177 @@
+//go:build linux
+
+package source
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os/exec"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// journalLookback is how many of a unit's latest journal entries are
+	// searched for its last exit; journalKept of them are kept.
+	journalLookback = 100
+	journalKept     = 5
+	// journalTimeout bounds journalctl, which can take a while on a large
+	// journal.
+	journalTimeout = 3 * time.Second
+)
+
+// exitCodes are the values of ExecMainCode, a CLD_* code from waitid(2).
+var exitCodes = map[int32]string{1: "exited", 2: "killed", 3: "dumped"}
+
+// unitLifecycle reads a service's recent starts and exits from its unit and
+// service properties. The journal is only read when there is something to
+// explain, a restart or a failed result; it is where the exit that caused
+// a restart is recorded, since systemd resets ExecMainStatus when the new
+// main process starts.
+func unitLifecycle(ctx context.Context, unitName string, mgr unitManager, unit, svc map[string]interface{}) *model.UnitLifecycle {
+	lc := &model.UnitLifecycle{
+		Result:      stringProp(svc, "Result"),
+		ActiveSince: usecToTime(uint64Prop(unit, "ActiveEnterTimestamp")),
+		StartedAt:   usecToTime(uint64Prop(unit, "InactiveExitTimestamp")),
+	}
+	if uint32Prop(svc, "NRestarts") > 0 || (lc.Result != "" && lc.Result != "success") {
+		records := readJournal(ctx, unitName, mgr)
+		lc.LastExit = lastExit(records)
+		if len(records) > journalKept {
+			records = records[len(records)-journalKept:]
+		}
+		for _, r := range records {
+			lc.Journal = append(lc.Journal, r.entry)
+		}
+	}
+	if lc.LastExit == nil {
+		lc.LastExit = mainExit(svc, lc.Result)
+	}
+	return lc
+}
+
+// mainExit is the last exit of the main process as the service properties
+// record it, or nil while the main process it describes is running.
+func mainExit(svc map[string]interface{}, result string) *model.UnitExit {
+	code, ok := exitCodes[int32Prop(svc, "ExecMainCode")]
+	at := usecToTime(uint64Prop(svc, "ExecMainExitTimestamp"))
+	if !ok || at.IsZero() {
+		return nil
+	}
+	exit := &model.UnitExit{Time: at, Code: code, Status: int(int32Prop(svc, "ExecMainStatus"))}
+	exit.Signal = exitSignal(code, exit.Status)
+	if result != "success" {
+		exit.Result = result
+	}
+	return exit
+}
+
+// journalRecord is a journal entry with the structured fields systemd logs
+// about a unit's main process and result.
+type journalRecord struct {
+	entry      model.JournalEntry
+	exitCode   string
+	exitStatus string
+	unitResult string
+}
+
+// readJournal returns the unit's latest journal entries, oldest first, or
+// nil when journalctl is missing or the journal cannot be read.
+func readJournal(ctx context.Context, unitName string, mgr unitManager) []journalRecord {
+	if _, err := exec.LookPath("journalctl"); err != nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, journalTimeout)
+	defer cancel()
+	args := append(mgr.journalMatch(unitName), "--output", "json",
+		"--lines", strconv.Itoa(journalLookback), "--no-pager", "--quiet")
+	out, err := exec.CommandContext(ctx, "journalctl", args...).Output()
+	if err != nil {
+		return nil
+	}
+	return parseJournal(out)
+}
+
+// parseJournal reads journalctl's JSON output, one object per line.
+// Entries that do not parse are skipped.
+func parseJournal(data []byte) []journalRecord {
+	var records []journalRecord
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		var fields map[string]interface{}
+		if json.Unmarshal(sc.Bytes(), &fields) != nil {
+			continue
+		}
+		usec, err := strconv.ParseUint(journalField(fields, "__REALTIME_TIMESTAMP"), 10, 64)
+		if err != nil {
+			continue
+		}
+		r := journalRecord{
+			entry:      model.JournalEntry{Time: usecToTime(usec), Message: journalField(fields, "MESSAGE")},
+			exitCode:   journalField(fields, "EXIT_CODE"),
+			exitStatus: journalField(fields, "EXIT_STATUS"),
+			unitResult: journalField(fields, "UNIT_RESULT"),
+		}
+		r.entry.Priority, _ = strconv.Atoi(journalField(fields, "PRIORITY"))
+		r.entry.PID, _ = strconv.Atoi(journalField(fields, "_PID"))
+		records = append(records, r)
+	}
+	return records
+}
+
+// journalField returns a field of a journal entry as text. journalctl
+// writes a field that is not valid UTF-8 as an array of bytes.
+func journalField(fields map[string]interface{}, key string) string {
+	switch v := fields[key].(type) {
+	case string:
+		return v
+	case []interface{}:
+		b := make([]byte, 0, len(v))
+		for _, e := range v {
+			n, ok := e.(float64)
+			if !ok {
+				return ""
+			}
+			b = append(b, byte(n))
+		}
+		return string(b)
+	}
+	return ""
+}
+
+// lastExit returns the last exit of the main process in records, with the
+// result systemd gave the unit after it.
+func lastExit(records []journalRecord) *model.UnitExit {
+	var exit *model.UnitExit
+	for _, r := range records {
+		switch {
+		case r.exitCode != "":
+			status, _ := strconv.Atoi(r.exitStatus)
+			exit = &model.UnitExit{Time: r.entry.Time, Code: r.exitCode, Status: status}
+			exit.Signal = exitSignal(r.exitCode, status)
+		case r.unitResult != "" && exit != nil && exit.Result == "":
+			exit.Result = r.unitResult
+		}
+	}
+	return exit
+}
+
+// exitSignal names the signal that ended a killed or dumped process.
+func exitSignal(code string, status int) string {
+	if code != "killed" && code != "dumped" {
+		return ""
+	}
+	return unix.SignalName(syscall.Signal(status))
+}
+
+func int32Prop(m map[string]interface{}, key string) int32 {
+	n, _ := m[key].(int32)
+	return n
+}
@@ -0,0 +1,75 @@
+//go:build linux
+
+package source
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// A redis unit killed for running out of memory and restarted, as
+// journalctl -o json prints it.
+const redisJournal = `{"__REALTIME_TIMESTAMP":"1760000000000000","PRIORITY":"6","_PID":"48100","MESSAGE":"Ready to accept connections tcp"}
+{"__REALTIME_TIMESTAMP":"1760000100000000","PRIORITY":"5","_PID":"1","MESSAGE":"redis.service: Main process exited, code=killed, status=9/KILL","EXIT_CODE":"killed","EXIT_STATUS":"9"}
+{"__REALTIME_TIMESTAMP":"1760000100000100","PRIORITY":"4","_PID":"1","MESSAGE":"redis.service: Failed with result 'oom-kill'.","UNIT_RESULT":"oom-kill"}
+not json
+{"PRIORITY":"6","MESSAGE":"no timestamp"}
+{"__REALTIME_TIMESTAMP":"1760000105000000","PRIORITY":"6","_PID":"1","MESSAGE":"redis.service: Scheduled restart job, restart counter is at 14."}
+{"__REALTIME_TIMESTAMP":"1760000105500000","PRIORITY":"6","_PID":"48211","MESSAGE":[104,105,255]}
+`
+
+func TestParseJournalAndLastExit(t *testing.T) {
+	t.Parallel()
+
+	records := parseJournal([]byte(redisJournal))
+	if len(records) != 5 {
+		t.Fatalf("parsed %d records, want 5: %+v", len(records), records)
+	}
+	if got := records[4].entry; got.Message != "hi\xff" || got.PID != 48211 || got.Priority != 6 {
+		t.Errorf("a binary message = %+v", got)
+	}
+
+	want := &model.UnitExit{Time: time.UnixMicro(1760000100000000), Code: "killed", Status: 9, Signal: "SIGKILL", Result: "oom-kill"}
+	if got := lastExit(records); !reflect.DeepEqual(got, want) {
+		t.Errorf("lastExit = %+v, want %+v", got, want)
+	}
+	// A result logged without an exit before it belongs to no exit.
+	if got := lastExit(records[2:]); got != nil {
+		t.Errorf("lastExit without an exit = %+v, want nil", got)
+	}
+}
+
+func TestUnitLifecycleFromProperties(t *testing.T) {
+	t.Parallel()
+
+	unit := map[string]interface{}{
+		"ActiveEnterTimestamp":  uint64(1760000105600000),
+		"InactiveExitTimestamp": uint64(1760000105000000),
+	}
+	// A running service that never restarted: its main process has not
+	// exited, and the journal is not read.
+	running := map[string]interface{}{"Result": "success", "NRestarts": uint32(0)}
+	lc := unitLifecycle(context.Background(), "redis.service", unitManager{}, unit, running)
+	if lc.Result != "success" || lc.LastExit != nil || lc.Journal != nil {
+		t.Errorf("running unit = %+v", lc)
+	}
+	if !lc.ActiveSince.Equal(time.UnixMicro(1760000105600000)) || !lc.StartedAt.Equal(time.UnixMicro(1760000105000000)) {
+		t.Errorf("timestamps = %v, %v", lc.ActiveSince, lc.StartedAt)
+	}
+
+	// A failed service keeps its main process's exit.
+	failed := map[string]interface{}{
+		"Result":                "core-dump",
+		"ExecMainCode":          int32(3),
+		"ExecMainStatus":        int32(11),
+		"ExecMainExitTimestamp": uint64(1760000100000000),
+	}
+	want := &model.UnitExit{Time: time.UnixMicro(1760000100000000), Code: "dumped", Status: 11, Signal: "SIGSEGV", Result: "core-dump"}
+	if got := mainExit(failed, "core-dump"); !reflect.DeepEqual(got, want) {
+		t.Errorf("mainExit = %+v, want %+v", got, want)
+	}
+}
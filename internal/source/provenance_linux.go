@@ -0,0 +1,112 @@
+//go:build linux
+
+package source
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// maxActivationDepth bounds the walk up a unit's reverse dependencies.
+const maxActivationDepth = 8
+
+// bootTargets are the targets a boot pulls in; an activation chain that
+// reaches one has found why the unit runs.
+var bootTargets = []string{"default.target", "graphical.target", "multi-user.target", "sysinit.target", "basic.target"}
+
+// unitProps fetches a unit's properties.
+type unitProps func(unit string) (map[string]interface{}, error)
+
+// systemdUnit reads a unit's configuration from its unit and service
+// properties (svc is nil for a unit that is not a service), and walks its
+// reverse dependencies with props to find what activated it.
+func systemdUnit(unitName string, unit, svc map[string]interface{}, props unitProps) *model.SystemdUnit {
+	u := &model.SystemdUnit{
+		ExecStart: execStart(svc["ExecStart"]),
+		User:      stringProp(svc, "User"),
+		Group:     stringProp(svc, "Group"),
+		Restart:   stringProp(svc, "Restart"),
+		DropIns:   stringsProp(unit, "DropInPaths"),
+		FileState: stringProp(unit, "UnitFileState"),
+	}
+	u.ActivatedBy = activationChain(unitName, unit, props)
+	return u
+}
+
+// execStart renders an ExecStart value, which D-Bus delivers as an array of
+// (path, argv, ignore-failure, timestamps..., pid, code, status), as one
+// command line per entry.
+func execStart(v interface{}) []string {
+	entries, _ := v.([][]interface{})
+	var out []string
+	for _, e := range entries {
+		if len(e) < 2 {
+			continue
+		}
+		if argv, ok := e[1].([]string); ok && len(argv) > 0 {
+			out = append(out, strings.Join(argv, " "))
+		}
+	}
+	return out
+}
+
+// activationChain walks from a unit up its reverse dependencies to what
+// activated it, and returns the chain from the top down. At each step it
+// follows TriggeredBy first, since a triggered unit was started by its
+// .socket, .path or .timer whatever else wants it, then RequiredBy and
+// WantedBy. The walk stops at a trigger unit, a boot target, or a unit
+// nothing depends on.
+func activationChain(unitName string, unit map[string]interface{}, props unitProps) []model.Activation {
+	var chain []model.Activation
+	seen := map[string]bool{unitName: true}
+	for range maxActivationDepth {
+		parent, relation := activator(unit, seen)
+		if parent == "" {
+			break
+		}
+		seen[parent] = true
+		chain = append(chain, model.Activation{Unit: parent, Relation: relation})
+		if relation == "triggers" || slices.Contains(bootTargets, parent) {
+			break
+		}
+		next, err := props(parent)
+		if err != nil {
+			break
+		}
+		unit = next
+	}
+	slices.Reverse(chain)
+	return chain
+}
+
+// activator picks the unit that pulled in a unit with the given
+// properties, and how: a trigger, then a boot target, then the first other
+// unit requiring or wanting it.
+func activator(unit map[string]interface{}, seen map[string]bool) (string, string) {
+	unseen := func(units []string) []string {
+		return slices.DeleteFunc(slices.Clone(units), func(u string) bool { return seen[u] })
+	}
+	if triggers := unseen(stringsProp(unit, "TriggeredBy")); len(triggers) > 0 {
+		return triggers[0], "triggers"
+	}
+	for _, rel := range []struct{ prop, relation string }{{"RequiredBy", "requires"}, {"WantedBy", "wants"}} {
+		for _, u := range unseen(stringsProp(unit, rel.prop)) {
+			if slices.Contains(bootTargets, u) {
+				return u, rel.relation
+			}
+		}
+	}
+	for _, rel := range []struct{ prop, relation string }{{"RequiredBy", "requires"}, {"WantedBy", "wants"}} {
+		if units := unseen(stringsProp(unit, rel.prop)); len(units) > 0 {
+			return units[0], rel.relation
+		}
+	}
+	return "", ""
+}
+
+func stringsProp(m map[string]interface{}, key string) []string {
+	s, _ := m[key].([]string)
+	return s
+}
@@ -0,0 +1,106 @@
+//go:build linux
+
+package source
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// fakeUnits serves unit properties from a table, as the bus would.
+func fakeUnits(units map[string]map[string]interface{}) unitProps {
+	return func(name string) (map[string]interface{}, error) {
+		if u, ok := units[name]; ok {
+			return u, nil
+		}
+		return nil, errors.New("no such unit")
+	}
+}
+
+func TestSystemdUnit(t *testing.T) {
+	t.Parallel()
+
+	unit := map[string]interface{}{
+		"DropInPaths":   []string{"/etc/systemd/system/nginx.service.d/override.conf"},
+		"UnitFileState": "enabled",
+		"WantedBy":      []string{"multi-user.target"},
+	}
+	svc := map[string]interface{}{
+		"ExecStart": [][]interface{}{
+			{"/usr/sbin/nginx", []string{"/usr/sbin/nginx", "-g", "daemon on; master_process on;"}, false, uint64(0)},
+		},
+		"User":    "www-data",
+		"Group":   "www-data",
+		"Restart": "on-failure",
+	}
+	want := &model.SystemdUnit{
+		ExecStart:   []string{"/usr/sbin/nginx -g daemon on; master_process on;"},
+		User:        "www-data",
+		Group:       "www-data",
+		Restart:     "on-failure",
+		DropIns:     []string{"/etc/systemd/system/nginx.service.d/override.conf"},
+		FileState:   "enabled",
+		ActivatedBy: []model.Activation{{Unit: "multi-user.target", Relation: "wants"}},
+	}
+	if got := systemdUnit("nginx.service", unit, svc, fakeUnits(nil)); !reflect.DeepEqual(got, want) {
+		t.Errorf("systemdUnit =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// A scope has no service properties.
+	got := systemdUnit("session-4.scope", map[string]interface{}{"UnitFileState": "transient"}, nil, fakeUnits(nil))
+	if got.FileState != "transient" || got.ExecStart != nil || got.ActivatedBy != nil {
+		t.Errorf("systemdUnit(scope) = %+v", got)
+	}
+}
+
+func TestActivationChain(t *testing.T) {
+	t.Parallel()
+
+	units := map[string]map[string]interface{}{
+		"app-deps.service": {"RequiredBy": []string{"app.target"}},
+		"app.target":       {"WantedBy": []string{"multi-user.target", "graphical.target"}},
+		"loop-a.service":   {"WantedBy": []string{"loop-b.service"}},
+		"loop-b.service":   {"WantedBy": []string{"loop-a.service"}},
+	}
+	tests := []struct {
+		name string
+		unit map[string]interface{}
+		want []model.Activation
+	}{
+		{
+			// Socket activation wins over whatever also wants the service.
+			"triggered",
+			map[string]interface{}{"TriggeredBy": []string{"docker.socket"}, "WantedBy": []string{"multi-user.target"}},
+			[]model.Activation{{Unit: "docker.socket", Relation: "triggers"}},
+		},
+		{
+			// A boot target is preferred to another unit wanting it.
+			"boot target",
+			map[string]interface{}{"WantedBy": []string{"other.service", "multi-user.target"}},
+			[]model.Activation{{Unit: "multi-user.target", Relation: "wants"}},
+		},
+		{
+			"walked up",
+			map[string]interface{}{"WantedBy": []string{"app-deps.service"}},
+			[]model.Activation{
+				{Unit: "multi-user.target", Relation: "wants"},
+				{Unit: "app.target", Relation: "requires"},
+				{Unit: "app-deps.service", Relation: "wants"},
+			},
+		},
+		{
+			"cycle",
+			map[string]interface{}{"WantedBy": []string{"loop-a.service"}},
+			[]model.Activation{{Unit: "loop-b.service", Relation: "wants"}, {Unit: "loop-a.service", Relation: "wants"}},
+		},
+		{"nothing", map[string]interface{}{}, nil},
+	}
+	for _, tt := range tests {
+		if got := activationChain("x.service", tt.unit, fakeUnits(units)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: activationChain = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -2,8 +2,12 @@
 
 package source
 
-import "github.com/pranshuparmar/witr/pkg/model"
+import (
+	"context"
 
-func detectWindowsService(ancestry []model.Process) *model.Source {
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func detectWindowsService(_ context.Context, ancestry []model.Process) *model.Source {
 	return nil
 }
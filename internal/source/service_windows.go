@@ -3,19 +3,20 @@
 package source
 
 import (
+	"context"
 	"os/exec"
 	"strings"
 
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
-func detectWindowsService(ancestry []model.Process) *model.Source {
+func detectWindowsService(ctx context.Context, ancestry []model.Process) *model.Source {
 	// 1. Check for explicit service name in process metadata (prioritize target)
 	for i := len(ancestry) - 1; i >= 0; i-- {
 		p := ancestry[i]
 		if p.Service != "" {
 			registryKey := `HKLM\SYSTEM\CurrentControlSet\Services\` + p.Service
-			description := resolveWindowsServiceDescription(p.Service)
+			description := resolveWindowsServiceDescription(ctx, p.Service)
 
 			return &model.Source{
 				Type:        model.SourceWindowsService,
@@ -51,7 +52,7 @@ func detectWindowsService(ancestry []model.Process) *model.Source {
 			name := strings.TrimSuffix(target.Command, ".exe")
 
 			registryKey := `HKLM\SYSTEM\CurrentControlSet\Services\` + name
-			description := resolveWindowsServiceDescription(name)
+			description := resolveWindowsServiceDescription(ctx, name)
 
 			return &model.Source{
 				Type:        model.SourceWindowsService,
@@ -68,12 +69,12 @@ func detectWindowsService(ancestry []model.Process) *model.Source {
 	return nil
 }
 
-func resolveWindowsServiceDescription(serviceName string) string {
+func resolveWindowsServiceDescription(ctx context.Context, serviceName string) string {
 	if _, err := exec.LookPath("sc"); err != nil {
 		return ""
 	}
 
-	cmd := exec.Command("sc", "GetDisplayName", serviceName)
+	cmd := exec.CommandContext(ctx, "sc", "GetDisplayName", serviceName)
 	out, _ := cmd.Output()
 
 	output := string(out)
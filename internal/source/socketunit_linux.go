@@ -0,0 +1,180 @@
+//go:build linux
+
+package source
+
+import (
+	"context"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	sd "github.com/coreos/go-systemd/v22/dbus"
+	"github.com/pranshuparmar/witr/internal/procfs"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// SocketActivation returns the active systemd socket unit listening on
+// port, the service it triggers and whether that service is running, or nil
+// when no socket unit listens on the port. proto, "tcp" or "udp", limits the
+// match to the socket unit's stream or datagram listeners; empty matches
+// both. It asks systemd over D-Bus, so it needs the live system.
+func SocketActivation(ctx context.Context, port int, proto string) (*model.SocketActivation, error) {
+	if port <= 0 || !procfs.Live() || !IsSystemdRunning() {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, dbusTimeout)
+	defer cancel()
+
+	conn, err := sd.NewSystemConnectionContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// One listing gives both the active socket units and the active
+	// services, so only a socket's Listen property is read per unit.
+	units, err := conn.ListUnitsByPatternsContext(ctx, []string{"active"}, []string{"*.socket", "*.service"})
+	if err != nil {
+		return nil, err
+	}
+	var services []string
+	for _, u := range units {
+		if strings.HasSuffix(u.Name, ".service") {
+			services = append(services, u.Name)
+		}
+	}
+	for _, u := range units {
+		if !strings.HasSuffix(u.Name, ".socket") {
+			continue
+		}
+		prop, err := conn.GetUnitTypePropertyContext(ctx, u.Name, "Socket", "Listen")
+		if err != nil {
+			continue
+		}
+		ls := listeners(prop.Value.Value())
+		if !slices.ContainsFunc(ls, func(l listener) bool { return l.serves(port, proto) }) {
+			continue
+		}
+
+		act := &model.SocketActivation{Socket: u.Name}
+		for _, l := range ls {
+			act.Listen = append(act.Listen, l.addr)
+		}
+		if p, err := conn.GetUnitTypePropertyContext(ctx, u.Name, "Socket", "Accept"); err == nil {
+			act.Accept, _ = p.Value.Value().(bool)
+		}
+		var triggers []string
+		if p, err := conn.GetUnitPropertyContext(ctx, u.Name, "Triggers"); err == nil {
+			triggers, _ = p.Value.Value().([]string)
+		}
+		act.Service = triggeredService(u.Name, act.Accept, triggers)
+		act.MainPIDs = serviceMainPIDs(ctx, conn, act.Service, services)
+		act.Running = len(act.MainPIDs) > 0
+		return act, ctx.Err()
+	}
+	return nil, ctx.Err()
+}
+
+// listener is one entry of a socket unit's Listen value: its type, such as
+// "Stream" or "Datagram", and its address.
+type listener struct {
+	kind, addr string
+}
+
+// listeners reads a socket unit's Listen value, which D-Bus delivers as an
+// array of (type, address): ("Stream", "0.0.0.0:631").
+func listeners(v interface{}) []listener {
+	entries, _ := v.([][]interface{})
+	var out []listener
+	for _, e := range entries {
+		if len(e) < 2 {
+			continue
+		}
+		kind, _ := e[0].(string)
+		if addr, ok := e[1].(string); ok && addr != "" {
+			out = append(out, listener{kind: kind, addr: addr})
+		}
+	}
+	return out
+}
+
+// serves reports whether l listens on port over proto: "tcp" is a Stream
+// listener and "udp" a Datagram one, and empty takes either.
+func (l listener) serves(port int, proto string) bool {
+	if listenPort(l.addr) != port {
+		return false
+	}
+	switch proto {
+	case "tcp":
+		return l.kind == "Stream"
+	case "udp":
+		return l.kind == "Datagram"
+	}
+	return l.kind == "Stream" || l.kind == "Datagram"
+}
+
+// listenPort returns the port of a Listen address: a bare "631", which
+// systemd listens on for every address, or "host:port". A unix path, a
+// netlink or vsock address and a FIFO have none, and give 0.
+func listenPort(addr string) int {
+	if n, err := strconv.Atoi(addr); err == nil {
+		return n
+	}
+	if strings.HasPrefix(addr, "/") || strings.HasPrefix(addr, "@") {
+		return 0
+	}
+	_, p, err := net.SplitHostPort(addr)
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(p)
+	return n
+}
+
+// triggeredService returns the service a socket unit starts: the first
+// service among its Triggers, or else the unit systemd derives from the
+// socket's name, foo.service or, with Accept=yes, the template foo@.service.
+func triggeredService(socket string, accept bool, triggers []string) string {
+	for _, t := range triggers {
+		if strings.HasSuffix(t, ".service") {
+			return t
+		}
+	}
+	base := strings.TrimSuffix(socket, ".socket")
+	if accept {
+		return base + "@.service"
+	}
+	return base + ".service"
+}
+
+// serviceMainPIDs returns the main PIDs of service if it is among the
+// active services, or of its active instances if it is a template, sorted.
+func serviceMainPIDs(ctx context.Context, conn *sd.Conn, service string, active []string) []int {
+	var pids []int
+	for _, u := range active {
+		if !instanceOf(u, service) {
+			continue
+		}
+		prop, err := conn.GetUnitTypePropertyContext(ctx, u, "Service", "MainPID")
+		if err != nil {
+			continue
+		}
+		if pid, _ := prop.Value.Value().(uint32); pid > 0 {
+			pids = append(pids, int(pid))
+		}
+	}
+	slices.Sort(pids)
+	return pids
+}
+
+// instanceOf reports whether unit is service or, when service is a template
+// such as sshd@.service, one of its instances.
+func instanceOf(unit, service string) bool {
+	if unit == service {
+		return true
+	}
+	prefix, suffix, ok := strings.Cut(service, "@.")
+	return ok && strings.HasPrefix(unit, prefix+"@") && strings.HasSuffix(unit, "."+suffix)
+}
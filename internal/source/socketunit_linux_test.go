@@ -0,0 +1,110 @@
+//go:build linux
+
+package source
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestListeners(t *testing.T) {
+	t.Parallel()
+
+	listen := [][]interface{}{
+		{"Stream", "0.0.0.0:631"},
+		{"Stream", "[::]:631"},
+		{"Stream", "/run/cups/cups.sock"},
+		{"Datagram"},
+	}
+	want := []listener{{"Stream", "0.0.0.0:631"}, {"Stream", "[::]:631"}, {"Stream", "/run/cups/cups.sock"}}
+	if got := listeners(listen); !reflect.DeepEqual(got, want) {
+		t.Errorf("listeners = %q, want %q", got, want)
+	}
+	if got := listeners(nil); got != nil {
+		t.Errorf("listeners(nil) = %q", got)
+	}
+}
+
+func TestListenerServes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		l     listener
+		port  int
+		proto string
+		want  bool
+	}{
+		{listener{"Stream", "0.0.0.0:53"}, 53, "", true},
+		{listener{"Stream", "0.0.0.0:53"}, 53, "tcp", true},
+		{listener{"Stream", "0.0.0.0:53"}, 53, "udp", false},
+		{listener{"Datagram", "[::]:53"}, 53, "udp", true},
+		{listener{"Datagram", "[::]:53"}, 53, "tcp", false},
+		{listener{"Stream", "0.0.0.0:53"}, 54, "", false},
+		{listener{"SequentialPacket", "0.0.0.0:53"}, 53, "", false},
+	}
+	for _, tt := range tests {
+		if got := tt.l.serves(tt.port, tt.proto); got != tt.want {
+			t.Errorf("%v.serves(%d, %q) = %v, want %v", tt.l, tt.port, tt.proto, got, tt.want)
+		}
+	}
+}
+
+func TestInstanceOf(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		unit, service string
+		want          bool
+	}{
+		{"cups.service", "cups.service", true},
+		{"sshd@3-10.0.0.1:22-10.0.0.2:5000.service", "sshd@.service", true},
+		{"sshd.service", "sshd@.service", false},
+		{"sshd-keygen@rsa.service", "sshd@.service", false},
+		{"cups-browsed.service", "cups.service", false},
+	} {
+		if got := instanceOf(tt.unit, tt.service); got != tt.want {
+			t.Errorf("instanceOf(%q, %q) = %v, want %v", tt.unit, tt.service, got, tt.want)
+		}
+	}
+}
+
+func TestListenPort(t *testing.T) {
+	t.Parallel()
+
+	for addr, want := range map[string]int{
+		"22":                  22,
+		"0.0.0.0:631":         631,
+		"127.0.0.1:8080":      8080,
+		"[::]:22":             22,
+		"[::1]:9090":          9090,
+		"/run/cups/cups.sock": 0,
+		"@/org/example/bus":   0,
+		"vsock:2:1234":        0,
+		"route 0":             0,
+	} {
+		if got := listenPort(addr); got != want {
+			t.Errorf("listenPort(%q) = %d, want %d", addr, got, want)
+		}
+	}
+}
+
+func TestTriggeredService(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		socket   string
+		accept   bool
+		triggers []string
+		want     string
+	}{
+		{"cups.socket", false, []string{"cups.path", "cups.service"}, "cups.service"},
+		{"docker.socket", false, nil, "docker.service"},
+		{"sshd.socket", true, nil, "sshd@.service"},
+		{"git-daemon.socket", true, []string{"git-daemon@.service"}, "git-daemon@.service"},
+	}
+	for _, tt := range tests {
+		if got := triggeredService(tt.socket, tt.accept, tt.triggers); got != tt.want {
+			t.Errorf("triggeredService(%q, %v, %q) = %q, want %q", tt.socket, tt.accept, tt.triggers, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package source
+
+import (
+	"context"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// SocketActivation returns nil: socket activation is a systemd feature.
+func SocketActivation(_ context.Context, _ int, _ string) (*model.SocketActivation, error) {
+	return nil, nil
+}
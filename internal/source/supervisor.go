@@ -4,40 +4,10 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/pranshuparmar/witr/internal/config"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
-var knownSupervisors = map[string]string{
-	"pm2":          "pm2",
-	"supervisord":  "supervisord",
-	"supervisor":   "supervisord",
-	"gunicorn":     "gunicorn",
-	"uwsgi":        "uwsgi",
-	"s6-supervise": "s6",
-	"s6":           "s6",
-	"s6-svscan":    "s6",
-	"runsv":        "runit",
-	"runit":        "runit",
-	"runit-init":   "runit",
-	"openrc":       "openrc",
-	"openrc-init":  "openrc",
-	"monit":        "monit",
-	"circusd":      "circus",
-	"circus":       "circus",
-	"systemd":      "systemd service",
-	"systemctl":    "systemd service",
-	"daemontools":  "daemontools",
-	"initctl":      "upstart",
-	"tini":         "tini",
-	"docker-init":  "docker-init",
-	"podman-init":  "podman-init",
-	"smf":          "smf",
-	"launchd":      "launchd",
-	"god":          "god",
-	"forever":      "forever",
-	"nssm":         "nssm",
-}
-
 func detectSupervisor(ancestry []model.Process) *model.Source {
 	// Check if there's a shell in the ancestry
 	hasShell := false
@@ -59,7 +29,7 @@ func detectSupervisor(ancestry []model.Process) *model.Source {
 			}
 		}
 
-		if label, ok := knownSupervisors[strings.ToLower(base)]; ok {
+		if label, ok := config.Current().Supervisors[strings.ToLower(base)]; ok {
 			if label == "init" && hasShell {
 				continue
 			}
@@ -80,7 +50,7 @@ func detectSupervisor(ancestry []model.Process) *model.Source {
 }
 
 // matchCmdlineTokens extracts the executable basename and each argument token
-// from a command line, then looks up each against knownSupervisors by exact match.
+// from a command line, then looks up each against the configured supervisors by exact match.
 func matchCmdlineTokens(cmdline string, hasShell bool) string {
 	for _, token := range strings.Fields(strings.ToLower(cmdline)) {
 		// Skip flags and env assignments
@@ -88,7 +58,7 @@ func matchCmdlineTokens(cmdline string, hasShell bool) string {
 			continue
 		}
 		base := filepath.Base(token)
-		if label, ok := knownSupervisors[base]; ok {
+		if label, ok := config.Current().Supervisors[base]; ok {
 			if label == "init" && hasShell {
 				continue
 			}
@@ -2,9 +2,13 @@
 
 package source
 
-import "github.com/pranshuparmar/witr/pkg/model"
+import (
+	"context"
 
-func detectSystemd(_ []model.Process) *model.Source {
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func detectSystemd(_ context.Context, _ []model.Process) *model.Source {
 	// FreeBSD doesn't use systemd
 	return nil
 }
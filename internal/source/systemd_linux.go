@@ -6,12 +6,12 @@ import (
 	"context"
 	"fmt"
 	"math"
-	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	sd "github.com/coreos/go-systemd/v22/dbus"
+	"github.com/pranshuparmar/witr/internal/procfs"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
@@ -22,11 +22,11 @@ const dbusTimeout = 2 * time.Second
 // IsSystemdRunning checks whether systemd is actually the running init system.
 // This is the canonical check used by sd_booted() in libsystemd.
 func IsSystemdRunning() bool {
-	_, err := os.Stat("/run/systemd/system")
+	_, err := procfs.Stat("/run/systemd/system")
 	return err == nil
 }
 
-func detectSystemd(ancestry []model.Process) *model.Source {
+func detectSystemd(ctx context.Context, ancestry []model.Process) *model.Source {
 	// Verify systemd is actually the init system, not just that PID 1
 	// happens to be named "init" (which could be SysVinit, OpenRC, runit, etc.)
 	if !IsSystemdRunning() {
@@ -44,41 +44,46 @@ func detectSystemd(ancestry []model.Process) *model.Source {
 		return nil
 	}
 
-	// The unit name comes for free from the process cgroup; description, unit
-	// file, restart count and timer schedule are best-effort enrichment over
-	// systemd's D-Bus API.
-	unitName := getUnitNameFromCgroup(ancestry[len(ancestry)-1].PID)
+	// The unit name and the manager it belongs to come for free from the
+	// process cgroup; description, unit file, restart count and timer
+	// schedule are best-effort enrichment over systemd's D-Bus API.
+	unitName, mgr := unitFromCgroup(ancestry[len(ancestry)-1].PID)
 
 	src := &model.Source{
-		Type:    model.SourceSystemd,
-		Name:    unitName,
-		Details: map[string]string{},
+		Type:        model.SourceSystemd,
+		Name:        unitName,
+		UserManager: mgr.String(),
+		Details:     map[string]string{},
 	}
-	enrichFromSystemd(src, unitName)
+	enrichFromSystemd(ctx, src, unitName, mgr)
 	return src
 }
 
-// enrichFromSystemd fills Description, UnitFile, NRestarts and (for timer-
-// triggered services) the schedule via systemd's D-Bus API. Every step is
-// best-effort: a missing bus, a permission error, or an unloaded unit just
-// leaves the corresponding field empty rather than failing detection. This
-// replaces forking `systemctl show` (2-3 processes per report) with a single
-// short-lived D-Bus connection.
-func enrichFromSystemd(src *model.Source, unitName string) {
-	if unitName == "" {
+// enrichFromSystemd fills Description, UnitFile, NRestarts, the lifecycle,
+// the unit's configuration and activation chain, and (for timer-triggered
+// services) the schedule via the D-Bus API of mgr, the systemd instance
+// managing the unit. Every step is best-effort: a missing bus, a permission
+// error, or an unloaded unit just leaves the corresponding field empty
+// rather than failing detection. This replaces forking `systemctl show`
+// (2-3 processes per report) with a single short-lived D-Bus connection.
+func enrichFromSystemd(ctx context.Context, src *model.Source, unitName string, mgr unitManager) {
+	// The bus belongs to the running host, not to a proc root or snapshot.
+	if unitName == "" || !procfs.Live() {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), dbusTimeout)
+	parent := ctx
+	ctx, cancel := context.WithTimeout(ctx, dbusTimeout)
 	defer cancel()
 
-	conn, err := sd.NewSystemConnectionContext(ctx)
+	conn, err := mgr.connect(ctx)
 	if err != nil {
 		return // no usable bus — keep the cgroup-derived unit name only
 	}
 	defer conn.Close()
 
-	if unit, err := conn.GetUnitPropertiesContext(ctx, unitName); err == nil {
+	unit, err := conn.GetUnitPropertiesContext(ctx, unitName)
+	if err == nil {
 		src.Description = stringProp(unit, "Description")
 		if fp := stringProp(unit, "FragmentPath"); fp != "" {
 			src.UnitFile = fp
@@ -87,23 +92,31 @@ func enrichFromSystemd(src *model.Source, unitName string) {
 		}
 	}
 
+	var svc map[string]interface{}
 	if strings.HasSuffix(unitName, ".service") {
-		if svc, err := conn.GetUnitTypePropertiesContext(ctx, unitName, "Service"); err == nil {
+		if svc, err = conn.GetUnitTypePropertiesContext(ctx, unitName, "Service"); err == nil {
 			src.Details["NRestarts"] = strconv.FormatUint(uint64(uint32Prop(svc, "NRestarts")), 10)
+			src.Lifecycle = unitLifecycle(parent, unitName, mgr, unit, svc)
 		}
 		timerUnit := strings.TrimSuffix(unitName, ".service") + ".timer"
-		if sched := timerSchedule(ctx, conn, timerUnit); sched != "" {
-			src.Details["schedule"] = sched
-		}
+		timerSchedule(ctx, conn, timerUnit, src.Details)
+	}
+
+	if unit != nil {
+		src.Unit = systemdUnit(unitName, unit, svc, func(name string) (map[string]interface{}, error) {
+			return conn.GetUnitPropertiesContext(ctx, name)
+		})
 	}
 }
 
-// timerSchedule renders a "<spec>, last: …, next: …" line for a .timer unit,
-// or "" when the timer isn't loaded.
-func timerSchedule(ctx context.Context, conn *sd.Conn, timerUnit string) string {
+// timerSchedule records a .timer unit's schedule in details: its spec, and
+// when it last triggered and next elapses, as RFC 3339 times so that the
+// details of a result only change when the timer fires. Nothing is
+// recorded when the timer isn't loaded.
+func timerSchedule(ctx context.Context, conn *sd.Conn, timerUnit string, details map[string]string) {
 	tp, err := conn.GetUnitTypePropertiesContext(ctx, timerUnit, "Timer")
 	if err != nil {
-		return ""
+		return
 	}
 
 	spec := calendarSpec(tp["TimersCalendar"])
@@ -111,17 +124,16 @@ func timerSchedule(ctx context.Context, conn *sd.Conn, timerUnit string) string
 		spec = monotonicSpec(tp["TimersMonotonic"])
 	}
 	if spec == "" {
-		return ""
+		return
 	}
 
-	parts := []string{spec}
+	details["schedule"] = spec
 	if last := usecToTime(uint64Prop(tp, "LastTriggerUSec")); !last.IsZero() {
-		parts = append(parts, "last: "+formatRelativeTime(last))
+		details["last-trigger"] = last.Format(time.RFC3339)
 	}
 	if next := usecToTime(uint64Prop(tp, "NextElapseUSecRealtime")); !next.IsZero() {
-		parts = append(parts, "next: "+formatRelativeTime(next))
+		details["next-trigger"] = next.Format(time.RFC3339)
 	}
-	return strings.Join(parts, ", ")
 }
 
 // calendarSpec extracts the calendar expression from a TimersCalendar value,
@@ -218,60 +230,72 @@ func uint64Prop(m map[string]interface{}, key string) uint64 {
 	return n
 }
 
-// formatRelativeTime returns a human-friendly relative time string.
-func formatRelativeTime(t time.Time) string {
-	d := time.Since(t)
-	if d < 0 {
-		d = -d
-		switch {
-		case d < time.Minute:
-			return "in <1 min"
-		case d < time.Hour:
-			return fmt.Sprintf("in %d min", int(d.Minutes()))
-		case d < 24*time.Hour:
-			return fmt.Sprintf("in %dh", int(d.Hours()))
-		default:
-			return fmt.Sprintf("in %dd", int(d.Hours()/24))
-		}
-	}
-	switch {
-	case d < time.Minute:
-		return "<1 min ago"
-	case d < time.Hour:
-		return fmt.Sprintf("%d min ago", int(d.Minutes()))
-	case d < 24*time.Hour:
-		return fmt.Sprintf("%dh ago", int(d.Hours()))
-	default:
-		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
-	}
-}
-
-func getUnitNameFromCgroup(pid int) string {
-	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+// unitFromCgroup returns the systemd unit pid belongs to, from its cgroup,
+// and the manager of that unit.
+func unitFromCgroup(pid int) (string, unitManager) {
+	data, err := procfs.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
 	if err != nil {
-		return ""
+		return "", unitManager{}
 	}
+	return parseUnitCgroup(string(data))
+}
 
-	lines := strings.Split(string(data), "\n")
-	for _, line := range lines {
+// parseUnitCgroup reads /proc/<pid>/cgroup content down to the deepest
+// .service or .scope unit on the systemd hierarchy. A unit below
+// user@UID.service (user.slice/user-1000.slice/user@1000.service/app.slice/
+// foo.service) belongs to that user's manager; the manager's own process,
+// in its init.scope, belongs to user@UID.service.
+func parseUnitCgroup(content string) (string, unitManager) {
+	for _, line := range strings.Split(content, "\n") {
 		parts := strings.SplitN(line, ":", 3)
 		if len(parts) < 3 {
 			continue
 		}
-		controllers := parts[1]
-		path := parts[2]
-
-		if controllers == "" || strings.Contains(controllers, "systemd") {
-			path = strings.TrimSpace(path)
-			pathParts := strings.Split(path, "/")
+		controllers, path := parts[1], strings.TrimSpace(parts[2])
+		if controllers != "" && !strings.Contains(controllers, "systemd") {
+			continue
+		}
 
-			for i := len(pathParts) - 1; i >= 0; i-- {
-				part := pathParts[i]
-				if strings.HasSuffix(part, ".service") || strings.HasSuffix(part, ".scope") {
-					return part
+		var unit string
+		var mgr unitManager
+		for _, part := range strings.Split(path, "/") {
+			if !strings.HasSuffix(part, ".service") && !strings.HasSuffix(part, ".scope") {
+				continue
+			}
+			if !mgr.user {
+				if uid, ok := userManagerUID(part); ok {
+					unit = part
+					mgr = unitManager{user: true, uid: uid}
+					continue
 				}
 			}
+			unit = part
+		}
+		if unit == "" {
+			continue
+		}
+		if !mgr.user || unit == mgr.String() {
+			return unit, unitManager{}
 		}
+		if unit == "init.scope" {
+			return mgr.String(), unitManager{}
+		}
+		return unit, mgr
 	}
-	return ""
+	return "", unitManager{}
+}
+
+// userManagerUID reads the uid out of a user manager's unit name,
+// "user@1000.service".
+func userManagerUID(unit string) (int, bool) {
+	s, ok := strings.CutPrefix(unit, "user@")
+	if !ok {
+		return 0, false
+	}
+	s, ok = strings.CutSuffix(s, ".service")
+	if !ok {
+		return 0, false
+	}
+	uid, err := strconv.Atoi(s)
+	return uid, err == nil && uid >= 0
 }
@@ -5,6 +5,7 @@ package source
 import (
 	"math"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -69,38 +70,55 @@ func TestUsecToTime(t *testing.T) {
 	}
 }
 
-func TestFormatRelativeTime(t *testing.T) {
-	now := time.Now()
-	// Durations are buffered off the truncation boundaries so sub-second drift
-	// between now and the time.Since() call inside the function can't flip an
-	// "N min" bucket to "N-1".
-	cases := []struct {
-		d    time.Duration // offset from now; negative = past, positive = future
-		want string
+func TestUnitFromCgroupSelf(t *testing.T) {
+	// The result depends on the host's cgroup layout (a .scope, a .service, or
+	// nothing), so we only exercise the read+parse without asserting a value.
+	_, _ = unitFromCgroup(os.Getpid())
+
+	// PID 0 has no cgroup file, so the read fails and we get "".
+	if got, mgr := unitFromCgroup(0); got != "" || mgr.user {
+		t.Errorf("unitFromCgroup(0) = %q, %+v; want empty", got, mgr)
+	}
+}
+
+func TestParseUnitCgroup(t *testing.T) {
+	user := unitManager{user: true, uid: 1000}
+	tests := []struct {
+		name    string
+		content string
+		unit    string
+		mgr     unitManager
 	}{
-		{-30 * time.Second, "<1 min ago"},
-		{-(5*time.Minute + 30*time.Second), "5 min ago"},
-		{-(3*time.Hour + 30*time.Minute), "3h ago"},
-		{-50 * time.Hour, "2d ago"},
-		{30 * time.Second, "in <1 min"},
-		{5*time.Minute + 30*time.Second, "in 5 min"},
-		{3*time.Hour + 30*time.Minute, "in 3h"},
-		{50 * time.Hour, "in 2d"},
-	}
-	for _, tc := range cases {
-		if got := formatRelativeTime(now.Add(tc.d)); got != tc.want {
-			t.Errorf("formatRelativeTime(now%+v) = %q, want %q", tc.d, got, tc.want)
+		{"system service", "0::/system.slice/nginx.service", "nginx.service", unitManager{}},
+		{"login session", "0::/user.slice/user-1000.slice/session-2.scope", "session-2.scope", unitManager{}},
+		{"user service", "0::/user.slice/user-1000.slice/user@1000.service/app.slice/syncthing.service", "syncthing.service", user},
+		{"user app scope", "0::/user.slice/user-1000.slice/user@1000.service/app.slice/app-gnome-firefox-4242.scope", "app-gnome-firefox-4242.scope", user},
+		{"user service sub-cgroup", "0::/user.slice/user-1000.slice/user@1000.service/app.slice/podman.service/sub", "podman.service", user},
+		{"root's user manager", "0::/user.slice/user-0.slice/user@0.service/app.slice/backup.service", "backup.service", unitManager{user: true}},
+		{"user manager itself", "0::/user.slice/user-1000.slice/user@1000.service/init.scope", "user@1000.service", unitManager{}},
+		{"user manager cgroup", "0::/user.slice/user-1000.slice/user@1000.service", "user@1000.service", unitManager{}},
+		{"cgroup v1", "4:cpu,cpuacct:/\n1:name=systemd:/user.slice/user-1000.slice/user@1000.service/app.slice/syncthing.service", "syncthing.service", user},
+		{"no unit", "0::/", "", unitManager{}},
+	}
+	for _, tt := range tests {
+		unit, mgr := parseUnitCgroup(tt.content)
+		if unit != tt.unit || mgr != tt.mgr {
+			t.Errorf("%s: parseUnitCgroup = %q, %+v; want %q, %+v", tt.name, unit, mgr, tt.unit, tt.mgr)
 		}
 	}
 }
 
-func TestGetUnitNameFromCgroupSelf(t *testing.T) {
-	// The result depends on the host's cgroup layout (a .scope, a .service, or
-	// nothing), so we only exercise the read+parse without asserting a value.
-	_ = getUnitNameFromCgroup(os.Getpid())
-
-	// PID 0 has no cgroup file, so the read fails and we get "".
-	if got := getUnitNameFromCgroup(0); got != "" {
-		t.Errorf("getUnitNameFromCgroup(0) = %q, want empty", got)
+func TestUnitManager(t *testing.T) {
+	sys := unitManager{}
+	if sys.String() != "" || !reflect.DeepEqual(sys.journalMatch("nginx.service"), []string{"--unit", "nginx.service"}) {
+		t.Errorf("system manager = %q, %q", sys.String(), sys.journalMatch("nginx.service"))
+	}
+	user := unitManager{user: true, uid: 1000}
+	if user.String() != "user@1000.service" {
+		t.Errorf("user manager = %q, want user@1000.service", user.String())
+	}
+	want := []string{"_SYSTEMD_USER_UNIT=syncthing.service", "_UID=1000", "+", "USER_UNIT=syncthing.service", "_UID=1000"}
+	if got := user.journalMatch("syncthing.service"); !reflect.DeepEqual(got, want) {
+		t.Errorf("journalMatch = %q, want %q", got, want)
 	}
 }
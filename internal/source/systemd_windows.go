@@ -2,9 +2,13 @@
 
 package source
 
-import "github.com/pranshuparmar/witr/pkg/model"
+import (
+	"context"
 
-func detectSystemd(ancestry []model.Process) *model.Source {
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func detectSystemd(_ context.Context, ancestry []model.Process) *model.Source {
 	return nil
 }
 
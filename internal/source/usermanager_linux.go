@@ -0,0 +1,81 @@
+//go:build linux
+
+package source
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	sd "github.com/coreos/go-systemd/v22/dbus"
+	"github.com/godbus/dbus/v5"
+)
+
+// unitManager is the systemd instance a unit belongs to: the system
+// manager, or when user is set, the user manager (systemd --user) of uid,
+// which runs as user@UID.service.
+type unitManager struct {
+	user bool
+	uid  int
+}
+
+// String names the user manager's own unit, "user@1000.service", or is
+// empty for the system manager.
+func (m unitManager) String() string {
+	if !m.user {
+		return ""
+	}
+	return fmt.Sprintf("user@%d.service", m.uid)
+}
+
+// connect opens a D-Bus connection to the manager. A user manager is
+// reached over its user's session bus, or when witr may not use that bus,
+// over the manager's private socket, which also admits root.
+func (m unitManager) connect(ctx context.Context) (*sd.Conn, error) {
+	if !m.user {
+		return sd.NewSystemConnectionContext(ctx)
+	}
+	runtimeDir := fmt.Sprintf("/run/user/%d", m.uid)
+	conn, err := sd.NewConnection(func() (*dbus.Conn, error) {
+		return dialManager(ctx, "unix:path="+runtimeDir+"/bus", true)
+	})
+	if err == nil {
+		return conn, nil
+	}
+	// The private socket is peer-to-peer: there is no bus to say Hello to.
+	return sd.NewConnection(func() (*dbus.Conn, error) {
+		return dialManager(ctx, "unix:path="+runtimeDir+"/systemd/private", false)
+	})
+}
+
+// dialManager connects to address and authenticates as witr's own uid.
+func dialManager(ctx context.Context, address string, hello bool) (*dbus.Conn, error) {
+	conn, err := dbus.Dial(address, dbus.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.Auth([]dbus.Auth{dbus.AuthExternal(strconv.Itoa(os.Getuid()))}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if hello {
+		if err := conn.Hello(); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// journalMatch returns the journalctl arguments selecting a unit's entries.
+// journalctl --user-unit only matches the caller's own units, so a user
+// unit is matched by its fields: what it logged itself, or what its
+// manager logged about it.
+func (m unitManager) journalMatch(unitName string) []string {
+	if !m.user {
+		return []string{"--unit", unitName}
+	}
+	uid := "_UID=" + strconv.Itoa(m.uid)
+	return []string{"_SYSTEMD_USER_UNIT=" + unitName, uid, "+", "USER_UNIT=" + unitName, uid}
+}
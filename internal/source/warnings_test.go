@@ -1,11 +1,13 @@
 package source
 
 import (
+	"reflect"
 	"runtime"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/pranshuparmar/witr/internal/config"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
@@ -28,7 +30,19 @@ func wrap(p model.Process) []string {
 	parent := baseProc()
 	parent.PID = 1
 	parent.Command = "systemd"
-	return Warnings([]model.Process{parent, p}, 0, model.SourceSystemd)
+	return messages(Warnings([]model.Process{parent, p}, 0, model.SourceSystemd))
+}
+
+// messages returns the warnings' messages, nil for none.
+func messages(ws []model.Warning) []string {
+	if len(ws) == 0 {
+		return nil
+	}
+	out := make([]string, len(ws))
+	for i, w := range ws {
+		out[i] = w.Message
+	}
+	return out
 }
 
 func contains(haystack []string, needle string) bool {
@@ -136,7 +150,7 @@ func TestWarningsUnknownSupervisor(t *testing.T) {
 	t.Parallel()
 
 	p := baseProc()
-	got := Warnings([]model.Process{p}, 0, model.SourceUnknown)
+	got := messages(Warnings([]model.Process{p}, 0, model.SourceUnknown))
 	hasWarning := contains(got, "No known supervisor")
 	if runtime.GOOS == "windows" {
 		// Suppressed on Windows: ancestry truncates at orphaned processes, so
@@ -272,10 +286,10 @@ func TestWarningsRestartCount(t *testing.T) {
 
 	// The warning is driven by the real restart count (e.g. systemd NRestarts),
 	// not by the shape of the ancestry.
-	if got := Warnings(chain, 7, model.SourceSystemd); !contains(got, "restarted 7 times") {
+	if got := messages(Warnings(chain, 7, model.SourceSystemd)); !contains(got, "restarted 7 times") {
 		t.Errorf("expected restart warning for 7 restarts, got: %v", got)
 	}
-	if got := Warnings(chain, 5, model.SourceSystemd); contains(got, "restarted") {
+	if got := messages(Warnings(chain, 5, model.SourceSystemd)); contains(got, "restarted") {
 		t.Errorf("no restart warning expected for 5 restarts, got: %v", got)
 	}
 }
@@ -294,7 +308,139 @@ func TestWarningsBenignProcProducesNoSpurious(t *testing.T) {
 func TestWarningsEmptyInputReturnsNil(t *testing.T) {
 	t.Parallel()
 
-	if got := Warnings(nil, 0); got != nil {
+	if got := messages(Warnings(nil, 0)); got != nil {
 		t.Errorf("Warnings(nil) = %v, want nil", got)
 	}
 }
+
+// TestWarningsFollowConfig checks that the thresholds and tables come from the
+// active configuration. It swaps the package-wide config, so it must not run
+// in parallel.
+func TestWarningsFollowConfig(t *testing.T) {
+	prev := config.Current()
+	t.Cleanup(func() { config.Set(prev) })
+
+	c := config.Default()
+	c.Thresholds.Restarts = 1
+	c.Thresholds.Age = config.Duration(7 * 24 * time.Hour)
+	c.SuspiciousDirs = append(c.SuspiciousDirs, "/dev/shm")
+	c.DangerousCapabilities = append(c.DangerousCapabilities, "CAP_BPF")
+	c.EnvRules = append(c.EnvRules, config.EnvRule{Pattern: "LD_AUDIT", Warning: "Process sets LD_AUDIT"})
+	c.Supervisors["mysup"] = "my supervisor"
+	config.Set(c)
+
+	p := baseProc()
+	p.StartedAt = time.Now().Add(-10 * 24 * time.Hour)
+	p.WorkingDir = "/dev/shm"
+	p.Capabilities = []string{"CAP_BPF"}
+	p.Env = []string{"LD_AUDIT=/tmp/a.so"}
+	chain := []model.Process{{PID: 1, Command: "systemd"}, p}
+
+	got := messages(Warnings(chain, 2, model.SourceSystemd))
+	for _, want := range []string{"restarted 2 times", "over 7 days", "/dev/shm", "CAP_BPF", "LD_AUDIT"} {
+		if !contains(got, want) {
+			t.Errorf("expected a warning containing %q, got: %v", want, got)
+		}
+	}
+
+	src := detectSupervisor([]model.Process{{PID: 10, Command: "mysup"}, {PID: 11, Command: "worker"}})
+	if src == nil || src.Name != "my supervisor" {
+		t.Errorf("configured supervisor not detected: %+v", src)
+	}
+}
+
+func TestWarningsCodesAndEvidence(t *testing.T) {
+	t.Parallel()
+
+	p := baseProc()
+	p.User = "svc"
+	p.Capabilities = []string{"CAP_SYS_ADMIN", "CAP_NET_BIND_SERVICE"}
+	p.WorkingDir = "/tmp"
+	p.ExeDeleted = true
+	p.Exe = "/usr/bin/app"
+	p.Sockets = []model.Socket{{Protocol: "tcp", Address: "0.0.0.0", Port: 80, State: "LISTEN"}}
+	p.Env = []string{"LD_PRELOAD=/tmp/x.so"}
+	chain := []model.Process{{PID: 1, Command: "systemd"}, p}
+
+	got := map[model.WarningCode]model.Warning{}
+	for _, w := range Warnings(chain, 9, model.SourceSystemd) {
+		got[w.Code] = w
+	}
+	want := map[model.WarningCode]struct {
+		sev      model.Severity
+		evidence map[string]string
+	}{
+		model.WarnRestarts:      {model.SeverityMedium, map[string]string{"restarts": "9", "threshold": "5"}},
+		model.WarnPublicBind:    {model.SeverityMedium, map[string]string{"addresses": "0.0.0.0:80"}},
+		model.WarnDangerousCaps: {model.SeverityHigh, map[string]string{"capabilities": "CAP_SYS_ADMIN"}},
+		model.WarnSuspiciousCwd: {model.SeverityMedium, map[string]string{"dir": "/tmp"}},
+		model.WarnDeletedExe:    {model.SeverityHigh, map[string]string{"exe": "/usr/bin/app"}},
+		model.WarnLDPreload:     {model.SeverityHigh, map[string]string{"variables": "LD_PRELOAD"}},
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d warnings, want %d: %v", len(got), len(want), got)
+	}
+	for code, w := range want {
+		g, ok := got[code]
+		if !ok {
+			t.Errorf("missing %s", code)
+			continue
+		}
+		if g.Severity != w.sev || !reflect.DeepEqual(g.Evidence, w.evidence) {
+			t.Errorf("%s: severity %s, evidence %v; want %s, %v", code, g.Severity, g.Evidence, w.sev, w.evidence)
+		}
+	}
+}
+
+// TestWarningsSeverityOverride swaps the package-wide config, so it must not
+// run in parallel.
+func TestWarningsSeverityOverride(t *testing.T) {
+	prev := config.Current()
+	t.Cleanup(func() { config.Set(prev) })
+
+	c := config.Default()
+	c.Severities[model.WarnRoot] = model.SeverityHigh
+	config.Set(c)
+
+	p := baseProc()
+	p.User = "root"
+	ws := Warnings([]model.Process{{PID: 1, Command: "systemd"}, p}, 0, model.SourceSystemd)
+	if len(ws) != 1 || ws[0].Code != model.WarnRoot || ws[0].Severity != model.SeverityHigh {
+		t.Errorf("got %+v, want W_ROOT raised to high", ws)
+	}
+}
+
+func TestCgroupWarnings(t *testing.T) {
+	t.Parallel()
+
+	if got := CgroupWarnings(nil); got != nil {
+		t.Errorf("no cgroup should give no warnings, got %v", got)
+	}
+
+	cg := &model.CgroupContext{
+		Path:          "/system.slice/redis.service",
+		MemoryCurrent: 97 << 20, MemoryMax: 100 << 20,
+		PIDsCurrent: 12, PIDsMax: 100,
+		OOMEvents: 5, OOMKills: 3,
+	}
+	got := map[model.WarningCode]model.Warning{}
+	for _, w := range CgroupWarnings(cg) {
+		got[w.Code] = w
+	}
+	if len(got) != 2 {
+		t.Errorf("got %v, want the memory and OOM warnings", got)
+	}
+	if w := got[model.WarnCgroupMemory]; w.Message != "Cgroup memory at 97% of limit" || w.Evidence["max"] != "100MiB" || w.Evidence["threshold"] != "90%" {
+		t.Errorf("memory warning = %+v", w)
+	}
+	if w := got[model.WarnOOMKills]; w.Message != "Process cgroup has had 3 OOM kills" || w.Evidence["oom_kill"] != "3" {
+		t.Errorf("OOM warning = %+v", w)
+	}
+
+	// No limit, no usage warning, however much is used.
+	unlimited := &model.CgroupContext{Path: "/", MemoryCurrent: 8 << 30, PIDsCurrent: 95, PIDsMax: 100}
+	ws := CgroupWarnings(unlimited)
+	if len(ws) != 1 || ws[0].Code != model.WarnCgroupPIDs || ws[0].Message != "Cgroup tasks at 95% of pids limit" {
+		t.Errorf("got %+v, want only the pids warning", ws)
+	}
+}
@@ -0,0 +1,197 @@
+package suppress
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// baselineVersion is bumped when the baseline file changes incompatibly.
+const baselineVersion = 1
+
+// Baseline is the set of warnings a host has accepted, per process identity.
+type Baseline struct {
+	Version  int
+	Hostname string
+	SavedAt  time.Time
+	Entries  []Entry
+}
+
+// Entry is the warnings accepted for one process identity.
+type Entry struct {
+	Identity
+	Codes []model.WarningCode
+	// Warnings are the Subjects of the accepted warnings, which tell apart
+	// warnings with the same code: a public address, capability or variable
+	// the process did not have when the baseline was saved is not accepted.
+	// An entry saved before they were recorded accepts by code alone.
+	Warnings []string `json:",omitempty"`
+}
+
+// accepts reports whether e accepts w.
+func (e *Entry) accepts(w model.Warning) bool {
+	if e == nil || w.Code == "" {
+		return false
+	}
+	if len(e.Warnings) > 0 {
+		return slices.Contains(e.Warnings, w.Subject())
+	}
+	return slices.Contains(e.Codes, w.Code)
+}
+
+// DefaultBaselinePath is $XDG_STATE_HOME/witr/baseline.json, falling back to
+// ~/.local/state (or %LocalAppData% on Windows). It is empty when no home
+// directory is known.
+func DefaultBaselinePath() string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" && runtime.GOOS == "windows" {
+		dir = os.Getenv("LocalAppData")
+	}
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "witr", "baseline.json")
+}
+
+// LoadBaseline reads the baseline at path. A missing file is no baseline:
+// nil and no error.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if b.Version > baselineVersion {
+		return nil, fmt.Errorf("%s: baseline version %d is newer than this witr understands (%d); upgrade witr", path, b.Version, baselineVersion)
+	}
+	return &b, nil
+}
+
+// CheckHost returns an error when b was saved on a host other than host: a
+// baseline copied from another machine accepts that machine's warnings.
+func (b *Baseline) CheckHost(host string) error {
+	if b == nil || b.Hostname == "" || host == "" || b.Hostname == host {
+		return nil
+	}
+	return fmt.Errorf("saved on %s, not on %s", b.Hostname, host)
+}
+
+// entry returns b's entry for id, or nil when it has none.
+func (b *Baseline) entry(id Identity) *Entry {
+	if b == nil {
+		return nil
+	}
+	id = baselineIdentity(id)
+	for i, e := range b.Entries {
+		if baselineIdentity(e.Identity) == id {
+			return &b.Entries[i]
+		}
+	}
+	return nil
+}
+
+// Merge makes the entries of other replace b's entries for the same
+// identities, and adds the rest.
+func (b *Baseline) Merge(other []Entry) {
+	for _, e := range other {
+		if i := slices.IndexFunc(b.Entries, func(x Entry) bool { return x.Identity == e.Identity }); i >= 0 {
+			b.Entries[i] = e
+		} else {
+			b.Entries = append(b.Entries, e)
+		}
+	}
+	slices.SortFunc(b.Entries, func(x, y Entry) int {
+		for _, c := range [][2]string{
+			{x.Process, y.Process}, {x.Exe, y.Exe}, {x.Unit, y.Unit}, {x.Image, y.Image},
+		} {
+			if c[0] != c[1] {
+				if c[0] < c[1] {
+					return -1
+				}
+				return 1
+			}
+		}
+		return 0
+	})
+}
+
+// Save writes b to path, creating its directory. The file is replaced
+// atomically, so a run reading it never sees half a baseline.
+func (b *Baseline) Save(path string) error {
+	b.Version = baselineVersion
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".baseline-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Recorder collects the warnings of analyzed processes into baseline
+// entries. Processes that share an identity, such as an nginx master and
+// its workers, share one entry with the warnings of all of them.
+type Recorder struct {
+	entries []Entry
+}
+
+// Record adds the warnings res still has to the entry for its process.
+// Warnings without a code, read from an older witr's output, are skipped.
+func (r *Recorder) Record(res model.Result) {
+	id := baselineIdentity(IdentityOf(res))
+	i := slices.IndexFunc(r.entries, func(e Entry) bool { return e.Identity == id })
+	if i < 0 {
+		r.entries = append(r.entries, Entry{Identity: id})
+		i = len(r.entries) - 1
+	}
+	e := &r.entries[i]
+	for _, w := range res.Warnings {
+		if w.Code == "" {
+			continue
+		}
+		if !slices.Contains(e.Codes, w.Code) {
+			e.Codes = append(e.Codes, w.Code)
+		}
+		if s := w.Subject(); !slices.Contains(e.Warnings, s) {
+			e.Warnings = append(e.Warnings, s)
+		}
+	}
+	slices.Sort(e.Codes)
+	slices.Sort(e.Warnings)
+}
+
+// Entries returns the recorded entries, including processes that had no
+// warnings: saving those replaces whatever an older baseline accepted for
+// them.
+func (r *Recorder) Entries() []Entry { return r.entries }
@@ -0,0 +1,159 @@
+// Package suppress hides warnings a host has accepted. Two things suppress a
+// warning: the suppress rules of the configuration, matched by warning code,
+// process name, executable, unit or container image, and the baseline, a
+// per-host file that `witr baseline save` fills with the warnings each
+// process had when it was saved. Suppressed warnings move from
+// Result.Warnings to Result.Suppressed, so they are still there to show.
+package suppress
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/pranshuparmar/witr/internal/config"
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Identity is what a suppress rule or a baseline entry matches a process by.
+// It is stable across restarts, unlike a PID.
+type Identity struct {
+	// Process is the process name.
+	Process string
+	// Exe is the executable path.
+	Exe string `json:",omitempty"`
+	// Unit is the service that manages the process: a systemd unit, a
+	// launchd label, an rc script or a Windows service.
+	Unit string `json:",omitempty"`
+	// Image is the image of the container the process runs in, when a
+	// container target named it or an image rule looked it up. Baseline
+	// entries leave it out: the processes `witr baseline save` analyzes are
+	// not container targets, so the image is not known when it records them.
+	Image string `json:",omitempty"`
+}
+
+// baselineIdentity is id as the baseline records and matches it, without
+// the image.
+func baselineIdentity(id Identity) Identity {
+	id.Image = ""
+	return id
+}
+
+// IdentityOf returns the identity of the process res analyzed.
+func IdentityOf(res model.Result) Identity {
+	p := res.Process
+	id := Identity{Process: p.Command, Exe: p.Exe, Unit: p.Service}
+	if id.Unit == "" {
+		switch res.Source.Type {
+		case model.SourceSystemd, model.SourceLaunchd, model.SourceBsdRc, model.SourceWindowsService:
+			id.Unit = res.Source.Name
+		}
+	}
+	if res.Container != nil {
+		id.Image = res.Container.Image
+	}
+	return id
+}
+
+// Suppressor applies suppress rules and a baseline to results.
+type Suppressor struct {
+	rules    []config.SuppressRule
+	baseline *Baseline
+	// images maps container IDs to image names, listed from the container
+	// runtimes the first time an image rule needs one.
+	images map[string]string
+}
+
+// New returns a Suppressor for rules and baseline, either of which may be
+// empty.
+func New(rules []config.SuppressRule, baseline *Baseline) *Suppressor {
+	return &Suppressor{rules: rules, baseline: baseline}
+}
+
+// Apply moves the warnings of res that a rule or the baseline matches from
+// res.Warnings to res.Suppressed, recording what matched each in
+// SuppressedBy. Rules are tried first, in order. ctx bounds the container
+// runtime listing an image rule may need.
+func (s *Suppressor) Apply(ctx context.Context, res *model.Result) {
+	if s == nil || len(res.Warnings) == 0 || (len(s.rules) == 0 && s.baseline == nil) {
+		return
+	}
+	id := IdentityOf(*res)
+	if id.Image == "" && res.Process.ContainerID != "" && s.needsImage() {
+		id.Image = s.image(ctx, res.Process.ContainerID)
+	}
+	accepted := s.baseline.entry(id)
+
+	kept := res.Warnings[:0:0]
+	for _, w := range res.Warnings {
+		if by := s.match(id, w, accepted); by != "" {
+			w.SuppressedBy = by
+			res.Suppressed = append(res.Suppressed, w)
+			continue
+		}
+		kept = append(kept, w)
+	}
+	res.Warnings = kept
+}
+
+// match returns what suppresses w, or "" when nothing does.
+func (s *Suppressor) match(id Identity, w model.Warning, accepted *Entry) string {
+	for _, r := range s.rules {
+		if !Matches(r, id, w.Code) {
+			continue
+		}
+		if r.Reason != "" {
+			return "config: " + r.Reason
+		}
+		return "config"
+	}
+	if accepted.accepts(w) {
+		return "baseline"
+	}
+	return ""
+}
+
+// Matches reports whether r matches a warning with code raised for the
+// process id. Fields r leaves empty match anything.
+func Matches(r config.SuppressRule, id Identity, code model.WarningCode) bool {
+	return glob(r.Code, string(code)) &&
+		glob(r.Process, id.Process) &&
+		glob(r.Exe, id.Exe) &&
+		glob(r.Unit, id.Unit) &&
+		glob(r.Image, id.Image)
+}
+
+func glob(pattern, s string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, _ := path.Match(pattern, s)
+	return ok
+}
+
+func (s *Suppressor) needsImage() bool {
+	for _, r := range s.rules {
+		if r.Image != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// image returns the image of the container whose ID starts with id, as the
+// container runtimes list it.
+func (s *Suppressor) image(ctx context.Context, id string) string {
+	if s.images == nil {
+		s.images = map[string]string{}
+		for _, c := range procpkg.ListAllContainers(ctx) {
+			s.images[c.ID] = c.Image
+		}
+	}
+	for cid, image := range s.images {
+		if strings.HasPrefix(cid, id) || strings.HasPrefix(id, cid) {
+			return image
+		}
+	}
+	return ""
+}
@@ -0,0 +1,232 @@
+package suppress
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/internal/config"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func sshd() model.Result {
+	return model.Result{
+		Process: model.Process{PID: 812, Command: "sshd", Exe: "/usr/sbin/sshd"},
+		Source:  model.Source{Type: model.SourceSystemd, Name: "ssh.service"},
+		Warnings: []model.Warning{
+			{Code: model.WarnRoot, Severity: model.SeverityLow, Message: "Process is running as root",
+				Evidence: map[string]string{"user": "root"}},
+			{Code: model.WarnPublicBind, Severity: model.SeverityMedium, Message: "Process is listening on a public interface",
+				Evidence: map[string]string{"addresses": "0.0.0.0:22"}},
+			{Code: model.WarnLDPreload, Severity: model.SeverityHigh, Message: "Process sets LD_PRELOAD"},
+		},
+	}
+}
+
+func codes(ws []model.Warning) []model.WarningCode {
+	var out []model.WarningCode
+	for _, w := range ws {
+		out = append(out, w.Code)
+	}
+	return out
+}
+
+func TestIdentityOf(t *testing.T) {
+	t.Parallel()
+
+	want := Identity{Process: "sshd", Exe: "/usr/sbin/sshd", Unit: "ssh.service"}
+	if got := IdentityOf(sshd()); got != want {
+		t.Errorf("IdentityOf = %+v, want %+v", got, want)
+	}
+
+	res := sshd()
+	res.Source = model.Source{Type: model.SourceShell, Name: "bash"}
+	res.Container = &model.ContainerMatch{Image: "nginx:1.27"}
+	if got := IdentityOf(res); got.Unit != "" || got.Image != "nginx:1.27" {
+		t.Errorf("a shell is not a unit, and a container target names the image: %+v", got)
+	}
+}
+
+func TestApplyRules(t *testing.T) {
+	t.Parallel()
+
+	s := New([]config.SuppressRule{
+		{Code: "W_ROOT", Process: "sshd", Reason: "sshd needs root"},
+		{Code: "W_PUBLIC_*", Exe: "/usr/sbin/*"},
+		{Code: "W_LD_PRELOAD", Process: "nginx"},
+	}, nil)
+	res := sshd()
+	s.Apply(context.Background(), &res)
+
+	if got := codes(res.Warnings); !slices.Equal(got, []model.WarningCode{model.WarnLDPreload}) {
+		t.Errorf("kept %v, want only W_LD_PRELOAD", got)
+	}
+	if len(res.Suppressed) != 2 {
+		t.Fatalf("suppressed %+v, want two warnings", res.Suppressed)
+	}
+	if by := res.Suppressed[0].SuppressedBy; by != "config: sshd needs root" {
+		t.Errorf("SuppressedBy = %q, want the rule's reason", by)
+	}
+	if by := res.Suppressed[1].SuppressedBy; by != "config" {
+		t.Errorf("SuppressedBy = %q for a rule without a reason, want config", by)
+	}
+}
+
+func TestApplyBaseline(t *testing.T) {
+	t.Parallel()
+
+	b := &Baseline{Entries: []Entry{
+		{Identity: IdentityOf(sshd()), Codes: []model.WarningCode{model.WarnRoot, model.WarnPublicBind}},
+	}}
+	res := sshd()
+	New(nil, b).Apply(context.Background(), &res)
+	if got := codes(res.Warnings); !slices.Equal(got, []model.WarningCode{model.WarnLDPreload}) {
+		t.Errorf("a warning the baseline did not record should stay, kept %v", got)
+	}
+	for _, w := range res.Suppressed {
+		if w.SuppressedBy != "baseline" {
+			t.Errorf("%s: SuppressedBy = %q, want baseline", w.Code, w.SuppressedBy)
+		}
+	}
+
+	// The same program under another unit is another identity.
+	other := sshd()
+	other.Source.Name = "sshd-alt.service"
+	New(nil, b).Apply(context.Background(), &other)
+	if len(other.Suppressed) != 0 {
+		t.Errorf("the baseline of ssh.service should not apply to sshd-alt.service: %+v", other.Suppressed)
+	}
+
+	// A recorded baseline accepts what the process had, not everything
+	// under the same codes: a new public address is reported.
+	var rec Recorder
+	saved := sshd()
+	saved.Warnings = saved.Warnings[:2]
+	rec.Record(saved)
+	recorded := &Baseline{Entries: rec.Entries()}
+	res = sshd()
+	New(nil, recorded).Apply(context.Background(), &res)
+	if got := codes(res.Warnings); !slices.Equal(got, []model.WarningCode{model.WarnLDPreload}) {
+		t.Errorf("the recorded baseline should hide what it saw, kept %v", got)
+	}
+	res = sshd()
+	res.Warnings[1].Evidence = map[string]string{"addresses": "0.0.0.0:22, 0.0.0.0:2222"}
+	New(nil, recorded).Apply(context.Background(), &res)
+	if got := codes(res.Warnings); !slices.Equal(got, []model.WarningCode{model.WarnPublicBind, model.WarnLDPreload}) {
+		t.Errorf("a public address the baseline did not see should be reported, kept %v", got)
+	}
+
+	var nilSuppressor *Suppressor
+	res = sshd()
+	nilSuppressor.Apply(context.Background(), &res)
+	if len(res.Warnings) != 3 {
+		t.Error("a nil Suppressor should leave warnings alone")
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	master := sshd()
+	master.Warnings = master.Warnings[:1]
+	worker := sshd()
+	worker.Process.PID = 813
+	worker.Warnings = worker.Warnings[1:2]
+	quiet := model.Result{Process: model.Process{Command: "cron", Exe: "/usr/sbin/cron"}}
+
+	var rec Recorder
+	rec.Record(master)
+	rec.Record(worker)
+	rec.Record(quiet)
+
+	want := []Entry{
+		{Identity: IdentityOf(master), Codes: []model.WarningCode{model.WarnPublicBind, model.WarnRoot},
+			Warnings: []string{"W_PUBLIC_BIND addresses=0.0.0.0:22", "W_ROOT user=root"}},
+		{Identity: Identity{Process: "cron", Exe: "/usr/sbin/cron"}},
+	}
+	if got := rec.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestBaselineSaveLoad(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state", "witr", "baseline.json")
+	if b, err := LoadBaseline(path); b != nil || err != nil {
+		t.Fatalf("a missing baseline should load as nil, got %v, %v", b, err)
+	}
+
+	b := &Baseline{Hostname: "web1"}
+	b.Merge([]Entry{
+		{Identity: Identity{Process: "sshd"}, Codes: []model.WarningCode{model.WarnRoot}},
+		{Identity: Identity{Process: "nginx"}, Codes: []model.WarningCode{model.WarnRoot}},
+	})
+	b.Merge([]Entry{{Identity: Identity{Process: "sshd"}}})
+	if err := b.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Entry{
+		{Identity: Identity{Process: "nginx"}, Codes: []model.WarningCode{model.WarnRoot}},
+		{Identity: Identity{Process: "sshd"}},
+	}
+	if got.Version != baselineVersion || got.Hostname != "web1" || !reflect.DeepEqual(got.Entries, want) {
+		t.Errorf("loaded %+v, want version %d, host web1 and entries %+v", got, baselineVersion, want)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"Version": 99}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadBaseline(path); err == nil || !strings.Contains(err.Error(), "upgrade witr") {
+		t.Errorf("a newer baseline should ask for an upgrade, got %v", err)
+	}
+}
+
+func TestBaselineAppliesToContainerTarget(t *testing.T) {
+	t.Parallel()
+
+	// baseline save analyzes the process by PID, so it does not know the
+	// image; a later -c lookup of the same container does.
+	saved := sshd()
+	saved.Process.ContainerID = "4f2a9c1d7e3b"
+	var rec Recorder
+	rec.Record(saved)
+	b := &Baseline{}
+	b.Merge(rec.Entries())
+	if img := b.Entries[0].Image; img != "" {
+		t.Errorf("a baseline entry should not record an image, got %q", img)
+	}
+
+	res := sshd()
+	res.Process.ContainerID = "4f2a9c1d7e3b"
+	res.Container = &model.ContainerMatch{ID: "4f2a9c1d7e3b", Image: "linuxserver/openssh-server"}
+	New(nil, b).Apply(context.Background(), &res)
+	if len(res.Warnings) != 0 || len(res.Suppressed) != 3 {
+		t.Errorf("the baseline should accept every warning of the container target: kept %v, suppressed %v", codes(res.Warnings), codes(res.Suppressed))
+	}
+}
+
+func TestBaselineCheckHost(t *testing.T) {
+	t.Parallel()
+
+	b := &Baseline{Hostname: "web1"}
+	if err := b.CheckHost("web1"); err != nil {
+		t.Errorf("same host: %v", err)
+	}
+	if err := b.CheckHost("db2"); err == nil || !strings.Contains(err.Error(), "saved on web1, not on db2") {
+		t.Errorf("another host: got %v, want a mismatch", err)
+	}
+	var none *Baseline
+	if err := none.CheckHost("db2"); err != nil || (&Baseline{}).CheckHost("db2") != nil {
+		t.Error("no baseline, or one without a hostname, fits any host")
+	}
+}
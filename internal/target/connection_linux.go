@@ -0,0 +1,48 @@
+//go:build linux
+
+package target
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+)
+
+// ResolveConnection returns the PIDs with a TCP or UDP socket connected to
+// one of ips on port: the processes talking to that endpoint.
+func ResolveConnection(ctx context.Context, ips []net.IP, port int) ([]int, error) {
+	sockets, err := procpkg.Sockets()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sockets: %w", err)
+	}
+
+	inodes := make(map[string]bool)
+	for inode, s := range sockets {
+		if s.RemotePort != port || s.State == "LISTEN" {
+			continue
+		}
+		remote := net.ParseIP(s.RemoteAddress)
+		for _, ip := range ips {
+			if remote.Equal(ip) {
+				inodes[inode] = true
+				break
+			}
+		}
+	}
+	endpoint := net.JoinHostPort(ips[0].String(), strconv.Itoa(port))
+	if len(inodes) == 0 {
+		return nil, fmt.Errorf("no process connected to %s", endpoint)
+	}
+
+	pids, err := pidsHoldingSockets(ctx, inodes)
+	if err != nil {
+		return nil, err
+	}
+	if len(pids) == 0 {
+		return nil, ErrSocketOwnerUnknown
+	}
+	return pids, nil
+}
@@ -0,0 +1,28 @@
+//go:build linux
+
+package target
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestResolveConnectionAgainstSyntheticProc(t *testing.T) {
+	useSyntheticProc(t, syntheticHost())
+	ctx := context.Background()
+
+	// node (4242) holds 127.0.0.1:3000 → 127.0.0.1:54321.
+	pids, err := ResolveConnection(ctx, []net.IP{net.ParseIP("127.0.0.1")}, 54321)
+	if err != nil || !reflect.DeepEqual(pids, []int{4242}) {
+		t.Errorf("ResolveConnection = %v, %v; want [4242]", pids, err)
+	}
+	// The listener on 5432 has no remote end to match.
+	if _, err := ResolveConnection(ctx, []net.IP{net.ParseIP("0.0.0.0")}, 0); err == nil {
+		t.Error("a listener is not a connection")
+	}
+	if _, err := ResolveConnection(ctx, []net.IP{net.ParseIP("10.2.3.4")}, 5432); err == nil {
+		t.Error("ResolveConnection should fail when nothing talks to the endpoint")
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package target
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+// ResolveConnection looks up the processes connected to an endpoint. Only
+// Linux records the remote end of each socket where witr reads it.
+func ResolveConnection(ctx context.Context, ips []net.IP, port int) ([]int, error) {
+	return nil, fmt.Errorf("connection lookup is %w", ErrUnsupported)
+}
@@ -1,6 +1,7 @@
 package target
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"path/filepath"
@@ -8,14 +9,14 @@ import (
 	"strings"
 )
 
-func ResolveFile(path string) ([]int, error) {
+func ResolveFile(ctx context.Context, path string) ([]int, error) {
 	// Absolute path so a leading-dash path can't be parsed as an lsof option.
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		absPath = path
 	}
 
-	cmd := exec.Command("lsof", "-F", "p", absPath)
+	cmd := exec.CommandContext(ctx, "lsof", "-F", "p", absPath)
 	out, err := cmd.Output()
 	if err != nil {
 		if exitError, ok := err.(*exec.ExitError); ok && exitError.ExitCode() == 1 {
@@ -1,6 +1,7 @@
 package target
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"path/filepath"
@@ -8,14 +9,14 @@ import (
 	"strings"
 )
 
-func ResolveFile(path string) ([]int, error) {
+func ResolveFile(ctx context.Context, path string) ([]int, error) {
 	// Absolute path so a leading-dash path can't be parsed as an fstat option.
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		absPath = path
 	}
 
-	cmd := exec.Command("fstat", absPath)
+	cmd := exec.CommandContext(ctx, "fstat", absPath)
 	out, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("fstat failed: %w", err)
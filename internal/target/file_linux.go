@@ -1,14 +1,16 @@
 package target
 
 import (
+	"context"
 	"fmt"
-	"os"
 	"path/filepath"
 	"strconv"
+
+	"github.com/pranshuparmar/witr/internal/procfs"
 )
 
 // ResolveFile finds processes holding a lock on the given file path
-func ResolveFile(path string) ([]int, error) {
+func ResolveFile(ctx context.Context, path string) ([]int, error) {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return nil, err
@@ -21,12 +23,17 @@ func ResolveFile(path string) ([]int, error) {
 
 	var pids []int
 
-	procDirs, err := os.ReadDir("/proc")
+	procDirs, err := procfs.ReadDir("/proc")
 	if err != nil {
 		return nil, fmt.Errorf("failed to read /proc: %w", err)
 	}
 
 	for _, d := range procDirs {
+		// Reading every fd of every process is the slow part; stop as soon
+		// as the caller gives up.
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		if !d.IsDir() {
 			continue
 		}
@@ -36,13 +43,13 @@ func ResolveFile(path string) ([]int, error) {
 		}
 
 		fdDir := filepath.Join("/proc", d.Name(), "fd")
-		fds, err := os.ReadDir(fdDir)
+		fds, err := procfs.ReadDir(fdDir)
 		if err != nil {
 			continue
 		}
 
 		for _, fd := range fds {
-			linkPath, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			linkPath, err := procfs.ReadLink(filepath.Join(fdDir, fd.Name()))
 			if err != nil {
 				continue
 			}
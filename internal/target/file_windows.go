@@ -3,6 +3,7 @@
 package target
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"runtime"
@@ -44,7 +45,10 @@ type rmProcessInfo struct {
 	Restartable      int32
 }
 
-func ResolveFile(path string) ([]int, error) {
+func ResolveFile(ctx context.Context, path string) ([]int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	abs, err := filepath.Abs(path)
 	if err != nil {
 		abs = path
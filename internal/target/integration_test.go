@@ -3,6 +3,7 @@
 package target
 
 import (
+	"context"
 	"net"
 	"os"
 	"os/exec"
@@ -58,7 +59,7 @@ func TestIntegration_ResolveNameFindsSpawnedChild(t *testing.T) {
 	var lastErr error
 	for i := 0; i < 10; i++ {
 		var err error
-		pids, err = ResolveName(name, true)
+		pids, err = ResolveName(context.Background(), name, true)
 		if err == nil && slices.Contains(pids, childPID) {
 			return
 		}
@@ -101,7 +102,7 @@ func TestIntegration_ResolveNameFindsGrep(t *testing.T) {
 	var pids []int
 	var lastErr error
 	for i := 0; i < 10; i++ {
-		pids, lastErr = ResolveName("grep", true)
+		pids, lastErr = ResolveName(context.Background(), "grep", true)
 		if lastErr == nil && slices.Contains(pids, childPID) {
 			return
 		}
@@ -125,7 +126,7 @@ func TestIntegration_ResolvePortFindsLoopbackListener(t *testing.T) {
 	port := ln.Addr().(*net.TCPAddr).Port
 	self := os.Getpid()
 
-	pids, err := ResolvePort(port)
+	pids, err := ResolvePort(context.Background(), port)
 	if err != nil {
 		t.Fatalf("ResolvePort(%d): %v", port, err)
 	}
@@ -148,7 +149,7 @@ func TestIntegration_ResolvePortNonexistent(t *testing.T) {
 	// The kernel may keep the port in TIME_WAIT briefly. Loop a few times
 	// until the port is genuinely unowned.
 	for i := 0; i < 10; i++ {
-		_, err := ResolvePort(port)
+		_, err := ResolvePort(context.Background(), port)
 		if err != nil {
 			return // expected
 		}
@@ -177,7 +178,7 @@ func TestIntegration_ResolveFileSelf(t *testing.T) {
 	var pids []int
 	var lastErr error
 	for i := 0; i < 10; i++ {
-		pids, lastErr = ResolveFile(f.Name())
+		pids, lastErr = ResolveFile(context.Background(), f.Name())
 		if lastErr == nil && slices.Contains(pids, self) {
 			return
 		}
@@ -3,6 +3,7 @@
 package target
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -26,7 +27,7 @@ func isValidServiceLabel(label string) bool {
 	return validServiceLabelRegex.MatchString(label)
 }
 
-func ResolveName(name string, exact bool) ([]int, error) {
+func ResolveName(ctx context.Context, name string, exact bool) ([]int, error) {
 	var procPIDs []int
 
 	lowerName := strings.ToLower(name)
@@ -35,15 +36,15 @@ func ResolveName(name string, exact bool) ([]int, error) {
 	// Resolve own ancestry to exclude parents (sudo, shell, etc.) from matching
 	ignoredPids := make(map[int]bool)
 	ignoredPids[selfPid] = true
-	if ancestry, err := procpkg.ResolveAncestry(selfPid); err == nil {
-		for _, p := range ancestry {
+	if lineage, err := procpkg.IndexFrom(ctx).Lineage(selfPid); err == nil {
+		for _, p := range lineage {
 			ignoredPids[p.PID] = true
 		}
 	}
 
 	// Use ps to list all processes on macOS
 	// ps -axo pid=,comm=,args=
-	out, err := exec.Command("ps", "-axo", "pid=,comm=,args=").Output()
+	out, err := exec.CommandContext(ctx, "ps", "-axo", "pid=,comm=,args=").Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list processes: %w", err)
 	}
@@ -106,7 +107,7 @@ func ResolveName(name string, exact bool) ([]int, error) {
 	}
 
 	// Service detection (launchd)
-	servicePID, _ := resolveLaunchdServicePID(name)
+	servicePID, _ := resolveLaunchdServicePID(ctx, name)
 
 	// Merge and dedupe matches, keeping service PID first.
 	seen := map[int]bool{}
@@ -133,7 +134,7 @@ func ResolveName(name string, exact bool) ([]int, error) {
 }
 
 // resolveLaunchdServicePID tries to resolve a launchd service and returns its PID if running.
-func resolveLaunchdServicePID(name string) (int, error) {
+func resolveLaunchdServicePID(ctx context.Context, name string) (int, error) {
 	// Validate input before using in command
 	if !isValidServiceLabel(name) {
 		return 0, fmt.Errorf("invalid service name %q", name)
@@ -150,7 +151,7 @@ func resolveLaunchdServicePID(name string) (int, error) {
 	for _, label := range labels {
 		// All labels are derived from validated name, so they're safe
 		// launchctl print system/<label> or gui/<uid>/<label>
-		out, err := exec.Command("launchctl", "print", "system/"+label).Output()
+		out, err := exec.CommandContext(ctx, "launchctl", "print", "system/"+label).Output()
 		if err == nil {
 			// Parse output to find PID
 			// Look for "pid = <number>"
@@ -3,6 +3,7 @@
 package target
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -26,7 +27,7 @@ func isValidServiceLabel(label string) bool {
 	return validServiceLabelRegex.MatchString(label)
 }
 
-func ResolveName(name string, exact bool) ([]int, error) {
+func ResolveName(ctx context.Context, name string, exact bool) ([]int, error) {
 	var procPIDs []int
 
 	lowerName := strings.ToLower(name)
@@ -35,15 +36,15 @@ func ResolveName(name string, exact bool) ([]int, error) {
 	// Resolve own ancestry to exclude parents (sudo, shell, etc.) from matching
 	ignoredPids := make(map[int]bool)
 	ignoredPids[selfPid] = true
-	if ancestry, err := procpkg.ResolveAncestry(selfPid); err == nil {
-		for _, p := range ancestry {
+	if lineage, err := procpkg.IndexFrom(ctx).Lineage(selfPid); err == nil {
+		for _, p := range lineage {
 			ignoredPids[p.PID] = true
 		}
 	}
 
 	// Use ps to list all processes on FreeBSD
 	// FreeBSD syntax: ps -axww -o pid -o comm -o args
-	out, err := exec.Command("ps", "-axww", "-o", "pid", "-o", "comm", "-o", "args").Output()
+	out, err := exec.CommandContext(ctx, "ps", "-axww", "-o", "pid", "-o", "comm", "-o", "args").Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list processes: %w", err)
 	}
@@ -112,7 +113,7 @@ func ResolveName(name string, exact bool) ([]int, error) {
 	}
 
 	// Service detection (rc.d)
-	servicePID, _ := resolveRcServicePID(name)
+	servicePID, _ := resolveRcServicePID(ctx, name)
 
 	// Merge and dedupe matches, keeping service PID first.
 	seen := map[int]bool{}
@@ -139,7 +140,7 @@ func ResolveName(name string, exact bool) ([]int, error) {
 }
 
 // resolveRcServicePID tries to resolve a FreeBSD rc.d service and returns its PID if running.
-func resolveRcServicePID(name string) (int, error) {
+func resolveRcServicePID(ctx context.Context, name string) (int, error) {
 	// Validate input before using in command
 	if !isValidServiceLabel(name) {
 		return 0, fmt.Errorf("invalid service name %q", name)
@@ -152,7 +153,7 @@ func resolveRcServicePID(name string) (int, error) {
 		pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
 		if err == nil && pid > 0 {
 			// Verify process exists using ps command
-			out, err := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "pid=").Output()
+			out, err := exec.CommandContext(ctx, "ps", "-p", strconv.Itoa(pid), "-o", "pid=").Output()
 			if err == nil && strings.TrimSpace(string(out)) != "" {
 				return pid, nil
 			}
@@ -160,7 +161,7 @@ func resolveRcServicePID(name string) (int, error) {
 	}
 
 	// Try service <name> status
-	out, err := exec.Command("service", name, "status").Output()
+	out, err := exec.CommandContext(ctx, "service", name, "status").Output()
 	if err == nil {
 		outStr := string(out)
 		// Look for "is running as pid <number>" or "PID: <number>"
@@ -3,6 +3,7 @@
 package target
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -11,14 +12,23 @@ import (
 	"strings"
 
 	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/procfs"
 )
 
-func ResolveName(name string, exact bool) ([]int, error) {
+func ResolveName(ctx context.Context, name string, exact bool) ([]int, error) {
 	var procPIDs []int
 
-	entries, _ := os.ReadDir("/proc")
+	// One scan of the process table, shared with the rest of the run; the
+	// command line of a process is only read when its name did not match.
+	idx := procpkg.IndexFrom(ctx)
+	procs, _ := idx.Processes()
 	lowerName := strings.ToLower(name)
-	selfPid := os.Getpid()
+	// witr and its ancestors only exist in the live process table; under a
+	// proc root or snapshot, the same PIDs belong to unrelated processes.
+	selfPid := -1
+	if procfs.Live() {
+		selfPid = os.Getpid()
+	}
 
 	// Build ignored PID set lazily — only resolve ancestry if we actually
 	// need to filter matches (avoids walking the chain on every invocation)
@@ -29,9 +39,12 @@ func ResolveName(name string, exact bool) ([]int, error) {
 		}
 		if ignoredPids == nil {
 			ignoredPids = make(map[int]bool)
+			if selfPid < 0 {
+				return false
+			}
 			ignoredPids[selfPid] = true
-			if ancestry, err := procpkg.ResolveAncestry(selfPid); err == nil {
-				for _, p := range ancestry {
+			if lineage, err := idx.Lineage(selfPid); err == nil {
+				for _, p := range lineage {
 					ignoredPids[p.PID] = true
 				}
 			}
@@ -39,11 +52,11 @@ func ResolveName(name string, exact bool) ([]int, error) {
 		return ignoredPids[pid]
 	}
 
-	for _, e := range entries {
-		pid, err := strconv.Atoi(e.Name())
-		if err != nil {
-			continue
+	for _, p := range procs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
+		pid := p.PID
 
 		if lowerName == strconv.Itoa(pid) {
 			continue
@@ -53,26 +66,20 @@ func ResolveName(name string, exact bool) ([]int, error) {
 			continue
 		}
 
-		comm, err := os.ReadFile("/proc/" + e.Name() + "/comm")
-		if err == nil {
-			commLower := strings.ToLower(strings.TrimSpace(string(comm)))
-			var match bool
-			if exact {
-				match = commLower == lowerName
-			} else {
-				match = strings.Contains(commLower, lowerName)
-			}
-			if match {
-				procPIDs = append(procPIDs, pid)
-				continue
-			}
+		commLower := strings.ToLower(strings.TrimSpace(p.Command))
+		var match bool
+		if exact {
+			match = commLower == lowerName
+		} else {
+			match = strings.Contains(commLower, lowerName)
+		}
+		if match {
+			procPIDs = append(procPIDs, pid)
+			continue
 		}
 
-		cmdline, err := os.ReadFile("/proc/" + e.Name() + "/cmdline")
-		if err == nil {
-			cmd := strings.ReplaceAll(string(cmdline), "\x00", " ")
+		if cmd := idx.Cmdline(pid); cmd != "" {
 			cmdLower := strings.ToLower(cmd)
-			var match bool
 			if exact {
 				match = matchesExactToken(cmdLower, lowerName)
 			} else {
@@ -90,8 +97,8 @@ func ResolveName(name string, exact bool) ([]int, error) {
 	// pays it when resolving a service whose process name differs (or a service
 	// that isn't currently running).
 	var servicePID int
-	if len(procPIDs) == 0 {
-		servicePID, _ = resolveSystemdServiceMainPID(name)
+	if len(procPIDs) == 0 && procfs.Live() {
+		servicePID, _ = resolveSystemdServiceMainPID(ctx, name)
 	}
 
 	seen := map[int]bool{}
@@ -118,13 +125,13 @@ func ResolveName(name string, exact bool) ([]int, error) {
 }
 
 // resolveSystemdServiceMainPID tries to resolve a systemd service and returns its MainPID if running.
-func resolveSystemdServiceMainPID(name string) (int, error) {
+func resolveSystemdServiceMainPID(ctx context.Context, name string) (int, error) {
 	// Accept both foo and foo.service
 	svcName := name
 	if !strings.HasSuffix(svcName, ".service") {
 		svcName += ".service"
 	}
-	out, err := exec.Command("systemctl", "show", "-p", "MainPID", "--value", "--", svcName).Output()
+	out, err := exec.CommandContext(ctx, "systemctl", "show", "-p", "MainPID", "--value", "--", svcName).Output()
 	if err != nil {
 		return 0, err
 	}
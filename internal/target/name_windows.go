@@ -3,6 +3,7 @@
 package target
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -16,8 +17,9 @@ import (
 // fuzzy/exact-token matching that includes the command line, only then do we
 // pay for per-PID PEB reads — bounded to candidates that survive a cheap
 // pre-filter.
-func ResolveName(name string, exact bool) ([]int, error) {
-	procs, err := procpkg.ListProcessSnapshot()
+func ResolveName(ctx context.Context, name string, exact bool) ([]int, error) {
+	idx := procpkg.IndexFrom(ctx)
+	procs, err := idx.Processes()
 	if err != nil {
 		return nil, fmt.Errorf("enumerate processes: %w", err)
 	}
@@ -25,8 +27,8 @@ func ResolveName(name string, exact bool) ([]int, error) {
 	lowerName := strings.ToLower(name)
 	selfPid := os.Getpid()
 	ignoredPids := map[int]bool{selfPid: true}
-	if ancestry, err := procpkg.ResolveAncestry(selfPid); err == nil {
-		for _, p := range ancestry {
+	if lineage, err := idx.Lineage(selfPid); err == nil {
+		for _, p := range lineage {
 			ignoredPids[p.PID] = true
 		}
 	}
@@ -61,6 +63,9 @@ func ResolveName(name string, exact bool) ([]int, error) {
 	// scan. Only do it when the name pass produced nothing, and bound the
 	// work by skipping ignored PIDs.
 	for _, p := range procs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		if ignoredPids[p.PID] {
 			continue
 		}
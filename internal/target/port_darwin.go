@@ -3,6 +3,7 @@
 package target
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"sort"
@@ -10,11 +11,11 @@ import (
 	"strings"
 )
 
-func ResolvePort(port int) ([]int, error) {
+func ResolvePort(ctx context.Context, port int) ([]int, error) {
 	pidSet := make(map[int]bool)
 
 	// Query TCP listeners: lsof -i TCP:<port> -s TCP:LISTEN -n -P -t
-	if out, err := exec.Command("lsof", "-i", fmt.Sprintf("TCP:%d", port), "-s", "TCP:LISTEN", "-n", "-P", "-t").Output(); err == nil {
+	if out, err := exec.CommandContext(ctx, "lsof", "-i", fmt.Sprintf("TCP:%d", port), "-s", "TCP:LISTEN", "-n", "-P", "-t").Output(); err == nil {
 		for _, pidStr := range strings.Split(strings.TrimSpace(string(out)), "\n") {
 			if pid, err := strconv.Atoi(strings.TrimSpace(pidStr)); err == nil && pid > 0 {
 				pidSet[pid] = true
@@ -24,7 +25,7 @@ func ResolvePort(port int) ([]int, error) {
 
 	// Query UDP bound sockets: lsof -i UDP:<port> -n -P -t
 	// UDP is connectionless so there is no LISTEN state to filter on.
-	if out, err := exec.Command("lsof", "-i", fmt.Sprintf("UDP:%d", port), "-n", "-P", "-t").Output(); err == nil {
+	if out, err := exec.CommandContext(ctx, "lsof", "-i", fmt.Sprintf("UDP:%d", port), "-n", "-P", "-t").Output(); err == nil {
 		for _, pidStr := range strings.Split(strings.TrimSpace(string(out)), "\n") {
 			if pid, err := strconv.Atoi(strings.TrimSpace(pidStr)); err == nil && pid > 0 {
 				pidSet[pid] = true
@@ -35,7 +36,7 @@ func ResolvePort(port int) ([]int, error) {
 	if len(pidSet) == 0 {
 		// Fallback: include processes with connected (non-listening) sockets
 		// on this port — `lsof -i :port` matches either side of the connection.
-		if out, err := exec.Command("lsof", "-i", fmt.Sprintf(":%d", port), "-n", "-P", "-t").Output(); err == nil {
+		if out, err := exec.CommandContext(ctx, "lsof", "-i", fmt.Sprintf(":%d", port), "-n", "-P", "-t").Output(); err == nil {
 			for _, pidStr := range strings.Split(strings.TrimSpace(string(out)), "\n") {
 				if pid, err := strconv.Atoi(strings.TrimSpace(pidStr)); err == nil && pid > 0 {
 					pidSet[pid] = true
@@ -44,7 +45,7 @@ func ResolvePort(port int) ([]int, error) {
 		}
 		if len(pidSet) == 0 {
 			// Try alternative: netstat fallback
-			return resolvePortNetstat(port)
+			return resolvePortNetstat(ctx, port)
 		}
 	}
 
@@ -58,14 +59,14 @@ func ResolvePort(port int) ([]int, error) {
 	return result, nil
 }
 
-func resolvePortNetstat(port int) ([]int, error) {
+func resolvePortNetstat(ctx context.Context, port int) ([]int, error) {
 	pidSet := make(map[int]bool)
 	fallbackSet := make(map[int]bool)
 	sawListenNoOwner := false
 	portStr := fmt.Sprintf(".%d", port)
 
 	// Check TCP: netstat -anv -p tcp
-	if out, err := exec.Command("netstat", "-anv", "-p", "tcp").Output(); err == nil {
+	if out, err := exec.CommandContext(ctx, "netstat", "-anv", "-p", "tcp").Output(); err == nil {
 		for line := range strings.Lines(string(out)) {
 			fields := strings.Fields(line)
 			if len(fields) < 9 {
@@ -92,7 +93,7 @@ func resolvePortNetstat(port int) ([]int, error) {
 	}
 
 	// Check UDP bound/connected sockets: netstat -anv -p udp (no LISTEN state).
-	if out, err := exec.Command("netstat", "-anv", "-p", "udp").Output(); err == nil {
+	if out, err := exec.CommandContext(ctx, "netstat", "-anv", "-p", "udp").Output(); err == nil {
 		for line := range strings.Lines(string(out)) {
 			fields := strings.Fields(line)
 			if len(fields) >= 9 && strings.HasSuffix(fields[3], portStr) {
@@ -3,6 +3,7 @@
 package target
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -13,20 +14,20 @@ import (
 	"github.com/pranshuparmar/witr/internal/output"
 )
 
-func ResolvePort(port int) ([]int, error) {
-	addressToPIDs, err := sockstatPortLookup(port, true)
+func ResolvePort(ctx context.Context, port int) ([]int, error) {
+	addressToPIDs, err := sockstatPortLookup(ctx, port, true)
 	if err == nil && len(addressToPIDs) == 0 {
 		err = fmt.Errorf("empty")
 	}
 	if err != nil {
-		if fallback, fbErr := sockstatPortLookup(port, false); fbErr == nil && len(fallback) > 0 {
+		if fallback, fbErr := sockstatPortLookup(ctx, port, false); fbErr == nil && len(fallback) > 0 {
 			addressToPIDs = fallback
 		}
 	}
 
 	if len(addressToPIDs) == 0 {
 		// Try netstat as fallback
-		return resolvePortNetstat(port)
+		return resolvePortNetstat(ctx, port)
 	}
 
 	// For each unique bind address, keep only the smallest PID
@@ -45,7 +46,7 @@ func ResolvePort(port int) ([]int, error) {
 	// If multiple different addresses are listening, show ambiguity and exit
 	// (this indicates separate services, not master/worker)
 	if len(uniqueAddresses) > 1 {
-		return handlePortAmbiguity(port, uniqueAddresses)
+		return handlePortAmbiguity(ctx, port, uniqueAddresses)
 	}
 
 	// Single address: return the PID
@@ -65,7 +66,7 @@ func ResolvePort(port int) ([]int, error) {
 // is true, only listening sockets are returned (the historical behavior). When
 // false, all sockets bound to or connected on the local port are returned so
 // processes with established connections become discoverable.
-func sockstatPortLookup(port int, listenersOnly bool) (map[string][]int, error) {
+func sockstatPortLookup(ctx context.Context, port int, listenersOnly bool) (map[string][]int, error) {
 	addressToPIDs := make(map[string][]int)
 
 	for _, proto := range []string{"tcp", "udp"} {
@@ -74,7 +75,7 @@ func sockstatPortLookup(port int, listenersOnly bool) (map[string][]int, error)
 			if listenersOnly {
 				args = append([]string{"-l"}, args...)
 			}
-			out, err := exec.Command("sockstat", args...).Output()
+			out, err := exec.CommandContext(ctx, "sockstat", args...).Output()
 			if err != nil {
 				continue
 			}
@@ -105,14 +106,14 @@ func sockstatPortLookup(port int, listenersOnly bool) (map[string][]int, error)
 	return addressToPIDs, nil
 }
 
-func resolvePortNetstat(port int) ([]int, error) {
+func resolvePortNetstat(ctx context.Context, port int) ([]int, error) {
 	portStr := fmt.Sprintf(".%d", port)
 	portColonStr := fmt.Sprintf(":%d", port)
 
 	// Check both TCP and UDP via netstat. Any match — listener or connected —
 	// is enough to forward to fstat for PID resolution.
 	for _, proto := range []string{"tcp", "udp"} {
-		out, err := exec.Command("netstat", "-an", "-p", proto).Output()
+		out, err := exec.CommandContext(ctx, "netstat", "-an", "-p", proto).Output()
 		if err != nil {
 			continue
 		}
@@ -123,7 +124,7 @@ func resolvePortNetstat(port int) ([]int, error) {
 				continue
 			}
 			if strings.HasSuffix(fields[3], portStr) || strings.HasSuffix(fields[3], portColonStr) {
-				return resolvePortFstat(port)
+				return resolvePortFstat(ctx, port)
 			}
 		}
 	}
@@ -131,10 +132,10 @@ func resolvePortNetstat(port int) ([]int, error) {
 	return nil, fmt.Errorf("no process listening on port %d", port)
 }
 
-func resolvePortFstat(port int) ([]int, error) {
+func resolvePortFstat(ctx context.Context, port int) ([]int, error) {
 	// Use fstat to find processes with open sockets
 	// This is less efficient but works as a fallback
-	out, err := exec.Command("fstat").Output()
+	out, err := exec.CommandContext(ctx, "fstat").Output()
 	if err != nil {
 		return nil, fmt.Errorf("no process listening on port %d", port)
 	}
@@ -181,7 +182,7 @@ func resolvePortFstat(port int) ([]int, error) {
 
 // handlePortAmbiguity displays disambiguation information when multiple services
 // are listening on different addresses for the same port
-func handlePortAmbiguity(port int, addressToPID map[string]int) ([]int, error) {
+func handlePortAmbiguity(ctx context.Context, port int, addressToPID map[string]int) ([]int, error) {
 	fmt.Fprintf(os.Stderr, "Ambiguous port query: %d\n\n", port)
 	fmt.Fprintln(os.Stderr, "Multiple services are listening on different addresses:")
 	fmt.Fprintln(os.Stderr, "")
@@ -203,7 +204,7 @@ func handlePortAmbiguity(port int, addressToPID map[string]int) ([]int, error) {
 	for i, entry := range entries {
 		// Get command name
 		cmdline := "(unknown)"
-		psOut, err := exec.Command("ps", "-p", strconv.Itoa(entry.pid), "-o", "args").Output()
+		psOut, err := exec.CommandContext(ctx, "ps", "-p", strconv.Itoa(entry.pid), "-o", "args").Output()
 		if err == nil {
 			lines := strings.Split(strings.TrimSpace(string(psOut)), "\n")
 			if len(lines) >= 2 {
@@ -213,7 +214,7 @@ func handlePortAmbiguity(port int, addressToPID map[string]int) ([]int, error) {
 
 		// Check if in jail
 		context := ""
-		jailOut, err := exec.Command("jls", "-j", strconv.Itoa(entry.pid)).Output()
+		jailOut, err := exec.CommandContext(ctx, "jls", "-j", strconv.Itoa(entry.pid)).Output()
 		if err == nil && strings.TrimSpace(string(jailOut)) != "" {
 			context = " (jail)"
 		}
@@ -3,31 +3,57 @@
 package target
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"path/filepath"
+	"slices"
 	"sort"
-	"strconv"
 	"strings"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/procfs"
 )
 
-func findSocketInodes(port int, listenersOnly bool) (map[string]bool, error) {
+// findSocketInodes returns the inodes of the sockets on port. Unless all is
+// set, witr's own network namespace wins when it has one, so a host listener
+// is not mixed up with containers' listeners on the same port; otherwise
+// every other namespace is searched, so a port bound inside a container that
+// does not publish it is found too.
+func findSocketInodes(port int, listenersOnly, all bool) (map[string]bool, error) {
 	inodes := make(map[string]bool)
+	for i, t := range procpkg.NetTables() {
+		if i == 1 && len(inodes) > 0 && !all {
+			break
+		}
+		findSocketInodesIn(t.Dir, port, listenersOnly, inodes)
+	}
+
+	if len(inodes) == 0 {
+		if listenersOnly {
+			return nil, fmt.Errorf("no process listening on port %d", port)
+		}
+		return nil, fmt.Errorf("no process bound to or connected on port %d", port)
+	}
+
+	return inodes, nil
+}
 
+// findSocketInodesIn adds the inodes of the sockets bound to port in the
+// tables of dir, /proc/net or a /proc/<pid>/net, to inodes.
+func findSocketInodesIn(dir string, port int, listenersOnly bool, inodes map[string]bool) {
 	type procNetFile struct {
 		path  string
 		isTCP bool
 	}
 	files := []procNetFile{
-		{"/proc/net/tcp", true},
-		{"/proc/net/tcp6", true},
-		{"/proc/net/udp", false},
-		{"/proc/net/udp6", false},
+		{dir + "/tcp", true},
+		{dir + "/tcp6", true},
+		{dir + "/udp", false},
+		{dir + "/udp6", false},
 	}
 	targetHex := fmt.Sprintf("%04X", port)
 
 	for _, f := range files {
-		data, err := os.ReadFile(f.path)
+		data, err := procfs.ReadFile(f.path)
 		if err != nil {
 			continue
 		}
@@ -75,21 +101,12 @@ func findSocketInodes(port int, listenersOnly bool) (map[string]bool, error) {
 			}
 		}
 	}
-
-	if len(inodes) == 0 {
-		if listenersOnly {
-			return nil, fmt.Errorf("no process listening on port %d", port)
-		}
-		return nil, fmt.Errorf("no process bound to or connected on port %d", port)
-	}
-
-	return inodes, nil
 }
 
-func ResolvePort(port int) ([]int, error) {
-	inodes, err := findSocketInodes(port, true)
+func ResolvePort(ctx context.Context, port int) ([]int, error) {
+	inodes, err := findSocketInodes(port, true, allNetns(ctx))
 	if err != nil {
-		fallbackInodes, fallbackErr := findSocketInodes(port, false)
+		fallbackInodes, fallbackErr := findSocketInodes(port, false, allNetns(ctx))
 		if fallbackErr != nil {
 			return nil, err
 		}
@@ -97,44 +114,18 @@ func ResolvePort(port int) ([]int, error) {
 	}
 
 	// collect all owning pids so callers can handle multi-owner sockets.
-	pidSet := make(map[int]bool)
-	procEntries, _ := os.ReadDir("/proc")
-	for _, entry := range procEntries {
-		pid, err := strconv.Atoi(entry.Name())
-		if err != nil {
-			continue
-		}
-
-		fdDir := filepath.Join("/proc", entry.Name(), "fd")
-		fds, err := os.ReadDir(fdDir)
-		if err != nil {
-			continue
-		}
-
-		for _, fd := range fds {
-			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
-			if err != nil {
-				continue
-			}
-
-			if rest, ok := strings.CutPrefix(link, "socket:["); ok {
-				inode, ok := strings.CutSuffix(rest, "]")
-				if ok && inodes[inode] {
-					pidSet[pid] = true
-					break
-				}
-			}
-		}
+	pids, err := pidsHoldingSockets(ctx, inodes)
+	if err != nil {
+		return nil, err
 	}
 
-	result := make([]int, 0, len(pidSet))
-	for pid := range pidSet {
-		if len(pidSet) > 1 && pid == 1 {
+	result := make([]int, 0, len(pids))
+	for _, pid := range pids {
+		if len(pids) > 1 && pid == 1 {
 			continue
 		}
 		result = append(result, pid)
 	}
-	sort.Ints(result)
 
 	if len(result) == 0 {
 		return nil, ErrSocketOwnerUnknown
@@ -142,3 +133,22 @@ func ResolvePort(port int) ([]int, error) {
 
 	return result, nil
 }
+
+// pidsHoldingSockets returns the PIDs with a descriptor open on any of the
+// socket inodes, sorted.
+func pidsHoldingSockets(ctx context.Context, inodes map[string]bool) ([]int, error) {
+	holders, err := procpkg.SocketHolders(ctx, inodes)
+	if err != nil {
+		return nil, err
+	}
+	var pids []int
+	for _, h := range holders {
+		for _, pid := range h {
+			if !slices.Contains(pids, pid) {
+				pids = append(pids, pid)
+			}
+		}
+	}
+	sort.Ints(pids)
+	return pids, nil
+}
@@ -3,15 +3,16 @@
 package target
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"strconv"
 	"strings"
 )
 
-func ResolvePort(port int) ([]int, error) {
+func ResolvePort(ctx context.Context, port int) ([]int, error) {
 	// netstat -ano
-	out, err := exec.Command("netstat", "-ano").Output()
+	out, err := exec.CommandContext(ctx, "netstat", "-ano").Output()
 	if err != nil {
 		return nil, err
 	}
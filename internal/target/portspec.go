@@ -0,0 +1,230 @@
+package target
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/source"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// PortSpec is a parsed --port value: one port or an inclusive range,
+// optionally limited to one protocol.
+type PortSpec struct {
+	Low, High int
+	// Proto is "tcp" or "udp"; empty matches both.
+	Proto string
+}
+
+// ParsePort reads a --port value: a number (5432), a range (8000-8100), or a
+// service name from /etc/services (postgresql), each optionally qualified
+// with a protocol (udp/53, tcp/8000-8100, udp/domain).
+func ParsePort(value string) (PortSpec, error) {
+	val := strings.TrimSpace(value)
+	var spec PortSpec
+	if proto, rest, ok := strings.Cut(val, "/"); ok {
+		proto = strings.ToLower(proto)
+		if proto != "tcp" && proto != "udp" {
+			return PortSpec{}, fmt.Errorf("invalid port %q: protocol must be tcp or udp", value)
+		}
+		spec.Proto, val = proto, rest
+	}
+	if val == "" {
+		return PortSpec{}, fmt.Errorf("invalid port")
+	}
+
+	if lo, hi, ok := strings.Cut(val, "-"); ok && isDigits(lo) && isDigits(hi) {
+		low, _ := strconv.Atoi(lo)
+		high, _ := strconv.Atoi(hi)
+		if low < 1 || high > 65535 {
+			return PortSpec{}, fmt.Errorf("invalid port: must be between 1 and 65535")
+		}
+		if low > high {
+			return PortSpec{}, fmt.Errorf("invalid port range %q: %d is above %d", value, low, high)
+		}
+		spec.Low, spec.High = low, high
+		return spec, nil
+	}
+
+	port, err := strconv.Atoi(val)
+	if err != nil {
+		port, err = lookupService(val, spec.Proto)
+		if err != nil {
+			return PortSpec{}, fmt.Errorf("invalid port %q: not a number, range or known service name", value)
+		}
+	}
+	if port < 1 || port > 65535 {
+		return PortSpec{}, fmt.Errorf("invalid port: must be between 1 and 65535")
+	}
+	spec.Low, spec.High = port, port
+	return spec, nil
+}
+
+// lookupService finds the port of a service name in /etc/services (or the
+// platform's equivalent), for either protocol when proto is empty.
+func lookupService(name, proto string) (int, error) {
+	if proto != "" {
+		return net.LookupPort(proto, name)
+	}
+	port, err := net.LookupPort("tcp", name)
+	if err != nil {
+		port, err = net.LookupPort("udp", name)
+	}
+	return port, err
+}
+
+func isDigits(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
+// IsRange reports whether s covers more than one port.
+func (s PortSpec) IsRange() bool {
+	return s.High > s.Low
+}
+
+// String renders s the way it is written on the command line, with a
+// service name replaced by its number: "5432", "udp/53", "8000-8100".
+func (s PortSpec) String() string {
+	ports := strconv.Itoa(s.Low)
+	if s.IsRange() {
+		ports += "-" + strconv.Itoa(s.High)
+	}
+	if s.Proto != "" {
+		return s.Proto + "/" + ports
+	}
+	return ports
+}
+
+// describe names the ports of s in a sentence: "udp port 53",
+// "ports 8000-8100".
+func (s PortSpec) describe() string {
+	what := "port " + strconv.Itoa(s.Low)
+	if s.IsRange() {
+		what = fmt.Sprintf("ports %d-%d", s.Low, s.High)
+	}
+	if s.Proto != "" {
+		what = s.Proto + " " + what
+	}
+	return what
+}
+
+// Matches reports whether a socket of the given protocol (TCP, UDP6, ...)
+// on port falls within s.
+func (s PortSpec) Matches(port int, protocol string) bool {
+	if port < s.Low || port > s.High {
+		return false
+	}
+	return s.Proto == "" || strings.HasPrefix(strings.ToLower(protocol), s.Proto)
+}
+
+// BoundPorts returns the listening TCP and bound UDP sockets within s, one
+// per port, protocol, address and network namespace, ordered by port. A
+// socket several processes share, such as a listener inherited by forked
+// workers, is attributed to the lowest PID other than init.
+func BoundPorts(ctx context.Context, s PortSpec) ([]model.OpenPort, error) {
+	open, err := procpkg.ListOpenPorts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open ports: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var bound []model.OpenPort
+	for _, p := range open {
+		if !s.Matches(p.Port, p.Protocol) || !isBound(p) {
+			continue
+		}
+		i := slices.IndexFunc(bound, func(b model.OpenPort) bool {
+			return b.Port == p.Port && b.Protocol == p.Protocol && b.Address == p.Address && b.Netns == p.Netns
+		})
+		switch {
+		case i < 0:
+			bound = append(bound, p)
+		case bound[i].PID == 1 || (p.PID != 1 && p.PID < bound[i].PID):
+			bound[i].PID = p.PID
+		}
+	}
+	if len(bound) == 0 {
+		return nil, fmt.Errorf("no process listening on %s", s.describe())
+	}
+
+	slices.SortFunc(bound, func(a, b model.OpenPort) int {
+		if a.Port != b.Port {
+			return a.Port - b.Port
+		}
+		if c := strings.Compare(a.Protocol, b.Protocol); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Address, b.Address); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Netns, b.Netns)
+	})
+	return bound, nil
+}
+
+// isBound reports whether p serves the port: a TCP listener, or a UDP
+// socket that is not connected to one peer.
+func isBound(p model.OpenPort) bool {
+	if strings.HasPrefix(strings.ToUpper(p.Protocol), "UDP") {
+		return p.State != "ESTABLISHED"
+	}
+	return p.State == "LISTEN"
+}
+
+// resolvePortSpec returns the PIDs holding the ports of s, sorted. A plain
+// port goes through ResolvePort, which also finds connected sockets when
+// nothing listens.
+func resolvePortSpec(ctx context.Context, s PortSpec) ([]int, error) {
+	if !s.IsRange() && s.Proto == "" {
+		pids, err := ResolvePort(ctx, s.Low)
+		if err == nil {
+			pids = socketActivated(ctx, s, pids)
+		}
+		return pids, err
+	}
+	bound, err := BoundPorts(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	var pids []int
+	for _, p := range bound {
+		if !slices.Contains(pids, p.PID) {
+			pids = append(pids, p.PID)
+		}
+	}
+	slices.Sort(pids)
+	if !s.IsRange() {
+		pids = socketActivated(ctx, s, pids)
+	}
+	return pids, nil
+}
+
+// socketActivated replaces PID 1 among pids with the main PIDs of the
+// running service a systemd socket unit on the port of s activated: a
+// service started per connection (Accept=yes), or one that did not keep
+// the listening socket, leaves systemd as a holder of the port. The other
+// PIDs stay, and pids is returned as is when the service is not running.
+func socketActivated(ctx context.Context, s PortSpec, pids []int) []int {
+	if !slices.Contains(pids, 1) || !source.IsSystemdRunning() {
+		return pids
+	}
+	act, err := source.SocketActivation(ctx, s.Low, s.Proto)
+	if err != nil || act == nil || !act.Running {
+		return pids
+	}
+	out := slices.DeleteFunc(slices.Clone(pids), func(pid int) bool { return pid == 1 })
+	for _, pid := range act.MainPIDs {
+		if !slices.Contains(out, pid) {
+			out = append(out, pid)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
@@ -0,0 +1,48 @@
+//go:build linux
+
+package target
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestBoundPortsAgainstSyntheticProc(t *testing.T) {
+	host := syntheticHost()
+	// The checkpointer shares the postmaster's listener, and node also
+	// serves mDNS over UDP.
+	host["proc/311/fd/7"] = link("socket:[5001]")
+	host["proc/4242/fd/10"] = link("socket:[5003]")
+	host["proc/net/udp"] = &fstest.MapFile{Data: []byte(procNetTCPHeader +
+		"   0: 00000000:14E9 00000000:0000 07 00000000:00000000 00:00000000 00000000  1000        0 5003 2 0 0 0\n")}
+	useSyntheticProc(t, host)
+	ctx := context.Background()
+
+	bound, err := BoundPorts(ctx, PortSpec{Low: 1, High: 65535})
+	if err != nil {
+		t.Fatalf("BoundPorts: %v", err)
+	}
+	// The connected socket on 3000 is not bound to serve.
+	if len(bound) != 2 || bound[0].Port != 5353 || bound[0].PID != 4242 || bound[1].Port != 5432 || bound[1].PID != 310 {
+		t.Errorf("BoundPorts(1-65535) = %+v, want udp 5353 (4242) and tcp 5432 (310)", bound)
+	}
+
+	if bound, err := BoundPorts(ctx, PortSpec{Low: 1, High: 65535, Proto: "tcp"}); err != nil || len(bound) != 1 || bound[0].Port != 5432 {
+		t.Errorf("BoundPorts(tcp/1-65535) = %+v, %v; want only 5432", bound, err)
+	}
+	if _, err := BoundPorts(ctx, PortSpec{Low: 8000, High: 8100}); err == nil || !strings.Contains(err.Error(), "no process listening on ports 8000-8100") {
+		t.Errorf("an empty range should say so, got %v", err)
+	}
+
+	pids, err := resolvePortSpec(ctx, PortSpec{Low: 5000, High: 6000})
+	if err != nil || !reflect.DeepEqual(pids, []int{310, 4242}) {
+		t.Errorf("resolvePortSpec(5000-6000) = %v, %v; want [310 4242]", pids, err)
+	}
+	pids, err = resolvePortSpec(ctx, PortSpec{Low: 5353, High: 5353, Proto: "udp"})
+	if err != nil || !reflect.DeepEqual(pids, []int{4242}) {
+		t.Errorf("resolvePortSpec(udp/5353) = %v, %v; want [4242]", pids, err)
+	}
+}
@@ -0,0 +1,48 @@
+package target
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePort(t *testing.T) {
+	tests := []struct {
+		in   string
+		want PortSpec
+		str  string
+	}{
+		{"5432", PortSpec{Low: 5432, High: 5432}, "5432"},
+		{" 8000-8100 ", PortSpec{Low: 8000, High: 8100}, "8000-8100"},
+		{"udp/53", PortSpec{Low: 53, High: 53, Proto: "udp"}, "udp/53"},
+		{"TCP/8000-8001", PortSpec{Low: 8000, High: 8001, Proto: "tcp"}, "tcp/8000-8001"},
+		{"https", PortSpec{Low: 443, High: 443}, "443"},
+		{"udp/domain", PortSpec{Low: 53, High: 53, Proto: "udp"}, "udp/53"},
+		{"80-80", PortSpec{Low: 80, High: 80}, "80"},
+	}
+	for _, tt := range tests {
+		got, err := ParsePort(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParsePort(%q) = %+v, %v; want %+v", tt.in, got, err, tt.want)
+			continue
+		}
+		if got.String() != tt.str {
+			t.Errorf("ParsePort(%q).String() = %q, want %q", tt.in, got.String(), tt.str)
+		}
+	}
+
+	for _, bad := range []string{"", "0", "70000", "1-70000", "9000-8000", "sctp/80", "udp/", "no-such-service-here"} {
+		if _, err := ParsePort(bad); err == nil || !strings.Contains(err.Error(), "invalid port") {
+			t.Errorf("ParsePort(%q) = %v, want an invalid port error", bad, err)
+		}
+	}
+}
+
+func TestPortSpecMatches(t *testing.T) {
+	spec := PortSpec{Low: 50, High: 60, Proto: "udp"}
+	if !spec.Matches(53, "UDP6") || spec.Matches(53, "TCP") || spec.Matches(61, "UDP") {
+		t.Error("udp/50-60 should match UDP and UDP6 sockets in range only")
+	}
+	if any := (PortSpec{Low: 53, High: 53}); !any.Matches(53, "TCP") || !any.Matches(53, "UDP") {
+		t.Error("a spec without a protocol should match both")
+	}
+}
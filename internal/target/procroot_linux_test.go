@@ -0,0 +1,129 @@
+//go:build linux
+
+package target
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pranshuparmar/witr/internal/procfs"
+)
+
+const procNetTCPHeader = "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+
+// useSyntheticProc serves /proc from files for the rest of the test, the same
+// way --proc-root serves it from a mounted host.
+func useSyntheticProc(t *testing.T, files fstest.MapFS) {
+	t.Helper()
+	prev, prevLive := procfs.Current(), procfs.Live()
+	procfs.Set(procfs.FromFS(files), false)
+	t.Cleanup(func() { procfs.Set(prev, prevLive) })
+}
+
+func link(target string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(target), Mode: fs.ModeSymlink}
+}
+
+func syntheticHost() fstest.MapFS {
+	return fstest.MapFS{
+		"proc/1/stat":      {Data: []byte("1 (systemd) S 0 1 1 0 -1\n")},
+		"proc/1/comm":      {Data: []byte("systemd\n")},
+		"proc/1/cmdline":   {Data: []byte("/sbin/init\x00")},
+		"proc/1/fd/0":      link("/dev/null"),
+		"proc/2/stat":      {Data: []byte("2 (kthreadd) S 0 2 2 0 -1\n")},
+		"proc/2/comm":      {Data: []byte("kthreadd\n")},
+		"proc/2/cmdline":   {Data: nil},
+		"proc/310/stat":    {Data: []byte("310 (postgres) S 1 310 310 0 -1\n")},
+		"proc/310/comm":    {Data: []byte("postgres\n")},
+		"proc/310/cmdline": {Data: []byte("/usr/lib/postgresql/16/bin/postgres\x00-D\x00/var/lib/postgresql\x00")},
+		"proc/310/fd/5":    link("socket:[5001]"),
+		"proc/310/fd/6":    link("/var/lib/postgresql/postmaster.pid"),
+		"proc/311/stat":    {Data: []byte("311 (postgres) S 310 311 311 0 -1\n")},
+		"proc/311/comm":    {Data: []byte("postgres\n")},
+		"proc/311/cmdline": {Data: []byte("postgres: checkpointer\x00")},
+		"proc/311/fd/2":    link("/var/log/postgresql.log"),
+		// A 4-digit PID shared with nothing on the test host: the name
+		// resolver must not mistake it for witr itself.
+		"proc/4242/stat":    {Data: []byte("4242 (node) S 1 4242 4242 0 -1\n")},
+		"proc/4242/comm":    {Data: []byte("node\n")},
+		"proc/4242/cmdline": {Data: []byte("node\x00server.js\x00")},
+		"proc/4242/fd/9":    link("socket:[5002]"),
+		"proc/net/tcp": {Data: []byte(procNetTCPHeader +
+			"   0: 00000000:1538 00000000:0000 0A 00000000:00000000 00:00000000 00000000   113        0 5001 1 0 100 0 0 10 0\n" +
+			"   1: 0100007F:0BB8 0100007F:D431 01 00000000:00000000 00:00000000 00000000  1000        0 5002 1 0 20 4 30 10 -1\n")},
+		"proc/net/tcp6": {Data: []byte(procNetTCPHeader)},
+		"proc/net/udp":  {Data: []byte(procNetTCPHeader)},
+		"proc/net/udp6": {Data: []byte(procNetTCPHeader)},
+	}
+}
+
+func TestResolveAgainstSyntheticProc(t *testing.T) {
+	useSyntheticProc(t, syntheticHost())
+
+	t.Run("name", func(t *testing.T) {
+		pids, err := ResolveName(context.Background(), "postgres", false)
+		if err != nil {
+			t.Fatalf("ResolveName: %v", err)
+		}
+		if want := []int{310, 311}; !reflect.DeepEqual(pids, want) {
+			t.Errorf("ResolveName(postgres) = %v, want %v", pids, want)
+		}
+	})
+
+	t.Run("name not found skips systemd", func(t *testing.T) {
+		if _, err := ResolveName(context.Background(), "nginx", false); err == nil {
+			t.Error("ResolveName(nginx) should fail on a tree without nginx")
+		}
+	})
+
+	t.Run("listening port", func(t *testing.T) {
+		pids, err := ResolvePort(context.Background(), 5432)
+		if err != nil {
+			t.Fatalf("ResolvePort(5432): %v", err)
+		}
+		if want := []int{310}; !reflect.DeepEqual(pids, want) {
+			t.Errorf("ResolvePort(5432) = %v, want %v", pids, want)
+		}
+	})
+
+	t.Run("connected port falls back", func(t *testing.T) {
+		pids, err := ResolvePort(context.Background(), 3000)
+		if err != nil {
+			t.Fatalf("ResolvePort(3000): %v", err)
+		}
+		if want := []int{4242}; !reflect.DeepEqual(pids, want) {
+			t.Errorf("ResolvePort(3000) = %v, want %v", pids, want)
+		}
+	})
+
+	t.Run("unknown port", func(t *testing.T) {
+		_, err := ResolvePort(context.Background(), 9999)
+		if err == nil || errors.Is(err, ErrSocketOwnerUnknown) {
+			t.Errorf("ResolvePort(9999) = %v, want a not-listening error", err)
+		}
+	})
+
+	t.Run("file", func(t *testing.T) {
+		pids, err := ResolveFile(context.Background(), "/var/log/postgresql.log")
+		if err != nil {
+			t.Fatalf("ResolveFile: %v", err)
+		}
+		if want := []int{311}; !reflect.DeepEqual(pids, want) {
+			t.Errorf("ResolveFile = %v, want %v", pids, want)
+		}
+	})
+}
+
+func TestResolvePortSocketOwnerUnknown(t *testing.T) {
+	files := syntheticHost()
+	delete(files, "proc/310/fd/5")
+	useSyntheticProc(t, files)
+
+	if _, err := ResolvePort(context.Background(), 5432); !errors.Is(err, ErrSocketOwnerUnknown) {
+		t.Errorf("ResolvePort(5432) with no owning fd = %v, want ErrSocketOwnerUnknown", err)
+	}
+}
@@ -0,0 +1,66 @@
+package target
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/procfs"
+)
+
+// CompileRegex compiles the pattern of a --regex target.
+func CompileRegex(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --regex %q: %w", pattern, err)
+	}
+	return re, nil
+}
+
+// ResolveRegex returns the PIDs whose name or command line pattern matches,
+// sorted. Unlike a name, the pattern is case-sensitive; (?i) makes it not.
+// witr itself and its ancestors are never matched.
+func ResolveRegex(ctx context.Context, pattern string) ([]int, error) {
+	re, err := CompileRegex(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := procpkg.IndexFrom(ctx)
+	procs, err := idx.Processes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+
+	// witr and its ancestors only exist in the live process table; under a
+	// proc root or snapshot, the same PIDs belong to unrelated processes.
+	ignored := map[int]bool{}
+	if procfs.Live() {
+		self := os.Getpid()
+		ignored[self] = true
+		if lineage, err := idx.Lineage(self); err == nil {
+			for _, p := range lineage {
+				ignored[p.PID] = true
+			}
+		}
+	}
+
+	var pids []int
+	for _, p := range procs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if ignored[p.PID] {
+			continue
+		}
+		if re.MatchString(p.Command) || re.MatchString(idx.Cmdline(p.PID)) {
+			pids = append(pids, p.PID)
+		}
+	}
+	if len(pids) == 0 {
+		return nil, fmt.Errorf("no running process matches %q", pattern)
+	}
+	return pids, nil
+}
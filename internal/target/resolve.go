@@ -1,7 +1,9 @@
 package target
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
 
@@ -28,7 +30,38 @@ func matchesExactToken(cmdline, name string) bool {
 	return false
 }
 
-func Resolve(t model.Target, exact bool) ([]int, error) {
+// ParseEndpoint reads the host:port of a --connection target. The host is an
+// IP address ([::1] for IPv6) or a name, which is looked up.
+func ParseEndpoint(ctx context.Context, endpoint string) ([]net.IP, int, error) {
+	host, portStr, err := net.SplitHostPort(endpoint)
+	if err != nil || host == "" {
+		return nil, 0, fmt.Errorf("invalid connection %q: want host:port", endpoint)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port < 1 || port > 65535 {
+		return nil, 0, fmt.Errorf("invalid connection %q: port must be between 1 and 65535", endpoint)
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, port, nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		return nil, 0, fmt.Errorf("host %q not found: %w", host, err)
+	}
+	ips := make([]net.IP, len(addrs))
+	for i, a := range addrs {
+		ips[i] = a.IP
+	}
+	return ips, port, nil
+}
+
+// Resolve returns the PIDs t matches. Scans of the process table and the
+// lsof/netstat/sockstat calls behind it stop with ctx's error once ctx is
+// done.
+func Resolve(ctx context.Context, t model.Target, exact bool) ([]int, error) {
 	val := strings.TrimSpace(t.Value)
 
 	switch t.Type {
@@ -43,20 +76,33 @@ func Resolve(t model.Target, exact bool) ([]int, error) {
 		return []int{pid}, nil
 
 	case model.TargetPort:
-		port, err := strconv.Atoi(val)
+		spec, err := ParsePort(val)
 		if err != nil {
-			return nil, fmt.Errorf("invalid port")
+			return nil, err
 		}
-		if port < 1 || port > 65535 {
-			return nil, fmt.Errorf("invalid port: must be between 1 and 65535")
-		}
-		return ResolvePort(port)
+		return resolvePortSpec(ctx, spec)
 
 	case model.TargetName:
-		return ResolveName(val, exact)
+		return ResolveName(ctx, val, exact)
 
 	case model.TargetFile:
-		return ResolveFile(val)
+		return ResolveFile(ctx, val)
+
+	case model.TargetRegex:
+		return ResolveRegex(ctx, t.Value)
+
+	case model.TargetConnection:
+		ips, port, err := ParseEndpoint(ctx, val)
+		if err != nil {
+			return nil, err
+		}
+		return ResolveConnection(ctx, ips, port)
+
+	case model.TargetSocket:
+		if val == "" {
+			return nil, fmt.Errorf("invalid socket: empty path")
+		}
+		return ResolveSocket(ctx, val)
 
 	default:
 		return nil, fmt.Errorf("unknown target")
@@ -3,6 +3,8 @@
 package target
 
 import (
+	"context"
+	"net"
 	"testing"
 
 	"github.com/pranshuparmar/witr/pkg/model"
@@ -30,7 +32,7 @@ func TestResolveWithExactFlag(t *testing.T) {
 				Value: "test",
 			}
 
-			_, err := Resolve(target, tt.exact)
+			_, err := Resolve(context.Background(), target, tt.exact)
 
 			if err == nil {
 				t.Log("Resolve returned successfully (may have found matches)")
@@ -58,7 +60,7 @@ func TestResolvePIDWithExactFlag(t *testing.T) {
 				Value: tt.pid,
 			}
 
-			pids, err := Resolve(target, tt.exact)
+			pids, err := Resolve(context.Background(), target, tt.exact)
 			if err != nil {
 				t.Fatalf("Resolve failed: %v", err)
 			}
@@ -161,7 +163,7 @@ func TestResolvePortWithExactFlag(t *testing.T) {
 				Value: tt.port,
 			}
 
-			_, err := Resolve(target, tt.exact)
+			_, err := Resolve(context.Background(), target, tt.exact)
 
 			if err == nil {
 				t.Log("Resolve returned successfully (may have found matches)")
@@ -171,3 +173,28 @@ func TestResolvePortWithExactFlag(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSourceType(t *testing.T) {
+	if st, err := ParseSourceType("Supervisor"); err != nil || st != model.SourceSupervisor {
+		t.Errorf("ParseSourceType(Supervisor) = %q, %v", st, err)
+	}
+	if _, err := ParseSourceType("upstart"); err == nil {
+		t.Error("an unknown source type should be rejected")
+	}
+}
+
+func TestParseEndpoint(t *testing.T) {
+	ctx := context.Background()
+	ips, port, err := ParseEndpoint(ctx, "10.2.3.4:5432")
+	if err != nil || port != 5432 || len(ips) != 1 || !ips[0].Equal(net.ParseIP("10.2.3.4")) {
+		t.Errorf("ParseEndpoint(10.2.3.4:5432) = %v, %d, %v", ips, port, err)
+	}
+	if ips, port, err := ParseEndpoint(ctx, "[::1]:80"); err != nil || port != 80 || !ips[0].Equal(net.IPv6loopback) {
+		t.Errorf("ParseEndpoint([::1]:80) = %v, %d, %v", ips, port, err)
+	}
+	for _, bad := range []string{"10.2.3.4", ":5432", "10.2.3.4:0", "10.2.3.4:http"} {
+		if _, _, err := ParseEndpoint(ctx, bad); err == nil {
+			t.Errorf("ParseEndpoint(%q) should fail", bad)
+		}
+	}
+}
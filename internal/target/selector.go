@@ -0,0 +1,248 @@
+package target
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/procfs"
+	"github.com/pranshuparmar/witr/internal/source"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Selector narrows the processes a target matched before they are analyzed.
+// Every field that is set must hold for a process to be kept; the zero
+// Selector keeps them all.
+type Selector struct {
+	// User is the owner's name, as the User line shows it.
+	User string
+	// Source is what started the process: systemd, cron, shell, ...
+	Source model.SourceType
+	// InContainer names a container the process must run in, matched like
+	// a -c lookup.
+	InContainer string
+	// Cwd is a directory the process's working directory must be or be
+	// under.
+	Cwd string
+	// Env holds KEY=VALUE pairs the environment must contain, or a bare KEY
+	// that must only be set.
+	Env []string
+	// Netns is the inode of the network namespace the process must be in
+	// (Linux).
+	Netns string
+}
+
+type allNetnsKey struct{}
+
+// WithAllNetns returns a copy of ctx under which a port is looked up in every
+// network namespace, even when witr's own has a socket on it. A Netns
+// selector needs it: the socket it keeps may be in a namespace the lookup
+// would otherwise pass over.
+func WithAllNetns(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allNetnsKey{}, true)
+}
+
+// allNetns reports whether ctx came from WithAllNetns.
+func allNetns(ctx context.Context) bool {
+	all, _ := ctx.Value(allNetnsKey{}).(bool)
+	return all
+}
+
+// sourceTypes are the values --source accepts.
+var sourceTypes = []model.SourceType{
+	model.SourceContainer, model.SourceSystemd, model.SourceLaunchd,
+	model.SourceBsdRc, model.SourceSupervisor, model.SourceCron,
+	model.SourceSSH, model.SourceShell, model.SourceWindowsService,
+	model.SourceInit, model.SourceUnknown,
+}
+
+// ParseSourceType checks s against the source types witr detects.
+func ParseSourceType(s string) (model.SourceType, error) {
+	st := model.SourceType(strings.ToLower(strings.TrimSpace(s)))
+	if slices.Contains(sourceTypes, st) {
+		return st, nil
+	}
+	names := make([]string, len(sourceTypes))
+	for i, t := range sourceTypes {
+		names[i] = string(t)
+	}
+	return "", fmt.Errorf("invalid --source %q: want one of %s", s, strings.Join(names, ", "))
+}
+
+// Empty reports whether s keeps every process.
+func (s Selector) Empty() bool {
+	return s.User == "" && s.Source == "" && s.InContainer == "" && s.Cwd == "" && len(s.Env) == 0 && s.Netns == ""
+}
+
+// String describes s for messages: "user app, source supervisor".
+func (s Selector) String() string {
+	var parts []string
+	if s.User != "" {
+		parts = append(parts, "user "+s.User)
+	}
+	if s.Source != "" {
+		parts = append(parts, "source "+string(s.Source))
+	}
+	if s.InContainer != "" {
+		parts = append(parts, "in container "+s.InContainer)
+	}
+	if s.Cwd != "" {
+		parts = append(parts, "cwd "+s.Cwd)
+	}
+	for _, kv := range s.Env {
+		parts = append(parts, "env "+kv)
+	}
+	if s.Netns != "" {
+		parts = append(parts, "netns "+s.Netns)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Filter returns the pids s keeps, in order. The cheap checks run first, so
+// a process is only traced to its source or container when its user,
+// directory and environment already match. exact applies to InContainer as
+// it does to a -c lookup.
+func Filter(ctx context.Context, pids []int, s Selector, exact bool) ([]int, error) {
+	if s.Empty() {
+		return pids, nil
+	}
+
+	var containerIDs []string
+	if s.InContainer != "" {
+		for _, m := range procpkg.ResolveContainer(ctx, s.InContainer, exact) {
+			containerIDs = append(containerIDs, m.ID)
+		}
+		if len(containerIDs) == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("no container found matching %q", s.InContainer)
+		}
+	}
+
+	idx := procpkg.IndexFrom(ctx)
+	var kept []int
+	for _, pid := range pids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		p, err := idx.Process(pid)
+		if err != nil {
+			continue
+		}
+		if s.User != "" && !sameUser(p.User, s.User) {
+			continue
+		}
+		if s.Cwd != "" && !underDir(p.WorkingDir, s.Cwd) {
+			continue
+		}
+		if !hasEnv(p.Env, s.Env) {
+			continue
+		}
+		if s.Netns != "" && procpkg.NetnsOf(pid) != s.Netns {
+			continue
+		}
+		if containerIDs != nil && !slices.ContainsFunc(containerIDs, func(id string) bool {
+			return procpkg.PIDBelongsToContainer(pid, id)
+		}) {
+			continue
+		}
+		if s.Source != "" {
+			ancestry, err := idx.Ancestry(pid)
+			if err != nil || source.Detect(ctx, ancestry).Type != s.Source {
+				continue
+			}
+		}
+		kept = append(kept, pid)
+	}
+
+	if len(kept) == 0 {
+		return nil, fmt.Errorf("no matching process with %s (%d before filtering)", s, len(pids))
+	}
+	return kept, nil
+}
+
+// ParseNetns reads a --netns value down to the namespace's inode: the
+// inode itself (4026532289), a namespace link's text (net:[4026532289]), a
+// file a namespace is mounted on (/run/netns/blue), or the name "ip netns"
+// gave it (blue). A path or name is looked up in the analyzed system, so
+// under a proc root it is the root's /run/netns; a snapshot records none.
+func ParseNetns(value string) (string, error) {
+	if runtime.GOOS != "linux" {
+		return "", fmt.Errorf("invalid --netns: network namespaces are Linux only")
+	}
+	v := strings.TrimSpace(value)
+	if rest, ok := strings.CutPrefix(v, "net:["); ok {
+		v = strings.TrimSuffix(rest, "]")
+	}
+	if v != "" && strings.Trim(v, "0123456789") == "" {
+		return v, nil
+	}
+	if v == "" || v == "." || v == ".." {
+		return "", fmt.Errorf("invalid --netns %q", value)
+	}
+
+	path := v
+	if !strings.ContainsRune(v, '/') {
+		path = filepath.Join("/run/netns", v)
+	}
+	info, err := procfs.Stat(path)
+	if err != nil {
+		if !procfs.Live() {
+			return "", fmt.Errorf("invalid --netns %q: no such namespace in the analyzed system; give its inode", value)
+		}
+		return "", fmt.Errorf("invalid --netns %q: no such namespace", value)
+	}
+	id, ok := procfs.StatID(info)
+	if !ok {
+		return "", fmt.Errorf("invalid --netns %q: not a namespace", value)
+	}
+	return strconv.FormatUint(id.Ino, 10), nil
+}
+
+// sameUser compares a process owner with --user. A Windows owner
+// ("HOST\alice") also matches its bare account name.
+func sameUser(owner, want string) bool {
+	if owner == want {
+		return true
+	}
+	if i := strings.LastIndex(owner, `\`); i >= 0 {
+		return strings.EqualFold(owner[i+1:], want) || strings.EqualFold(owner, want)
+	}
+	return false
+}
+
+// underDir reports whether dir is root or inside it.
+func underDir(dir, root string) bool {
+	if dir == "" || dir == "unknown" {
+		return false
+	}
+	dir, root = filepath.Clean(dir), filepath.Clean(root)
+	if dir == root {
+		return true
+	}
+	if !strings.HasSuffix(root, string(filepath.Separator)) {
+		root += string(filepath.Separator)
+	}
+	return strings.HasPrefix(dir, root)
+}
+
+// hasEnv reports whether env, a process's KEY=VALUE list, holds every
+// selector in want.
+func hasEnv(env, want []string) bool {
+	for _, w := range want {
+		key, val, withVal := strings.Cut(w, "=")
+		if !slices.ContainsFunc(env, func(kv string) bool {
+			k, v, _ := strings.Cut(kv, "=")
+			return k == key && (!withVal || v == val)
+		}) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,172 @@
+//go:build linux
+
+package target
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pranshuparmar/witr/internal/procfs"
+)
+
+func TestRegexAndSelectorAgainstSyntheticProc(t *testing.T) {
+	host := syntheticHost()
+	// ReadProcess needs the full stat line, up to the start time.
+	host["proc/1/stat"] = &fstest.MapFile{Data: []byte("1 (systemd) S 0 1 1 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 10 0 0\n")}
+	host["proc/310/stat"] = &fstest.MapFile{Data: []byte("310 (postgres) S 1 310 310 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 500 0 0\n")}
+	host["proc/311/stat"] = &fstest.MapFile{Data: []byte("311 (postgres) S 310 311 311 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 505 0 0\n")}
+	host["proc/4242/stat"] = &fstest.MapFile{Data: []byte("4242 (node) S 1 4242 4242 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 900 0 0\n")}
+	host["proc/310/status"] = &fstest.MapFile{Data: []byte("Name:\tpostgres\nUid:\t0\t0\t0\t0\n")}
+	host["proc/310/cwd"] = link("/var/lib/postgresql")
+	host["proc/310/environ"] = &fstest.MapFile{Data: []byte("PGDATA=/var/lib/postgresql\x00LANG=C\x00")}
+	host["proc/311/status"] = &fstest.MapFile{Data: []byte("Name:\tpostgres\nUid:\t0\t0\t0\t0\n")}
+	host["proc/311/cwd"] = link("/")
+	host["proc/4242/status"] = &fstest.MapFile{Data: []byte("Name:\tnode\nUid:\t4242\t4242\t4242\t4242\n")}
+	host["proc/4242/cwd"] = link("/srv/app")
+	host["proc/4242/environ"] = &fstest.MapFile{Data: []byte("NODE_ENV=production\x00")}
+	useSyntheticProc(t, host)
+	ctx := context.Background()
+
+	pids, err := ResolveRegex(ctx, `^postgres: \w+$`)
+	if err != nil || !reflect.DeepEqual(pids, []int{311}) {
+		t.Errorf("ResolveRegex(checkpointer) = %v, %v; want [311]", pids, err)
+	}
+	pids, err = ResolveRegex(ctx, `postgres|node`)
+	if err != nil || !reflect.DeepEqual(pids, []int{310, 311, 4242}) {
+		t.Errorf("ResolveRegex(postgres|node) = %v, %v; want [310 311 4242]", pids, err)
+	}
+	if _, err := ResolveRegex(ctx, `Postgres`); err == nil {
+		t.Error("a regex is case-sensitive")
+	}
+	if _, err := ResolveRegex(ctx, `(`); err == nil || !strings.Contains(err.Error(), "invalid --regex") {
+		t.Errorf("a bad pattern should be reported as such, got %v", err)
+	}
+
+	all := []int{310, 311, 4242}
+	tests := []struct {
+		name string
+		sel  Selector
+		want []int
+	}{
+		{"none", Selector{}, all},
+		{"user", Selector{User: "root"}, []int{310, 311}},
+		{"cwd", Selector{Cwd: "/var/lib"}, []int{310}},
+		{"cwd is a directory, not a prefix", Selector{Cwd: "/srv/ap"}, nil},
+		{"env key", Selector{Env: []string{"NODE_ENV"}}, []int{4242}},
+		{"env value", Selector{Env: []string{"PGDATA=/var/lib/postgresql", "LANG=C"}}, []int{310}},
+		{"env wrong value", Selector{Env: []string{"LANG=en_US.UTF-8"}}, nil},
+		{"composed", Selector{User: "root", Cwd: "/"}, []int{310, 311}},
+	}
+	for _, tt := range tests {
+		got, err := Filter(ctx, all, tt.sel, false)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Filter = %v, %v; want %v", tt.name, got, err, tt.want)
+		}
+		if tt.want == nil && (err == nil || !strings.Contains(err.Error(), "no matching process")) {
+			t.Errorf("%s: Filter keeping nothing should say so, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestNetnsAgainstSyntheticProc(t *testing.T) {
+	host := syntheticHost()
+	host["proc/self/ns/net"] = link("net:[4026531840]")
+	host["proc/310/ns/net"] = link("net:[4026531840]")
+	host["proc/310/stat"] = &fstest.MapFile{Data: []byte("310 (postgres) S 1 310 310 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 500 0 0\n")}
+	// A containerized node listening on 8080, unpublished: only its own
+	// namespace's table has the port.
+	host["proc/4242/stat"] = &fstest.MapFile{Data: []byte("4242 (node) S 1 4242 4242 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 900 0 0\n")}
+	host["proc/4242/ns/net"] = link("net:[4026532289]")
+	host["proc/4242/fd/11"] = link("socket:[6001]")
+	host["proc/4242/net/tcp"] = &fstest.MapFile{Data: []byte(procNetTCPHeader +
+		"   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 6001 1 0 100 0 0 10 0\n")}
+	useSyntheticProc(t, host)
+	ctx := context.Background()
+
+	pids, err := ResolvePort(ctx, 8080)
+	if err != nil || !reflect.DeepEqual(pids, []int{4242}) {
+		t.Errorf("ResolvePort(8080) = %v, %v; want the containerized node", pids, err)
+	}
+
+	// With a listener on 8080 in witr's own namespace too, that one is
+	// the answer rather than a choice between both.
+	withHost := maps.Clone(host)
+	withHost["proc/310/fd/7"] = link("socket:[5003]")
+	withHost["proc/net/tcp"] = &fstest.MapFile{Data: append(slices.Clone(host["proc/net/tcp"].Data),
+		"   2: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000   999        0 5003 1 0 100 0 0 10 0\n"...)}
+	useSyntheticProc(t, withHost)
+	if pids, err := ResolvePort(ctx, 8080); err != nil || !reflect.DeepEqual(pids, []int{310}) {
+		t.Errorf("ResolvePort(8080) with a host listener = %v, %v; want only the host's", pids, err)
+	}
+	// Unless a --netns selector is to pick the container's.
+	pids, err = ResolvePort(WithAllNetns(ctx), 8080)
+	if err != nil || !reflect.DeepEqual(pids, []int{310, 4242}) {
+		t.Errorf("ResolvePort(8080) in every namespace = %v, %v; want both listeners", pids, err)
+	}
+	if got, err := Filter(ctx, pids, Selector{Netns: "4026532289"}, false); err != nil || !reflect.DeepEqual(got, []int{4242}) {
+		t.Errorf("Filter(netns) with a host listener = %v, %v; want [4242]", got, err)
+	}
+	useSyntheticProc(t, host)
+
+	got, err := Filter(ctx, []int{310, 4242}, Selector{Netns: "4026532289"}, false)
+	if err != nil || !reflect.DeepEqual(got, []int{4242}) {
+		t.Errorf("Filter(netns) = %v, %v; want [4242]", got, err)
+	}
+	if _, err := Filter(ctx, []int{310}, Selector{Netns: "4026532289"}, false); err == nil || !strings.Contains(err.Error(), "netns 4026532289") {
+		t.Errorf("Filter keeping nothing should name the namespace, got %v", err)
+	}
+}
+
+func TestParseNetns(t *testing.T) {
+	for in, want := range map[string]string{"4026532289": "4026532289", "net:[4026532289]": "4026532289", " 42 ": "42"} {
+		if got, err := ParseNetns(in); err != nil || got != want {
+			t.Errorf("ParseNetns(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	// A named namespace is the inode of the file it is mounted on.
+	dir := t.TempDir()
+	if got, err := ParseNetns(dir); err != nil || got == "" {
+		t.Errorf("ParseNetns(path) = %q, %v; want its inode", got, err)
+	}
+	for _, bad := range []string{"", "net:[]", "witr-no-such-netns"} {
+		if _, err := ParseNetns(bad); err == nil || !strings.Contains(err.Error(), "invalid --netns") {
+			t.Errorf("ParseNetns(%q) = %v, want an invalid --netns error", bad, err)
+		}
+	}
+
+	// Under a proc root, a name is the root's /run/netns entry, not the
+	// host's.
+	root := t.TempDir()
+	for _, d := range []string{"proc", "run/netns"} {
+		if err := os.MkdirAll(filepath.Join(root, d), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "run/netns/witr-test-blue"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(filepath.Join(root, "run/netns/witr-test-blue"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := procfs.StatID(info)
+	prev, prevLive := procfs.Current(), procfs.Live()
+	t.Cleanup(func() { procfs.Set(prev, prevLive) })
+	if err := procfs.SetRoot(root); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ParseNetns("witr-test-blue"); err != nil || got != strconv.FormatUint(id.Ino, 10) {
+		t.Errorf("ParseNetns(name) under a proc root = %q, %v; want %d", got, err, id.Ino)
+	}
+	if _, err := ParseNetns("witr-no-such-netns"); err == nil || !strings.Contains(err.Error(), "give its inode") {
+		t.Errorf("ParseNetns of an unknown name under a proc root = %v, want a hint to give the inode", err)
+	}
+}
@@ -0,0 +1,116 @@
+//go:build linux
+
+package target
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/procfs"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// ResolveSocket returns the PIDs serving the unix socket bound to path: the
+// listener of a stream or seqpacket socket, or the owner of a bound datagram
+// socket. path is a filesystem path, or "@name" for the abstract namespace.
+func ResolveSocket(ctx context.Context, path string) ([]int, error) {
+	bound, err := unixSocketsAt(path)
+	if err != nil {
+		return nil, err
+	}
+	inodes := make(map[string]bool)
+	for _, s := range bound {
+		if s.State != "ESTABLISHED" {
+			inodes[s.Inode] = true
+		}
+	}
+	if len(inodes) == 0 {
+		return nil, fmt.Errorf("no process listening on unix socket %s", path)
+	}
+
+	pids, err := pidsHoldingSockets(ctx, inodes)
+	if err != nil {
+		return nil, err
+	}
+	if len(pids) == 0 {
+		return nil, ErrSocketOwnerUnknown
+	}
+	return pids, nil
+}
+
+// ResolveSocketClients returns the PIDs connected to the unix socket bound
+// to path. The kernel records the other end of each connection the listener
+// accepted; that end's inode leads to the client. It needs the live system.
+func ResolveSocketClients(ctx context.Context, path string) ([]int, error) {
+	if !procfs.Live() {
+		return nil, fmt.Errorf("finding the clients of a unix socket needs the live system")
+	}
+	bound, err := unixSocketsAt(path)
+	if err != nil {
+		return nil, err
+	}
+	peers, err := procpkg.UnixPeers()
+	if err != nil {
+		return nil, err
+	}
+	clients := make(map[string]bool)
+	for _, s := range bound {
+		if s.State != "ESTABLISHED" {
+			continue
+		}
+		if peer, ok := peers[s.Inode]; ok {
+			clients[peer] = true
+		}
+	}
+	if len(clients) == 0 {
+		return nil, fmt.Errorf("no process connected to unix socket %s", path)
+	}
+
+	pids, err := pidsHoldingSockets(ctx, clients)
+	if err != nil {
+		return nil, err
+	}
+	if len(pids) == 0 {
+		return nil, ErrSocketOwnerUnknown
+	}
+	return pids, nil
+}
+
+// unixSocketsAt returns the sockets in /proc/net/unix bound to path. On the
+// live system a path reached through a symlink (/var/run is often /run)
+// matches the path the socket was bound to.
+func unixSocketsAt(path string) ([]model.Socket, error) {
+	all, err := procpkg.UnixSockets()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read unix sockets: %w", err)
+	}
+
+	abstract := strings.HasPrefix(path, "@")
+	want := path
+	if !abstract {
+		want = filepath.Clean(path)
+	}
+	resolved := ""
+	if !abstract && procfs.Live() {
+		resolved, _ = filepath.EvalSymlinks(want)
+	}
+
+	var bound []model.Socket
+	for _, s := range all {
+		match := s.Path == want
+		if !match && resolved != "" && !strings.HasPrefix(s.Path, "@") && filepath.Base(s.Path) == filepath.Base(resolved) {
+			r, err := filepath.EvalSymlinks(s.Path)
+			match = err == nil && r == resolved
+		}
+		if match {
+			bound = append(bound, s)
+		}
+	}
+	if len(bound) == 0 {
+		return nil, fmt.Errorf("no process listening on unix socket %s", path)
+	}
+	return bound, nil
+}
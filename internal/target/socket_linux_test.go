@@ -0,0 +1,37 @@
+//go:build linux
+
+package target
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestResolveSocketAgainstSyntheticProc(t *testing.T) {
+	host := syntheticHost()
+	host["proc/net/unix"] = &fstest.MapFile{Data: []byte("Num       RefCount Protocol Flags    Type St Inode Path\n" +
+		"0000000000000000: 00000002 00000000 00010000 0001 01 6001 /run/postgresql/.s.PGSQL.5432\n" +
+		"0000000000000000: 00000003 00000000 00000000 0001 03 6002 /run/postgresql/.s.PGSQL.5432\n" +
+		"0000000000000000: 00000002 00000000 00010000 0001 01 6003 /run/orphan.sock\n")}
+	host["proc/310/fd/7"] = link("socket:[6001]")
+	host["proc/311/fd/3"] = link("socket:[6002]") // a connection, not the listener
+	useSyntheticProc(t, host)
+	ctx := context.Background()
+
+	pids, err := ResolveSocket(ctx, "/run/postgresql/.s.PGSQL.5432")
+	if err != nil || !reflect.DeepEqual(pids, []int{310}) {
+		t.Errorf("ResolveSocket = %v, %v; want the listener, [310]", pids, err)
+	}
+	if _, err := ResolveSocket(ctx, "/run/nothing.sock"); err == nil {
+		t.Error("ResolveSocket should fail for a path nothing is bound to")
+	}
+	if _, err := ResolveSocket(ctx, "/run/orphan.sock"); !errors.Is(err, ErrSocketOwnerUnknown) {
+		t.Errorf("a socket no visible process holds should be ErrSocketOwnerUnknown, got %v", err)
+	}
+	if _, err := ResolveSocketClients(ctx, "/run/postgresql/.s.PGSQL.5432"); err == nil {
+		t.Error("the clients of a socket cannot be found from a proc tree")
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !linux
+
+package target
+
+import (
+	"context"
+	"fmt"
+)
+
+// ResolveSocket looks up a unix socket by path. Only Linux lists unix
+// sockets with the inodes that tie them to processes.
+func ResolveSocket(ctx context.Context, path string) ([]int, error) {
+	return nil, fmt.Errorf("unix socket lookup is %w", ErrUnsupported)
+}
+
+// ResolveSocketClients is ResolveSocket for the clients of the socket.
+func ResolveSocketClients(ctx context.Context, path string) ([]int, error) {
+	return nil, fmt.Errorf("unix socket lookup is %w", ErrUnsupported)
+}
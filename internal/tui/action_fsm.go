@@ -3,6 +3,8 @@ package tui
 import (
 	"fmt"
 	"strings"
+
+	"github.com/pranshuparmar/witr/internal/procfs"
 )
 
 // confirmDecision is the outcome of a key press at the kill/term/pause/resume
@@ -15,6 +17,13 @@ const (
 	confirmCancel                         // user declined (n / esc)
 )
 
+// actionsAvailable reports whether the action menu can be offered: the
+// platform supports signals and the processes shown belong to the live host
+// rather than a proc root or snapshot, whose PIDs mean nothing here.
+func actionsAvailable() bool {
+	return actionsSupported && procfs.Live()
+}
+
 // actionMenuSelect maps a key pressed while the action menu is open to the
 // resulting pending action and whether the menu should close. close==true with
 // pending==actionNone means the menu was cancelled; close==false means the key
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"cmp"
+	"context"
 	"fmt"
 	"os"
 	"sort"
@@ -15,6 +16,7 @@ import (
 	"github.com/pranshuparmar/witr/internal/output"
 	"github.com/pranshuparmar/witr/internal/pipeline"
 	"github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/procfs"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
@@ -25,7 +27,11 @@ func (m MainModel) refreshProcesses() tea.Cmd {
 			return err
 		}
 
-		selfPID := os.Getpid()
+		// witr only appears in the live process table.
+		selfPID := -1
+		if procfs.Live() {
+			selfPID = os.Getpid()
+		}
 		filteredProcs := make([]model.Process, 0, len(procs))
 		for _, p := range procs {
 			if p.PID == selfPID {
@@ -52,7 +58,7 @@ func (m MainModel) refreshPorts() tea.Cmd {
 
 func (m MainModel) refreshContainers() tea.Cmd {
 	return func() tea.Msg {
-		return proc.ListAllContainers()
+		return proc.ListAllContainers(context.Background())
 	}
 }
 
@@ -94,13 +100,14 @@ func mergeLocksAndOpenFiles(locks, opens []*model.LockedFile) []*model.LockedFil
 // returning the bare ContainerMatch for the runtime-info render.
 func (m MainModel) fetchContainerDetail(match *model.ContainerMatch) tea.Cmd {
 	return func() tea.Msg {
-		proc.EnrichContainer(match)
-		pid := proc.ResolveContainerHostPID(match.Runtime, match.ID)
+		proc.EnrichContainer(context.Background(), match)
+		pid := proc.ResolveContainerHostPID(context.Background(), match.Runtime, match.ID)
 		if pid > 0 && proc.PIDBelongsToContainer(pid, match.ID) {
-			res, err := pipeline.AnalyzePID(pipeline.AnalyzeConfig{
+			res, err := pipeline.AnalyzePID(context.Background(), pipeline.AnalyzeConfig{
 				PID:     pid,
 				Verbose: true,
 				Tree:    true,
+				Clients: true,
 			})
 			if err == nil {
 				res.Process.Container = output.FormatContainerLine(match)
@@ -116,7 +123,7 @@ func (m MainModel) fetchContainerDetail(match *model.ContainerMatch) tea.Cmd {
 
 func (m MainModel) fetchTree(p model.Process) tea.Cmd {
 	return func() tea.Msg {
-		res, err := pipeline.AnalyzePID(pipeline.AnalyzeConfig{
+		res, err := pipeline.AnalyzePID(context.Background(), pipeline.AnalyzeConfig{
 			PID:     p.PID,
 			Verbose: false,
 			Tree:    true,
@@ -132,10 +139,11 @@ func (m MainModel) fetchTree(p model.Process) tea.Cmd {
 
 func (m MainModel) fetchProcessDetail(pid int) tea.Cmd {
 	return func() tea.Msg {
-		res, err := pipeline.AnalyzePID(pipeline.AnalyzeConfig{
+		res, err := pipeline.AnalyzePID(context.Background(), pipeline.AnalyzeConfig{
 			PID:     pid,
 			Verbose: true,
 			Tree:    true,
+			Clients: true,
 		})
 		if err != nil {
 			return err
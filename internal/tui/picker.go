@@ -0,0 +1,124 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pranshuparmar/witr/internal/output"
+)
+
+// PickerItem is one process offered by Pick.
+type PickerItem struct {
+	PID     int // 0 when unknown, e.g. a container whose process is not visible
+	User    string
+	Started time.Time
+	Source  string
+	Cmdline string
+}
+
+// pickerMaxRows is the most rows Pick shows at once; the table scrolls past it.
+const pickerMaxRows = 10
+
+// pickerModel is the small inline table Pick runs: no alt screen, so the
+// render of the chosen process follows it in the scrollback.
+type pickerModel struct {
+	title  string
+	table  table.Model
+	chosen int
+	done   bool
+}
+
+func newPickerModel(title string, items []PickerItem) pickerModel {
+	columns := []table.Column{
+		{Title: centerHeader("PID", 8), Width: 8},
+		{Title: "User", Width: 12},
+		{Title: "Started", Width: 15},
+		{Title: "Source", Width: 24},
+		{Title: "Command", Width: 50},
+	}
+	rows := make([]table.Row, 0, len(items))
+	for _, it := range items {
+		pid := ""
+		if it.PID > 0 {
+			pid = fmt.Sprintf("%8d", it.PID)
+		}
+		started := it.Started.Format("Jan 02 15:04:05")
+		if it.Started.IsZero() {
+			started = ""
+		}
+		rows = append(rows, table.Row{
+			pid,
+			truncate(output.SanitizeTerminalLine(it.User), 12),
+			started,
+			truncateMiddle(output.SanitizeTerminalLine(it.Source), 24),
+			truncateMiddle(output.SanitizeTerminalLine(it.Cmdline), 50),
+		})
+	}
+
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithRows(rows),
+		table.WithFocused(true),
+		table.WithHeight(min(len(rows), pickerMaxRows)+1),
+	)
+	s := cachedTableStyles
+	s.Header = tableHeaderStyle.BorderForeground(colorBorderDim)
+	t.SetStyles(s)
+
+	return pickerModel{title: title, table: t, chosen: -1}
+}
+
+func (m pickerModel) Init() tea.Cmd { return nil }
+
+func (m pickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "enter":
+			if len(m.table.Rows()) > 0 {
+				m.chosen = m.table.Cursor()
+			}
+			m.done = true
+			return m, tea.Quit
+		case "q", "esc", "ctrl+c":
+			m.chosen = -1
+			m.done = true
+			return m, tea.Quit
+		}
+	}
+	var cmd tea.Cmd
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+func (m pickerModel) View() string {
+	// Once a choice is made the picker leaves nothing behind.
+	if m.done {
+		return ""
+	}
+	help := lipgloss.NewStyle().Foreground(colorMuted).Render("↑/↓ move • enter analyze • q cancel")
+	return lipgloss.JoinVertical(lipgloss.Left,
+		promptStyle.Render(m.title),
+		baseStyle.Render(m.table.View()),
+		help,
+	) + "\n"
+}
+
+// Pick asks which of items to analyze, in a table drawn in place on the
+// terminal. It returns the index of the chosen item, or -1 when the user
+// cancels with q, Esc or Ctrl-C. The caller checks that stdin and stdout
+// are a terminal.
+func Pick(ctx context.Context, title string, items []PickerItem) (int, error) {
+	p := tea.NewProgram(newPickerModel(title, items), tea.WithContext(ctx))
+	final, err := p.Run()
+	if err != nil {
+		if ctx.Err() != nil {
+			return -1, ctx.Err()
+		}
+		return -1, err
+	}
+	return final.(pickerModel).chosen, nil
+}
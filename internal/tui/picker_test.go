@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func pickerStep(t *testing.T, m pickerModel, msg tea.Msg) (pickerModel, tea.Cmd) {
+	t.Helper()
+	nm, cmd := m.Update(msg)
+	pm, ok := nm.(pickerModel)
+	if !ok {
+		t.Fatalf("Update returned %T, want pickerModel", nm)
+	}
+	return pm, cmd
+}
+
+func pickerItems() []PickerItem {
+	return []PickerItem{
+		{PID: 310, User: "postgres", Started: time.Date(2026, 3, 2, 9, 15, 0, 0, time.UTC), Source: "postgresql.service (systemd)", Cmdline: "/usr/lib/postgresql/16/bin/postgres -D /var/lib/postgresql"},
+		{PID: 4211, User: "alice", Source: "bash (shell)", Cmdline: "postgres --single"},
+	}
+}
+
+func TestPickerView(t *testing.T) {
+	m := newPickerModel(`2 processes match "postgres"`, pickerItems())
+	view := m.View()
+	for _, want := range []string{`2 processes match "postgres"`, "PID", "Source", "310", "postgres", "Mar 02 09:15:00", "bash (shell)", "enter analyze"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("picker view missing %q:\n%s", want, view)
+		}
+	}
+
+	m, _ = pickerStep(t, m, keyRunes("q"))
+	if v := m.View(); v != "" {
+		t.Errorf("a finished picker should leave nothing behind, got:\n%s", v)
+	}
+}
+
+func TestPickerKeys(t *testing.T) {
+	m := newPickerModel("pick", pickerItems())
+	m, _ = pickerStep(t, m, tea.KeyMsg{Type: tea.KeyDown})
+	m, cmd := pickerStep(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.chosen != 1 || !m.done {
+		t.Errorf("down then enter: chosen = %d, done = %v; want 1, true", m.chosen, m.done)
+	}
+	if cmd == nil {
+		t.Error("enter should return a quit command")
+	}
+
+	for _, key := range []tea.KeyMsg{{Type: tea.KeyCtrlC}, keyRunes("q"), {Type: tea.KeyEsc}} {
+		m, cmd := pickerStep(t, newPickerModel("pick", pickerItems()), key)
+		if m.chosen != -1 || !m.done {
+			t.Errorf("%s: chosen = %d, done = %v; want a cancel", key, m.chosen, m.done)
+		}
+		if cmd == nil {
+			t.Errorf("%s should return a quit command", key)
+		}
+	}
+}
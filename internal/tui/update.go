@@ -1346,7 +1346,7 @@ func (m MainModel) handleDetailKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, m.refreshProcesses()
 	case "a", "A":
-		if actionsSupported && m.selectedDetail != nil {
+		if actionsAvailable() && m.selectedDetail != nil {
 			m.actionMenuOpen = true
 		}
 		return m, nil
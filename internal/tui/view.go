@@ -422,7 +422,7 @@ func (m MainModel) viewProcessDetail(outerStyle lipgloss.Style) string {
 	case m.statusMsg != "":
 		helpText = errorStyle.Render(m.statusMsg)
 	default:
-		if actionsSupported {
+		if actionsAvailable() {
 			helpText = "a: Actions | Esc/q: Back | Tab: Focus | Up/Down: Scroll"
 		} else {
 			helpText = "Esc/q: Back | Tab: Focus | Up/Down: Scroll"
@@ -0,0 +1,21 @@
+package model
+
+// SocketActivation is the systemd socket unit listening on a port in place
+// of the service it starts on demand.
+type SocketActivation struct {
+	// Socket is the socket unit: "cups.socket".
+	Socket string
+	// Listen holds what the socket listens on, as systemd shows it:
+	// "0.0.0.0:631", "[::]:22", "/run/cups/cups.sock".
+	Listen []string
+	// Accept is the socket's Accept= mode: systemd accepts each connection
+	// and starts one service instance for it.
+	Accept bool
+	// Service is the service the socket triggers: "cups.service", or the
+	// template "sshd@.service" when Accept is set.
+	Service string
+	// Running reports whether the service, or an instance of it, is up.
+	Running bool
+	// MainPIDs are the main processes of the running service or instances.
+	MainPIDs []int `json:",omitempty"`
+}
@@ -0,0 +1,52 @@
+package model
+
+// CgroupContext holds the cgroup v2 a process runs in: its limits, what it
+// uses of them, and how often it ran into them (Linux).
+type CgroupContext struct {
+	// Path is the cgroup below the unified hierarchy, as
+	// "/system.slice/nginx.service".
+	Path string
+
+	// Memory in bytes, from memory.current and memory.max. MemoryMax is 0
+	// when the cgroup has no limit.
+	MemoryCurrent uint64
+	MemoryMax     uint64 `json:",omitempty"`
+	// From memory.events: how many times usage reached the limit (oom), and
+	// how many processes the OOM killer killed for it (oom_kill).
+	OOMEvents uint64 `json:",omitempty"`
+	OOMKills  uint64 `json:",omitempty"`
+
+	// CPU bandwidth from cpu.max: the cgroup may run CPUQuotaUsec out of
+	// every CPUPeriodUsec microseconds. CPUQuotaUsec is 0 when unlimited.
+	CPUQuotaUsec  uint64 `json:",omitempty"`
+	CPUPeriodUsec uint64 `json:",omitempty"`
+	// Throttling from cpu.stat: enforcement periods elapsed, periods in
+	// which the cgroup used up its quota, and the time it spent throttled.
+	CPUPeriods       uint64 `json:",omitempty"`
+	CPUThrottled     uint64 `json:",omitempty"`
+	CPUThrottledUsec uint64 `json:",omitempty"`
+
+	// Tasks, from pids.current and pids.max. PIDsMax is 0 when unlimited.
+	PIDsCurrent uint64 `json:",omitempty"`
+	PIDsMax     uint64 `json:",omitempty"`
+
+	// Pressure is the pressure stall information of each resource ("cpu",
+	// "memory", "io") from its .pressure file.
+	Pressure map[string]Pressure `json:",omitempty"`
+}
+
+// Pressure is how much of the time some (or all) of a cgroup's runnable tasks
+// were stalled waiting for a resource.
+type Pressure struct {
+	Some PressureStall
+	Full PressureStall
+}
+
+// PressureStall is the share of time stalled, in percent, averaged over the
+// last 10, 60 and 300 seconds, and the total stall time in microseconds.
+type PressureStall struct {
+	Avg10     float64
+	Avg60     float64
+	Avg300    float64
+	TotalUsec uint64
+}
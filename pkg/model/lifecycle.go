@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// UnitLifecycle is what systemd recorded about a unit's recent starts and
+// exits: why it restarted, and when it last came up.
+type UnitLifecycle struct {
+	// Result is the unit's result: "success", or how it last failed, such
+	// as "exit-code", "signal", "core-dump", "oom-kill" or "timeout".
+	Result string `json:",omitempty"`
+	// ActiveSince is when the unit last became active, StartedAt when it
+	// last began starting (ActiveEnterTimestamp, InactiveExitTimestamp).
+	ActiveSince time.Time
+	StartedAt   time.Time
+	// LastExit is how the unit's main process last exited, when the journal
+	// or systemd recorded it.
+	LastExit *UnitExit `json:",omitempty"`
+	// Journal holds the unit's last journal entries, oldest first.
+	Journal []JournalEntry `json:",omitempty"`
+}
+
+// UnitExit is one exit of a unit's main process.
+type UnitExit struct {
+	Time time.Time
+	// Code is "exited", "killed" or "dumped". Status is the exit status of
+	// an exited process, and the signal that ended a killed or dumped one.
+	Code   string
+	Status int
+	// Signal names Status for a killed or dumped process: "SIGKILL".
+	Signal string `json:",omitempty"`
+	// Result is the unit result systemd gave the exit, such as "oom-kill",
+	// when it recorded one.
+	Result string `json:",omitempty"`
+}
+
+// JournalEntry is one journal message about a unit.
+type JournalEntry struct {
+	Time     time.Time
+	Priority int
+	PID      int `json:",omitempty"`
+	Message  string
+}
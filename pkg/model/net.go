@@ -6,4 +6,7 @@ type OpenPort struct {
 	Address  string
 	Protocol string
 	State    string
+	// Netns is the socket's network namespace when it is not witr's own
+	// (Linux).
+	Netns string `json:",omitempty"`
 }
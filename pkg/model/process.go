@@ -44,6 +44,13 @@ type Process struct {
 	// Linux capabilities (e.g., CAP_NET_BIND_SERVICE, CAP_SYS_ADMIN)
 	Capabilities []string `json:",omitempty"`
 
+	// Linux namespaces: the inode of each one the process is in, by kind
+	// ("mnt", "net", "pid", ...), and its PID in every PID namespace from the
+	// one witr reads /proc in down to its own. More than one PID means the
+	// process is in a nested PID namespace, as in a container.
+	Namespaces    map[string]string `json:",omitempty"`
+	NamespacePIDs []int             `json:",omitempty"`
+
 	// Extended information for verbose output
 	Memory      MemoryInfo `json:",omitempty"`
 	IO          IOStats    `json:",omitempty"`
@@ -0,0 +1,115 @@
+package model
+
+import "time"
+
+// SchemaVersion is the version of the Report layout that `witr --json`
+// writes and `witr schema` describes. It is bumped when a field is removed,
+// renamed or changes type; adding a field does not bump it. Version 2 turned
+// Result.Warnings from strings into Warning records.
+const SchemaVersion = 2
+
+// Report is the document `witr --json` writes: a result for every target
+// that could be analyzed, an error for every target that could not, and
+// where and when the analysis ran. With --short, --tree, --warnings or
+// --env, each analyzed process is in that view's compact shape, in
+// ShortViews, TreeViews, WarningViews or EnvViews, instead of in Results.
+type Report struct {
+	SchemaVersion int
+	WitrVersion   string
+	Hostname      string
+	Kernel        string
+	Timestamp     time.Time
+	Results       []Result
+	Errors        []TargetError
+	ShortViews    []ShortView    `json:",omitempty"`
+	TreeViews     []TreeView     `json:",omitempty"`
+	WarningViews  []WarningsView `json:",omitempty"`
+	EnvViews      []EnvView      `json:",omitempty"`
+	// Summaries groups the matches of each target analyzed with --all by
+	// the source that started them.
+	Summaries []MatchSummary `json:",omitempty"`
+	// PortTables holds what each --port range target found bound.
+	PortTables []PortTable `json:",omitempty"`
+}
+
+// ProcessRef names a process in the compact views.
+type ProcessRef struct {
+	PID     int
+	Command string
+}
+
+// ShortView is a process in the --short view: its ancestry, oldest first.
+type ShortView struct {
+	Target   Target
+	Ancestry []ProcessRef
+}
+
+// TreeView is a process in the --tree view: its ancestry and children.
+type TreeView struct {
+	Target   Target
+	Ancestry []ProcessRef
+	Children []ProcessRef `json:",omitempty"`
+}
+
+// WarningsView is a process in the --warnings view.
+type WarningsView struct {
+	Target   Target
+	PID      int
+	Process  string
+	Command  string
+	Warnings []Warning
+	// Suppressed is only filled in with --show-suppressed.
+	Suppressed []Warning `json:",omitempty"`
+}
+
+// EnvView is a process in the --env view.
+type EnvView struct {
+	Target  Target
+	PID     int
+	Process string
+	Command string
+	Env     []string
+}
+
+// TargetError is a target that could not be analyzed.
+type TargetError struct {
+	Target Target
+	Error  string
+	// PIDs lists the matching processes when the target was ambiguous.
+	PIDs []int `json:",omitempty"`
+}
+
+// MatchSummary is the processes one target matched, grouped by source.
+type MatchSummary struct {
+	Target Target
+	Groups []MatchGroup
+}
+
+// MatchGroup is the matched processes that share a source: the same
+// systemd unit, cron, SSH sessions, and so on.
+type MatchGroup struct {
+	SourceType SourceType
+	SourceName string `json:",omitempty"`
+	PIDs       []int
+}
+
+// PortTable is every port in the range of a --port target that a process is
+// listening on or bound to.
+type PortTable struct {
+	Target Target
+	Ports  []BoundPort
+}
+
+// BoundPort is one port of a PortTable, the process holding it and the
+// source that started that process.
+type BoundPort struct {
+	Port       int
+	Protocol   string
+	Address    string
+	PID        int
+	Process    string
+	SourceType SourceType
+	SourceName string `json:",omitempty"`
+	// Netns is the network namespace of the port when it is not witr's own.
+	Netns string `json:",omitempty"`
+}
@@ -8,14 +8,39 @@ type Result struct {
 	Ancestry       []Process
 	Children       []Process `json:",omitempty"`
 	Source         Source
-	Warnings       []string
+	Warnings       []Warning
+	// Suppressed are warnings a suppress rule or the baseline matched. They
+	// are kept out of Warnings and do not affect the exit code.
+	Suppressed []Warning `json:",omitempty"`
 
 	// SocketInfo holds socket state details (for port queries)
 	SocketInfo *SocketInfo
 
+	// SocketActivation is the systemd socket unit listening on the port of
+	// a port query, when there is one (Linux).
+	SocketActivation *SocketActivation `json:",omitempty"`
+
+	// Clients are the local processes connected to the process's listening
+	// TCP and unix sockets (Linux).
+	Clients []Client `json:",omitempty"`
+
 	// ResourceContext holds resource usage context (macOS)
 	ResourceContext *ResourceContext
 
 	// FileContext holds file descriptor and lock info
 	FileContext *FileContext
+
+	// CgroupContext holds the limits, usage and pressure of the process's
+	// cgroup (Linux, cgroup v2)
+	CgroupContext *CgroupContext `json:",omitempty"`
+
+	// Container is the runtime's view of a container target. When the
+	// container's processes are not visible from this host (Docker Desktop,
+	// a VM), it is all that is known and Process and Ancestry are empty.
+	Container *ContainerMatch `json:",omitempty"`
+
+	// Incomplete names the enrichment steps that were skipped or cut short
+	// because the context of the analysis was done ("source details",
+	// "child processes"). The rest of the result is still valid.
+	Incomplete []string `json:",omitempty"`
 }
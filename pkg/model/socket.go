@@ -6,6 +6,37 @@ type Socket struct {
 	Address  string // 0.0.0.0, 127.0.0.1, ::
 	State    string
 	Protocol string
+
+	// The other end of a connected TCP or UDP socket, the bytes queued to
+	// send (TxQueue) and to read (RxQueue; for a listener, connections
+	// waiting to be accepted), and the UID that owns the socket. Linux only;
+	// UID is nil where it is not known.
+	RemoteAddress string `json:",omitempty"`
+	RemotePort    int    `json:",omitempty"`
+	TxQueue       uint64 `json:",omitempty"`
+	RxQueue       uint64 `json:",omitempty"`
+	UID           *int   `json:",omitempty"`
+
+	// Path is the filesystem path a unix socket is bound to ("@name" for
+	// the abstract namespace), and Type its kind: stream, dgram or
+	// seqpacket. Both are empty for TCP and UDP sockets.
+	Path string `json:",omitempty"`
+	Type string `json:",omitempty"`
+
+	// Netns is the inode of the network namespace the socket belongs to,
+	// when it is not witr's own: a container's, a pod's or one made with
+	// "ip netns". Linux only.
+	Netns string `json:",omitempty"`
+}
+
+// Client is a process on this host connected to a socket the process of a
+// result accepted connections on.
+type Client struct {
+	PID int
+	// Ancestry is the client's own chain, from init to the client.
+	Ancestry []Process
+	// Sockets are the server's ends of the client's connections.
+	Sockets []Socket
 }
 
 // SocketInfo holds information about a socket's state
@@ -22,4 +22,37 @@ type Source struct {
 	Description string
 	UnitFile    string
 	Details     map[string]string
+	// UserManager is the user manager (systemd --user) managing the unit,
+	// "user@1000.service", when the unit is not the system manager's.
+	UserManager string `json:",omitempty"`
+	// Unit is the unit's configuration and what activated it (systemd).
+	Unit *SystemdUnit `json:",omitempty"`
+	// Lifecycle is the unit's recent starts and exits (systemd).
+	Lifecycle *UnitLifecycle `json:",omitempty"`
+}
+
+// SystemdUnit is how a systemd unit is configured and what pulled it in.
+type SystemdUnit struct {
+	// ExecStart holds the command line of each ExecStart= entry.
+	ExecStart []string `json:",omitempty"`
+	User      string   `json:",omitempty"`
+	Group     string   `json:",omitempty"`
+	// Restart is the Restart= policy: "no", "on-failure", "always", ...
+	Restart string `json:",omitempty"`
+	// DropIns are the drop-in files amending the unit file.
+	DropIns []string `json:",omitempty"`
+	// FileState is the unit file's state: "enabled", "static",
+	// "generated", "transient", ...
+	FileState string `json:",omitempty"`
+	// ActivatedBy is the chain of units that pulled this one in, from the
+	// one that started it all (multi-user.target, or the .socket, .path or
+	// .timer that triggered it) down to the unit's direct activator.
+	ActivatedBy []Activation `json:",omitempty"`
+}
+
+// Activation is one link of a unit's activation chain: Unit pulled in the
+// next unit down through Relation, "wants", "requires" or "triggers".
+type Activation struct {
+	Unit     string
+	Relation string
 }
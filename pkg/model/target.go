@@ -3,11 +3,14 @@ package model
 type TargetType string
 
 const (
-	TargetName      TargetType = "name"
-	TargetPID       TargetType = "pid"
-	TargetPort      TargetType = "port"
-	TargetFile      TargetType = "file"
-	TargetContainer TargetType = "container"
+	TargetName       TargetType = "name"
+	TargetPID        TargetType = "pid"
+	TargetPort       TargetType = "port"
+	TargetFile       TargetType = "file"
+	TargetContainer  TargetType = "container"
+	TargetRegex      TargetType = "regex"
+	TargetSocket     TargetType = "socket"
+	TargetConnection TargetType = "connection"
 )
 
 type Target struct {
@@ -0,0 +1,119 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// WarningCode identifies a kind of warning. Codes are stable across releases;
+// the wording of Message is not.
+type WarningCode string
+
+const (
+	WarnRestarts        WarningCode = "W_RESTARTS"
+	WarnZombie          WarningCode = "W_ZOMBIE"
+	WarnStopped         WarningCode = "W_STOPPED"
+	WarnHighCPU         WarningCode = "W_HIGH_CPU"
+	WarnHighMemory      WarningCode = "W_HIGH_MEM"
+	WarnPublicBind      WarningCode = "W_PUBLIC_BIND"
+	WarnRoot            WarningCode = "W_ROOT"
+	WarnDangerousCaps   WarningCode = "W_DANGEROUS_CAPS"
+	WarnNoSupervisor    WarningCode = "W_NO_SUPERVISOR"
+	WarnLongRunning     WarningCode = "W_LONG_RUNNING"
+	WarnSuspiciousCwd   WarningCode = "W_SUSPICIOUS_CWD"
+	WarnNoHealthcheck   WarningCode = "W_NO_HEALTHCHECK"
+	WarnServiceMismatch WarningCode = "W_SERVICE_MISMATCH"
+	WarnDeletedExe      WarningCode = "W_DELETED_EXE"
+	WarnLDPreload       WarningCode = "W_LD_PRELOAD"
+	WarnDYLDVars        WarningCode = "W_DYLD_VARS"
+	WarnSuspiciousEnv   WarningCode = "W_SUSPICIOUS_ENV"
+	WarnCgroupMemory    WarningCode = "W_CGROUP_MEMORY"
+	WarnCgroupPIDs      WarningCode = "W_CGROUP_PIDS"
+	WarnOOMKills        WarningCode = "W_OOM_KILLS"
+)
+
+// Severity ranks how much a warning matters, from SeverityInfo to
+// SeverityHigh.
+type Severity string
+
+const (
+	SeverityInfo   Severity = "info"
+	SeverityLow    Severity = "low"
+	SeverityMedium Severity = "medium"
+	SeverityHigh   Severity = "high"
+)
+
+var severityRank = map[Severity]int{SeverityInfo: 1, SeverityLow: 2, SeverityMedium: 3, SeverityHigh: 4}
+
+// ParseSeverity accepts a severity name in any case.
+func ParseSeverity(s string) (Severity, error) {
+	sev := Severity(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := severityRank[sev]; !ok {
+		return "", fmt.Errorf("unknown severity %q (want info, low, medium or high)", s)
+	}
+	return sev, nil
+}
+
+// AtLeast reports whether s is as severe as min or more. An unknown severity
+// ranks below info.
+func (s Severity) AtLeast(min Severity) bool {
+	return severityRank[s] >= severityRank[min]
+}
+
+// Warning is one finding about the analyzed process. Evidence holds the facts
+// that triggered it, such as the capabilities or the working directory.
+type Warning struct {
+	Code     WarningCode
+	Severity Severity
+	Message  string
+	Evidence map[string]string `json:",omitempty"`
+	// SuppressedBy says what hid a suppressed warning: "baseline", or
+	// "config" and the rule's reason.
+	SuppressedBy string `json:",omitempty"`
+}
+
+func (w Warning) String() string { return w.Message }
+
+// subjectEvidence are the Evidence keys that say what a warning is about,
+// rather than how far past its threshold it is.
+var subjectEvidence = []string{
+	"addresses", "capabilities", "cgroup", "command", "container", "dir",
+	"exe", "service", "user", "variables",
+}
+
+// Subject identifies the finding a warning reports: its code and the evidence
+// naming what it is about, such as the public addresses or the variables,
+// but not the readings that move between runs (a cgroup at 87% then 88% of
+// its limit is one finding). A warning without a code, saved by an older
+// witr, is identified by its message.
+func (w Warning) Subject() string {
+	if w.Code == "" {
+		return w.Message
+	}
+	return strings.Join(append([]string{string(w.Code)}, w.SubjectEvidence()...), " ")
+}
+
+// SubjectEvidence returns the evidence Subject identifies a warning by, as
+// key=value pairs in a fixed order.
+func (w Warning) SubjectEvidence() []string {
+	var out []string
+	for _, k := range subjectEvidence {
+		if v, ok := w.Evidence[k]; ok {
+			out = append(out, k+"="+v)
+		}
+	}
+	return out
+}
+
+// UnmarshalJSON also reads a warning saved by an older witr, which wrote the
+// message alone; its code and severity are left empty.
+func (w *Warning) UnmarshalJSON(data []byte) error {
+	var msg string
+	if json.Unmarshal(data, &msg) == nil {
+		*w = Warning{Message: msg}
+		return nil
+	}
+	type plain Warning
+	return json.Unmarshal(data, (*plain)(w))
+}
@@ -0,0 +1,38 @@
+// Package witr is the supported Go API for embedding witr's analysis in other
+// tools. It answers the same question as the witr command — why is this
+// process running? — and returns the same model.Result the CLI renders, so
+// callers no longer have to shell out to the binary and parse its JSON.
+//
+// A typical caller creates one Client and reuses it:
+//
+//	c := witr.New(witr.Options{Verbose: true})
+//	res, err := c.Analyze(ctx, model.Target{Type: model.TargetPort, Value: "5432"})
+//	if err != nil {
+//		var multi *witr.MultipleMatchError
+//		if errors.As(err, &multi) {
+//			// pick one of multi.PIDs and call c.AnalyzePID
+//		}
+//		return err
+//	}
+//	witr.Render(os.Stdout, res, witr.RenderOptions{Format: witr.FormatStandard})
+//
+// # Stability
+//
+// This package and pkg/model follow semantic versioning from v1 onward:
+//
+//   - Exported identifiers in pkg/witr are not removed or changed in an
+//     incompatible way within a major version.
+//   - New fields may be added to model types, new Format values and Options
+//     fields may be added, and new methods may be added to Client. Code that
+//     uses keyed struct literals and tolerates unknown enum values keeps
+//     compiling and working.
+//   - The content of human-readable output (FormatStandard, FormatShort,
+//     FormatTree, FormatWarnings) and of warning messages is not covered:
+//     wording and layout change between releases. Use the model fields,
+//     warning codes, or FormatJSON when you need to parse results.
+//   - Packages under internal/ carry no compatibility promise and cannot be
+//     imported from outside this module.
+//
+// Detection is best effort and platform dependent, exactly as for the CLI:
+// fields that a platform cannot provide are left at their zero value.
+package witr
@@ -0,0 +1,65 @@
+package witr
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Format selects how Render presents a result.
+type Format string
+
+const (
+	// FormatStandard is the default multi-section report.
+	FormatStandard Format = "standard"
+	// FormatShort is the single-line ancestry chain (--short).
+	FormatShort Format = "short"
+	// FormatTree is the ancestry and children as a tree (--tree).
+	FormatTree Format = "tree"
+	// FormatWarnings shows only the process and its warnings (--warnings).
+	FormatWarnings Format = "warnings"
+	// FormatEnv shows only the process environment (--env).
+	FormatEnv Format = "env"
+	// FormatJSON is the full model.Result as indented JSON, one entry of the
+	// Results --json writes.
+	FormatJSON Format = "json"
+)
+
+// RenderOptions controls Render.
+type RenderOptions struct {
+	Format Format
+	// Color enables ANSI colors for the human-readable formats.
+	Color bool
+	// Verbose adds the extended sections to FormatStandard.
+	Verbose bool
+}
+
+// Render writes r to w in the requested format, exactly as the witr command
+// would print it. Human-readable formats sanitize every process-controlled
+// string, so output is safe to write to a terminal.
+func Render(w io.Writer, r model.Result, opts RenderOptions) error {
+	switch opts.Format {
+	case FormatStandard, "":
+		output.RenderStandard(w, r, opts.Color, opts.Verbose)
+	case FormatShort:
+		output.RenderShort(w, r, opts.Color)
+	case FormatTree:
+		output.PrintTree(w, r.Ancestry, r.Children, opts.Color)
+	case FormatWarnings:
+		output.RenderWarnings(w, r, opts.Color)
+	case FormatEnv:
+		output.RenderEnvOnly(w, r, opts.Color)
+	case FormatJSON:
+		s, err := output.ToJSON(r)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, s)
+		return err
+	default:
+		return fmt.Errorf("unknown format %q", opts.Format)
+	}
+	return nil
+}
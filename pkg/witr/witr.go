@@ -0,0 +1,225 @@
+package witr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	"github.com/pranshuparmar/witr/internal/pipeline"
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/target"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+var (
+	// ErrSocketOwnerUnknown means a socket is bound to the queried port but no
+	// host-visible process owns it (systemd socket activation, a container
+	// runtime's proxy, or insufficient permissions).
+	ErrSocketOwnerUnknown = target.ErrSocketOwnerUnknown
+
+	// ErrUnsupported means the lookup is unavailable on this platform (e.g.
+	// resolving by file on Windows).
+	ErrUnsupported = target.ErrUnsupported
+
+	// ErrNotFound means a container target matched no container, or a
+	// resolver reported success without any PIDs. Resolvers that can say more
+	// ("no process listening on port 8080") return their own error instead.
+	ErrNotFound = errors.New("no matching process found")
+
+	// ErrContainerNotHostVisible means a container matched, but its main
+	// process is not visible from this host (a VM-backed runtime, a stopped
+	// container, or a PID namespace witr cannot see into). The match itself is
+	// available from Client.ResolveContainers.
+	ErrContainerNotHostVisible = errors.New("container process is not visible on this host")
+)
+
+// MultipleMatchError is returned by Analyze when a target matches more than
+// one process or container. Callers choose one and call AnalyzePID.
+type MultipleMatchError struct {
+	Target model.Target
+	// PIDs holds the matching process IDs for process targets.
+	PIDs []int
+	// Containers holds the matching containers for container targets.
+	Containers []*model.ContainerMatch
+}
+
+func (e *MultipleMatchError) Error() string {
+	n := len(e.PIDs)
+	kind := "processes"
+	if len(e.Containers) > 0 {
+		n = len(e.Containers)
+		kind = "containers"
+	}
+	return fmt.Sprintf("multiple %s matched %s %q (%d results)", kind, e.Target.Type, e.Target.Value, n)
+}
+
+// Options configures a Client. The zero value gives the same analysis as
+// running witr without flags.
+type Options struct {
+	// Exact disables substring matching for name and container targets.
+	Exact bool
+	// Verbose collects extended information: memory, I/O, file descriptors,
+	// child processes, resource and file context.
+	Verbose bool
+	// Tree collects the target's child processes, as for `witr --tree`.
+	Tree bool
+	// Clients collects the local processes connected to the target's
+	// listening sockets. Finding them reads every process's file
+	// descriptors.
+	Clients bool
+}
+
+// Client runs witr analyses. A Client holds no per-call state and is safe for
+// concurrent use.
+type Client struct {
+	opts Options
+}
+
+// New returns a Client configured by opts.
+func New(opts Options) *Client {
+	return &Client{opts: opts}
+}
+
+// Resolve returns the PIDs a process target matches, without analyzing them.
+// Container targets are not accepted here; use ResolveContainers.
+func (c *Client) Resolve(ctx context.Context, t model.Target) ([]int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if t.Type == model.TargetContainer {
+		return nil, fmt.Errorf("container targets resolve to containers, not PIDs: use ResolveContainers")
+	}
+	pids, err := target.Resolve(ctx, t, c.opts.Exact)
+	if err != nil {
+		return nil, err
+	}
+	if len(pids) == 0 {
+		return nil, ErrNotFound
+	}
+	return pids, nil
+}
+
+// ResolveContainers returns every container, across all available runtimes,
+// that matches query by name, image, command or compose project/service.
+func (c *Client) ResolveContainers(ctx context.Context, query string) ([]*model.ContainerMatch, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return procpkg.ResolveContainer(ctx, query, c.opts.Exact), nil
+}
+
+// Analyze resolves t and explains the single process it matches. It returns a
+// *MultipleMatchError when t is ambiguous. When ctx is done before the process
+// is found, Analyze returns ctx's error; after that, it returns what it has,
+// with the steps it skipped in Result.Incomplete.
+func (c *Client) Analyze(ctx context.Context, t model.Target) (model.Result, error) {
+	// Resolving t and analyzing what it matched share one read of the
+	// process table.
+	ctx = procpkg.WithIndex(ctx, procpkg.NewIndex())
+	if t.Type == model.TargetContainer {
+		return c.analyzeContainer(ctx, t)
+	}
+
+	pids, err := c.Resolve(ctx, t)
+	if err != nil {
+		return model.Result{}, err
+	}
+	if len(pids) > 1 {
+		return model.Result{}, &MultipleMatchError{Target: t, PIDs: pids}
+	}
+
+	res, err := c.analyze(ctx, pids[0], t)
+	if err != nil {
+		return model.Result{}, err
+	}
+	if t.Type == model.TargetPort {
+		if spec, err := target.ParsePort(t.Value); err == nil && !spec.IsRange() {
+			pipeline.AnnotatePortTarget(ctx, &res, spec.Low, spec.Proto)
+		}
+	}
+	return res, nil
+}
+
+// AnalyzePID explains why the process with the given PID is running.
+func (c *Client) AnalyzePID(ctx context.Context, pid int) (model.Result, error) {
+	return c.analyze(ctx, pid, model.Target{Type: model.TargetPID, Value: strconv.Itoa(pid)})
+}
+
+func (c *Client) analyze(ctx context.Context, pid int, t model.Target) (model.Result, error) {
+	return pipeline.AnalyzePID(ctx, pipeline.AnalyzeConfig{
+		PID:     pid,
+		Verbose: c.opts.Verbose,
+		Tree:    c.opts.Tree,
+		Clients: c.opts.Clients,
+		Target:  t,
+	})
+}
+
+func (c *Client) analyzeContainer(ctx context.Context, t model.Target) (model.Result, error) {
+	matches, err := c.ResolveContainers(ctx, t.Value)
+	if err != nil {
+		return model.Result{}, err
+	}
+	switch {
+	case len(matches) == 0:
+		return model.Result{}, fmt.Errorf("no container found matching %q: %w", t.Value, ErrNotFound)
+	case len(matches) > 1:
+		return model.Result{}, &MultipleMatchError{Target: t, Containers: matches}
+	}
+
+	match := matches[0]
+	procpkg.EnrichContainer(ctx, match)
+	pid := procpkg.ResolveContainerHostPID(ctx, match.Runtime, match.ID)
+	if pid <= 0 || !procpkg.PIDBelongsToContainer(pid, match.ID) {
+		return model.Result{}, fmt.Errorf("container %s: %w", match.Name, ErrContainerNotHostVisible)
+	}
+
+	res, err := c.analyze(ctx, pid, t)
+	if err != nil {
+		return model.Result{}, err
+	}
+	res.Process.Container = output.FormatContainerLine(match)
+	if len(res.Ancestry) > 0 {
+		res.Ancestry[len(res.Ancestry)-1].Container = res.Process.Container
+	}
+	return res, nil
+}
+
+// ListProcesses returns every running process with the summary columns the
+// interactive process list shows (PID, PPID, command, user, start time, CPU
+// and memory usage, command line).
+func (c *Client) ListProcesses(ctx context.Context) ([]model.Process, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return procpkg.ListProcesses()
+}
+
+// ListPorts returns every socket owned by a visible process, one entry per
+// (PID, socket).
+func (c *Client) ListPorts(ctx context.Context) ([]model.OpenPort, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return procpkg.ListOpenPorts()
+}
+
+// ListContainers returns every container reported by every available
+// container runtime.
+func (c *Client) ListContainers(ctx context.Context) ([]*model.ContainerMatch, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return procpkg.ListAllContainers(ctx), nil
+}
+
+// ListLocks returns the file locks currently held on the system. It returns
+// an empty list on platforms without a system-wide lock table (Windows).
+func (c *Client) ListLocks(ctx context.Context) ([]*model.LockedFile, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return procpkg.ListLockedFiles(), nil
+}
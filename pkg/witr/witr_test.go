@@ -0,0 +1,115 @@
+package witr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// TestAnalyzeSelf drives the public API end to end against the test process,
+// which exists on every platform.
+func TestAnalyzeSelf(t *testing.T) {
+	self := os.Getpid()
+	c := New(Options{})
+
+	res, err := c.Analyze(context.Background(), model.Target{Type: model.TargetPID, Value: strconv.Itoa(self)})
+	if err != nil {
+		t.Fatalf("Analyze(pid %d): %v", self, err)
+	}
+	if res.Process.PID != self {
+		t.Errorf("Process.PID = %d, want %d", res.Process.PID, self)
+	}
+	if res.Target.Type != model.TargetPID {
+		t.Errorf("Target.Type = %q, want %q", res.Target.Type, model.TargetPID)
+	}
+	if len(res.Ancestry) == 0 {
+		t.Error("expected a non-empty ancestry chain")
+	}
+}
+
+func TestAnalyzeCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := New(Options{}).AnalyzePID(ctx, os.Getpid())
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("AnalyzePID with a canceled context = %v, want context.Canceled", err)
+	}
+}
+
+func TestAnalyzeInvalidTarget(t *testing.T) {
+	_, err := New(Options{}).Analyze(context.Background(), model.Target{Type: model.TargetPID, Value: "notanumber"})
+	if err == nil {
+		t.Fatal("expected an error for a non-numeric pid")
+	}
+}
+
+func TestResolveRejectsContainerTargets(t *testing.T) {
+	_, err := New(Options{}).Resolve(context.Background(), model.Target{Type: model.TargetContainer, Value: "redis"})
+	if err == nil {
+		t.Fatal("Resolve should reject container targets")
+	}
+}
+
+func TestMultipleMatchErrorMessage(t *testing.T) {
+	err := &MultipleMatchError{
+		Target: model.Target{Type: model.TargetName, Value: "python"},
+		PIDs:   []int{10, 11, 12},
+	}
+	if got := err.Error(); !strings.Contains(got, "3 results") || !strings.Contains(got, "processes") {
+		t.Errorf("Error() = %q, want process count", got)
+	}
+
+	err = &MultipleMatchError{
+		Target:     model.Target{Type: model.TargetContainer, Value: "redis"},
+		Containers: []*model.ContainerMatch{{Name: "a"}, {Name: "b"}},
+	}
+	if got := err.Error(); !strings.Contains(got, "2 results") || !strings.Contains(got, "containers") {
+		t.Errorf("Error() = %q, want container count", got)
+	}
+}
+
+func TestRenderFormats(t *testing.T) {
+	res := model.Result{
+		Process: model.Process{PID: 42, Command: "nginx", Cmdline: "nginx -g daemon off;"},
+		Ancestry: []model.Process{
+			{PID: 1, Command: "systemd"},
+			{PID: 42, Command: "nginx"},
+		},
+		Source: model.Source{Type: model.SourceSystemd, Name: "nginx.service"},
+	}
+
+	for _, f := range []Format{FormatStandard, FormatShort, FormatTree, FormatWarnings, FormatEnv} {
+		var buf bytes.Buffer
+		if err := Render(&buf, res, RenderOptions{Format: f}); err != nil {
+			t.Errorf("Render(%s): %v", f, err)
+			continue
+		}
+		if !strings.Contains(buf.String(), "42") {
+			t.Errorf("Render(%s) output missing the pid:\n%s", f, buf.String())
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := Render(&buf, res, RenderOptions{Format: FormatJSON}); err != nil {
+		t.Fatalf("Render(json): %v", err)
+	}
+	var decoded model.Result
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Render(json) produced invalid JSON: %v\n%s", err, buf.String())
+	}
+	if decoded.Process.PID != 42 {
+		t.Errorf("decoded Process.PID = %d, want 42", decoded.Process.PID)
+	}
+
+	if err := Render(&buf, res, RenderOptions{Format: "yaml"}); err == nil {
+		t.Error("Render should reject an unknown format")
+	}
+}